@@ -481,7 +481,17 @@ func main() {
 	// managing virtual machines.
 	markReady()
 
-	standalone.HandleStandaloneMode(domainManager)
+	if err := standalone.HandleStandaloneMode(domainManager); err != nil {
+		log.Log.Reason(err).Error("Standalone mode failed, quitting")
+		panic(err)
+	}
+	go standalone.WatchStandaloneLifecycle(domainManager, domainConn, signalStopChan, func() {
+		// tear down the same way as when the pod is deleted
+		select {
+		case c <- syscall.SIGTERM:
+		default:
+		}
+	})
 	domain := waitForDomainUUID(*qemuTimeout, events, signalStopChan, domainManager)
 	if domain != nil {
 		var pidDir string
@@ -775,7 +775,11 @@ func GetVirtualMachineRestoreInformerIndexers() cache.Indexers {
 			if vmr.Spec.Target.APIGroup != nil &&
 				*vmr.Spec.Target.APIGroup == core.GroupName &&
 				vmr.Spec.Target.Kind == "VirtualMachine" {
-				return []string{fmt.Sprintf("%s/%s", vmr.Namespace, vmr.Spec.Target.Name)}, nil
+				namespace := vmr.Namespace
+				if vmr.Spec.TargetNamespace != nil && *vmr.Spec.TargetNamespace != "" {
+					namespace = *vmr.Spec.TargetNamespace
+				}
+				return []string{fmt.Sprintf("%s/%s", namespace, vmr.Spec.Target.Name)}, nil
 			}
 
 			return nil, nil
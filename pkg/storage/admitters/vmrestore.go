@@ -23,12 +23,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/cache"
 
@@ -38,6 +44,7 @@ import (
 	"kubevirt.io/client-go/kubecli"
 
 	backendstorage "kubevirt.io/kubevirt/pkg/storage/backend-storage"
+	storageutils "kubevirt.io/kubevirt/pkg/storage/utils"
 	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
@@ -77,6 +84,7 @@ func (admitter *VMRestoreAdmitter) Admit(ctx context.Context, ar *admissionv1.Ad
 	}
 
 	var causes []metav1.StatusCause
+	var warnings []string
 
 	switch ar.Request.Operation {
 	case admissionv1.Create:
@@ -100,7 +108,22 @@ func (admitter *VMRestoreAdmitter) Admit(ctx context.Context, ar *admissionv1.Ad
 						return webhookutils.ToAdmissionResponseError(err)
 					}
 
-					newCauses := admitter.validateVolumeOverrides(ctx, vmRestore)
+					var newCauses []metav1.StatusCause
+					newCauses, err = admitter.validateTargetNamespace(ctx, ar.Request.UserInfo, vmRestore)
+					if err != nil {
+						return webhookutils.ToAdmissionResponseError(err)
+					}
+					causes = append(causes, newCauses...)
+
+					newCauses = admitter.validateVolumeOverrides(ctx, vmRestore)
+					if newCauses != nil {
+						causes = append(causes, newCauses...)
+					}
+
+					newCauses, err = admitter.validateVolumeSelection(ctx, vmRestore)
+					if err != nil {
+						return webhookutils.ToAdmissionResponseError(err)
+					}
 					if newCauses != nil {
 						causes = append(causes, newCauses...)
 					}
@@ -114,6 +137,24 @@ func (admitter *VMRestoreAdmitter) Admit(ctx context.Context, ar *admissionv1.Ad
 					if newCauses != nil {
 						causes = append(causes, newCauses...)
 					}
+
+					newCauses = admitter.validateMetadataRestorePolicy(vmRestore)
+					if newCauses != nil {
+						causes = append(causes, newCauses...)
+					}
+
+					newCauses = admitter.validatePlacementPolicy(vmRestore)
+					if newCauses != nil {
+						causes = append(causes, newCauses...)
+					}
+
+					newCauses, warnings, err = admitter.validateResourceRemapping(ctx, vmRestore)
+					if err != nil {
+						return webhookutils.ToAdmissionResponseError(err)
+					}
+					if newCauses != nil {
+						causes = append(causes, newCauses...)
+					}
 				default:
 					causes = []metav1.StatusCause{
 						{
@@ -142,6 +183,7 @@ func (admitter *VMRestoreAdmitter) Admit(ctx context.Context, ar *admissionv1.Ad
 		for _, obj := range objects {
 			r := obj.(*snapshotv1.VirtualMachineRestore)
 			if equality.Semantic.DeepEqual(r.Spec.Target, vmRestore.Spec.Target) &&
+				restoreTargetNamespace(r) == restoreTargetNamespace(vmRestore) &&
 				(r.Status == nil || r.Status.Complete == nil || !*r.Status.Complete) {
 				cause := metav1.StatusCause{
 					Type:    metav1.CauseTypeFieldValueInvalid,
@@ -177,33 +219,89 @@ func (admitter *VMRestoreAdmitter) Admit(ctx context.Context, ar *admissionv1.Ad
 	}
 
 	reviewResponse := admissionv1.AdmissionResponse{
-		Allowed: true,
+		Allowed:  true,
+		Warnings: warnings,
 	}
 	return &reviewResponse
 }
 
+// validateSnapshotReference makes sure the restore references either a
+// snapshot or a snapshot content, not both
+func validateSnapshotReference(field *k8sfield.Path, vmRestore *snapshotv1.VirtualMachineRestore) []metav1.StatusCause {
+	hasSnapshotName := vmRestore.Spec.VirtualMachineSnapshotName != ""
+	hasContentName := vmRestore.Spec.VirtualMachineSnapshotContentName != nil && *vmRestore.Spec.VirtualMachineSnapshotContentName != ""
+
+	switch {
+	case !hasSnapshotName && !hasContentName:
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: "one of virtualMachineSnapshotName or virtualMachineSnapshotContentName must be set",
+			Field:   field.Child("virtualMachineSnapshotName").String(),
+		}}
+	case hasSnapshotName && hasContentName:
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "virtualMachineSnapshotName and virtualMachineSnapshotContentName are mutually exclusive",
+			Field:   field.Child("virtualMachineSnapshotContentName").String(),
+		}}
+	}
+
+	return nil
+}
+
 func (admitter *VMRestoreAdmitter) validateTargetVM(ctx context.Context, field *k8sfield.Path, vmRestore *snapshotv1.VirtualMachineRestore) (causes []metav1.StatusCause, err error) {
 	targetName := vmRestore.Spec.Target.Name
 	namespace := vmRestore.Namespace
 
+	if causes = validateSnapshotReference(field, vmRestore); len(causes) > 0 {
+		return causes, nil
+	}
+
 	causes = admitter.validatePatches(vmRestore.Spec.Patches, field.Child("patches"))
 
-	vmSnapshot, err := admitter.Client.VirtualMachineSnapshot(namespace).Get(ctx, vmRestore.Spec.VirtualMachineSnapshotName, metav1.GetOptions{})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil, nil
+	var sourceUID *types.UID
+	var contentName *string
+	if vmRestore.Spec.VirtualMachineSnapshotContentName != nil {
+		contentName = vmRestore.Spec.VirtualMachineSnapshotContentName
+		vmSnapshotContent, err := admitter.Client.VirtualMachineSnapshotContent(namespace).Get(ctx, *contentName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
 		}
-		return nil, err
+
+		if vmSnapshotContent.Status == nil || vmSnapshotContent.Status.ReadyToUse == nil || !*vmSnapshotContent.Status.ReadyToUse {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("VirtualMachineSnapshotContent %q is not ready to use", *contentName),
+				Field:   field.Child("virtualMachineSnapshotContentName").String(),
+			})
+			return causes, nil
+		}
+
+		if vmSnapshotContent.Spec.Source.VirtualMachine != nil {
+			sourceUID = &vmSnapshotContent.Spec.Source.VirtualMachine.UID
+		}
+	} else {
+		vmSnapshot, err := admitter.Client.VirtualMachineSnapshot(namespace).Get(ctx, vmRestore.Spec.VirtualMachineSnapshotName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		sourceUID = vmSnapshot.Status.SourceUID
+		contentName = vmSnapshot.Status.VirtualMachineSnapshotContentName
 	}
 
-	target, err := admitter.Client.VirtualMachine(namespace).Get(ctx, targetName, metav1.GetOptions{})
+	target, err := admitter.Client.VirtualMachine(restoreTargetNamespace(vmRestore)).Get(ctx, targetName, metav1.GetOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, err
 	}
 
-	sourceTargetVmsAreDifferent := errors.IsNotFound(err) || (vmSnapshot.Status.SourceUID != nil && target.UID != *vmSnapshot.Status.SourceUID)
+	sourceTargetVmsAreDifferent := errors.IsNotFound(err) || (sourceUID != nil && target.UID != *sourceUID)
 	if sourceTargetVmsAreDifferent {
-		contentName := vmSnapshot.Status.VirtualMachineSnapshotContentName
 		if contentName == nil {
 			return nil, fmt.Errorf("snapshot content name is nil in vmSnapshot status")
 		}
@@ -230,6 +328,93 @@ func (admitter *VMRestoreAdmitter) validateTargetVM(ctx context.Context, field *
 	return causes, nil
 }
 
+// validateTargetNamespace checks that a restore into another namespace
+// replaces no volumes in place, and that the requesting user may create the
+// restored VM and its volumes in that namespace
+func (admitter *VMRestoreAdmitter) validateTargetNamespace(ctx context.Context, userInfo authenticationv1.UserInfo, vmRestore *snapshotv1.VirtualMachineRestore) (causes []metav1.StatusCause, err error) {
+	targetNamespace := restoreTargetNamespace(vmRestore)
+	if targetNamespace == vmRestore.Namespace {
+		return nil, nil
+	}
+
+	targetNamespaceField := k8sfield.NewPath("spec").Child("targetNamespace")
+
+	if vmRestore.Spec.VolumeRestorePolicy != nil && *vmRestore.Spec.VolumeRestorePolicy == snapshotv1.VolumeRestorePolicyInPlace {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("targetNamespace can't be combined with volume restore policy \"%s\"", snapshotv1.VolumeRestorePolicyInPlace),
+			Field:   targetNamespaceField.String(),
+		})
+	}
+
+	_, err = admitter.Client.CoreV1().Namespaces().Get(ctx, targetNamespace, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotFound,
+			Message: fmt.Sprintf("namespace %q does not exist", targetNamespace),
+			Field:   targetNamespaceField.String(),
+		})
+		return causes, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range []authv1.ResourceAttributes{
+		{Group: core.GroupName, Resource: "virtualmachines"},
+		{Group: "", Resource: "persistentvolumeclaims"},
+	} {
+		resource.Namespace = targetNamespace
+		resource.Verb = "create"
+		allowed, err := userAllowed(ctx, admitter.Client, userInfo, resource)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("user %q is not allowed to create %s in namespace %q", userInfo.Username, resource.Resource, targetNamespace),
+				Field:   targetNamespaceField.String(),
+			})
+		}
+	}
+
+	return causes, nil
+}
+
+// userAllowed checks with a SubjectAccessReview whether the user of an
+// admission request may access the resource
+func userAllowed(ctx context.Context, client kubecli.KubevirtClient, userInfo authenticationv1.UserInfo, resource authv1.ResourceAttributes) (bool, error) {
+	extra := make(map[string]authv1.ExtraValue, len(userInfo.Extra))
+	for k, v := range userInfo.Extra {
+		extra[k] = authv1.ExtraValue(v)
+	}
+
+	sar := &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:               userInfo.Username,
+			Groups:             userInfo.Groups,
+			UID:                userInfo.UID,
+			Extra:              extra,
+			ResourceAttributes: &resource,
+		},
+	}
+
+	sar, err := client.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	return sar.Status.Allowed, nil
+}
+
+func restoreTargetNamespace(vmRestore *snapshotv1.VirtualMachineRestore) string {
+	if vmRestore.Spec.TargetNamespace != nil && *vmRestore.Spec.TargetNamespace != "" {
+		return *vmRestore.Spec.TargetNamespace
+	}
+
+	return vmRestore.Namespace
+}
+
 func (admitter *VMRestoreAdmitter) validatePatches(patches []string, field *k8sfield.Path) (causes []metav1.StatusCause) {
 	// Validate patches are either on labels/annotations or on elements under "/spec/" path only
 	for _, patch := range patches {
@@ -286,7 +471,39 @@ func (admitter *VMRestoreAdmitter) validateVolumeOverrides(ctx context.Context,
 			})
 		}
 
-		if override.RestoreName == "" && override.Annotations == nil && override.Labels == nil {
+		if override.Provisioning != nil {
+			switch *override.Provisioning {
+			case snapshotv1.VolumeProvisioningThin, snapshotv1.VolumeProvisioningThick:
+			default:
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("volume provisioning \"%s\" doesn't exist", *override.Provisioning),
+					Field: k8sfield.NewPath("spec").
+						Child("volumeRestoreOverrides").
+						Index(i).Child("provisioning").
+						String(),
+				})
+			}
+		}
+
+		for j, accessMode := range override.AccessModes {
+			switch accessMode {
+			case corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany, corev1.ReadWriteOncePod:
+			default:
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("access mode \"%s\" doesn't exist", accessMode),
+					Field: k8sfield.NewPath("spec").
+						Child("volumeRestoreOverrides").
+						Index(i).Child("accessModes").
+						Index(j).
+						String(),
+				})
+			}
+		}
+
+		if override.RestoreName == "" && override.Annotations == nil && override.Labels == nil && override.Provisioning == nil &&
+			override.StorageClassName == nil && len(override.AccessModes) == 0 {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueRequired,
 				Message: fmt.Sprintf("must provide at least one overriden field"),
@@ -299,9 +516,21 @@ func (admitter *VMRestoreAdmitter) validateVolumeOverrides(ctx context.Context,
 }
 
 func (admitter *VMRestoreAdmitter) validateVolumeRestorePolicy(ctx context.Context, vmRestore *snapshotv1.VirtualMachineRestore) (causes []metav1.StatusCause) {
+	autoSnapshot := vmRestore.Spec.AutoSnapshotBeforeInPlaceRestore
+	if autoSnapshot != nil && *autoSnapshot &&
+		(vmRestore.Spec.VolumeRestorePolicy == nil || *vmRestore.Spec.VolumeRestorePolicy != snapshotv1.VolumeRestorePolicyInPlace) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("autoSnapshotBeforeInPlaceRestore requires volume restore policy \"%s\"", snapshotv1.VolumeRestorePolicyInPlace),
+			Field: k8sfield.NewPath("spec").
+				Child("autoSnapshotBeforeInPlaceRestore").
+				String(),
+		})
+	}
+
 	// Cancel if there's no volume restore policy
 	if vmRestore.Spec.VolumeRestorePolicy == nil {
-		return nil
+		return causes
 	}
 
 	policy := *vmRestore.Spec.VolumeRestorePolicy
@@ -310,7 +539,7 @@ func (admitter *VMRestoreAdmitter) validateVolumeRestorePolicy(ctx context.Conte
 	switch policy {
 	case snapshotv1.VolumeRestorePolicyInPlace:
 	case snapshotv1.VolumeRestorePolicyRandomizeNames:
-		return nil
+		return causes
 	default:
 		causes = append(causes, metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
@@ -349,3 +578,241 @@ func (admitter *VMRestoreAdmitter) validateVolumeOwnershipPolicy(ctx context.Con
 
 	return causes
 }
+
+func (admitter *VMRestoreAdmitter) validateMetadataRestorePolicy(vmRestore *snapshotv1.VirtualMachineRestore) (causes []metav1.StatusCause) {
+	policy := vmRestore.Spec.MetadataRestorePolicy
+	if policy == nil {
+		return nil
+	}
+
+	policyField := k8sfield.NewPath("spec").Child("metadataRestorePolicy")
+
+	switch policy.Type {
+	case snapshotv1.MetadataRestorePolicySelective:
+		return nil
+	case snapshotv1.MetadataRestorePolicyAll, snapshotv1.MetadataRestorePolicyNone:
+		if len(policy.LabelKeys) > 0 || len(policy.AnnotationKeys) > 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("labelKeys and annotationKeys are only allowed with metadata restore policy \"%s\"", snapshotv1.MetadataRestorePolicySelective),
+				Field:   policyField.String(),
+			})
+		}
+	default:
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("metadata restore policy \"%s\" doesn't exist", policy.Type),
+			Field:   policyField.Child("type").String(),
+		})
+	}
+
+	return causes
+}
+
+func (admitter *VMRestoreAdmitter) validateResourceRemapping(ctx context.Context, vmRestore *snapshotv1.VirtualMachineRestore) (causes []metav1.StatusCause, warnings []string, err error) {
+	if len(vmRestore.Spec.ResourceRemapping) == 0 {
+		return nil, nil, nil
+	}
+
+	// the restored VM references its ConfigMaps in the namespace it is restored to
+	namespace := restoreTargetNamespace(vmRestore)
+	remappingField := k8sfield.NewPath("spec").Child("resourceRemapping")
+	remapped := map[snapshotv1.ResourceRemappingKind]map[string]bool{}
+
+	for i, remapping := range vmRestore.Spec.ResourceRemapping {
+		switch remapping.Kind {
+		case snapshotv1.ResourceRemappingSecret, snapshotv1.ResourceRemappingConfigMap:
+		default:
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("resource remapping kind \"%s\" is not supported", remapping.Kind),
+				Field:   remappingField.Index(i).Child("kind").String(),
+			})
+			continue
+		}
+
+		if remapping.SourceName == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: "must provide a source name",
+				Field:   remappingField.Index(i).Child("sourceName").String(),
+			})
+		}
+
+		if remapping.TargetName == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: "must provide a target name",
+				Field:   remappingField.Index(i).Child("targetName").String(),
+			})
+			continue
+		}
+
+		if remapped[remapping.Kind] == nil {
+			remapped[remapping.Kind] = map[string]bool{}
+		}
+		remapped[remapping.Kind][remapping.SourceName] = true
+
+		// KubeVirt is not allowed to read Secrets, only ConfigMap targets can be verified
+		if remapping.Kind != snapshotv1.ResourceRemappingConfigMap {
+			continue
+		}
+		exists, err := admitter.configMapExists(ctx, namespace, remapping.TargetName)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("ConfigMap %q does not exist in namespace %q", remapping.TargetName, namespace),
+				Field:   remappingField.Index(i).Child("targetName").String(),
+			})
+		}
+	}
+
+	if len(causes) > 0 {
+		return causes, nil, nil
+	}
+
+	snapshotVM, err := admitter.getSnapshotVM(ctx, vmRestore)
+	if err != nil || snapshotVM == nil {
+		return nil, nil, err
+	}
+
+	var unmapped []string
+	storageutils.VisitResourceReferences(&snapshotVM.Spec, func(kind snapshotv1.ResourceRemappingKind, name *string) {
+		if kind == snapshotv1.ResourceRemappingConfigMap && !remapped[kind][*name] {
+			unmapped = append(unmapped, *name)
+		}
+	})
+	sort.Strings(unmapped)
+
+	for _, name := range unmapped {
+		exists, err := admitter.configMapExists(ctx, namespace, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			warnings = append(warnings, fmt.Sprintf("ConfigMap %q referenced by the snapshotted VirtualMachine has no remapping and does not exist", name))
+		}
+	}
+
+	return nil, warnings, nil
+}
+
+func (admitter *VMRestoreAdmitter) configMapExists(ctx context.Context, namespace, name string) (bool, error) {
+	_, err := admitter.Client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// getSnapshotVM returns the VirtualMachine captured by the restore source, or nil
+// if the source does not exist (yet)
+func (admitter *VMRestoreAdmitter) getSnapshotVM(ctx context.Context, vmRestore *snapshotv1.VirtualMachineRestore) (*snapshotv1.VirtualMachine, error) {
+	vmSnapshotContent, err := admitter.getSnapshotContent(ctx, vmRestore)
+	if err != nil || vmSnapshotContent == nil {
+		return nil, err
+	}
+
+	return vmSnapshotContent.Spec.Source.VirtualMachine, nil
+}
+
+// getSnapshotContent returns the VirtualMachineSnapshotContent the restore reads
+// from, or nil if it does not exist (yet)
+func (admitter *VMRestoreAdmitter) getSnapshotContent(ctx context.Context, vmRestore *snapshotv1.VirtualMachineRestore) (*snapshotv1.VirtualMachineSnapshotContent, error) {
+	namespace := vmRestore.Namespace
+	contentName := vmRestore.Spec.VirtualMachineSnapshotContentName
+	if contentName == nil {
+		vmSnapshot, err := admitter.Client.VirtualMachineSnapshot(namespace).Get(ctx, vmRestore.Spec.VirtualMachineSnapshotName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if vmSnapshot.Status == nil {
+			return nil, nil
+		}
+		contentName = vmSnapshot.Status.VirtualMachineSnapshotContentName
+	}
+	if contentName == nil {
+		return nil, nil
+	}
+
+	vmSnapshotContent, err := admitter.Client.VirtualMachineSnapshotContent(namespace).Get(ctx, *contentName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return vmSnapshotContent, nil
+}
+
+// validateVolumeSelection rejects setting both IncludeVolumes and ExcludeVolumes,
+// and volume names which the snapshot content has no backup of
+func (admitter *VMRestoreAdmitter) validateVolumeSelection(ctx context.Context, vmRestore *snapshotv1.VirtualMachineRestore) (causes []metav1.StatusCause, err error) {
+	includeVolumes := vmRestore.Spec.IncludeVolumes
+	excludeVolumes := vmRestore.Spec.ExcludeVolumes
+	if len(includeVolumes) == 0 && len(excludeVolumes) == 0 {
+		return nil, nil
+	}
+
+	specField := k8sfield.NewPath("spec")
+	if len(includeVolumes) > 0 && len(excludeVolumes) > 0 {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "includeVolumes and excludeVolumes are mutually exclusive",
+			Field:   specField.Child("excludeVolumes").String(),
+		}}, nil
+	}
+
+	vmSnapshotContent, err := admitter.getSnapshotContent(ctx, vmRestore)
+	if err != nil || vmSnapshotContent == nil {
+		return nil, err
+	}
+
+	volumesField := specField.Child("includeVolumes")
+	volumeNames := includeVolumes
+	if len(excludeVolumes) > 0 {
+		volumesField = specField.Child("excludeVolumes")
+		volumeNames = excludeVolumes
+	}
+	for i, volumeName := range volumeNames {
+		backedUp := slices.ContainsFunc(vmSnapshotContent.Spec.VolumeBackups, func(volumeBackup snapshotv1.VolumeBackup) bool {
+			return volumeBackup.VolumeName == volumeName
+		})
+		if !backedUp {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("volume %s is not part of the snapshot", volumeName),
+				Field:   volumesField.Index(i).String(),
+			})
+		}
+	}
+
+	return causes, nil
+}
+
+func (admitter *VMRestoreAdmitter) validatePlacementPolicy(vmRestore *snapshotv1.VirtualMachineRestore) (causes []metav1.StatusCause) {
+	policy := vmRestore.Spec.PlacementPolicy
+	if policy == nil {
+		return nil
+	}
+
+	switch *policy {
+	case snapshotv1.PlacementPolicyPreserve, snapshotv1.PlacementPolicyClear:
+		return nil
+	default:
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("placement policy \"%s\" doesn't exist", *policy),
+			Field:   k8sfield.NewPath("spec").Child("placementPolicy").String(),
+		})
+	}
+
+	return causes
+}
@@ -28,6 +28,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -35,6 +36,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	v1 "kubevirt.io/api/core/v1"
 	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
@@ -363,6 +366,53 @@ var _ = Describe("Validating VirtualMachineRestore Admitter", func() {
 				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.target.apiGroup"))
 			})
 
+			It("should reject when neither snapshot nor snapshot content is set", func() {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.virtualMachineSnapshotName"))
+			})
+
+			It("should reject when both snapshot and snapshot content are set", func() {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotName:        vmSnapshotName,
+						VirtualMachineSnapshotContentName: pointer.P("content"),
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.virtualMachineSnapshotContentName"))
+				Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("mutually exclusive"))
+			})
+
 			It("should reject if restore in progress", func() {
 				restore := &snapshotv1.VirtualMachineRestore{
 					ObjectMeta: metav1.ObjectMeta{
@@ -426,6 +476,52 @@ var _ = Describe("Validating VirtualMachineRestore Admitter", func() {
 				Expect(resp.Allowed).To(BeTrue())
 			})
 
+			DescribeTable("should validate a directly referenced VirtualMachineSnapshotContent", func(readyToUse, allowed bool) {
+				vmSnapshotContent := &snapshotv1.VirtualMachineSnapshotContent{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "snapshot-content",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineSnapshotContentSpec{
+						Source: snapshotv1.SourceSpec{
+							VirtualMachine: &snapshotv1.VirtualMachine{
+								ObjectMeta: vm.ObjectMeta,
+							},
+						},
+					},
+					Status: &snapshotv1.VirtualMachineSnapshotContentStatus{
+						ReadyToUse: pointer.P(readyToUse),
+					},
+				}
+
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotContentName: pointer.P(vmSnapshotContent.Name),
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, vmSnapshotContent).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(Equal(allowed))
+				if !allowed {
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.virtualMachineSnapshotContentName"))
+					Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("is not ready to use"))
+				}
+			},
+				Entry("accept ready content", true, true),
+				Entry("reject content not ready to use", false, false),
+			)
+
 			It("should reject volume overrides if no parameter is specified", func() {
 				restore := &snapshotv1.VirtualMachineRestore{
 					ObjectMeta: metav1.ObjectMeta{
@@ -455,6 +551,71 @@ var _ = Describe("Validating VirtualMachineRestore Admitter", func() {
 				Expect(resp.Result.Details.Causes[1].Field).To(Equal("spec.volumeRestoreOverrides[0]"))
 			})
 
+			It("should reject invalid volume provisioning override", func() {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotName: vmSnapshotName,
+						VolumeRestoreOverrides: []snapshotv1.VolumeRestoreOverride{
+							{
+								VolumeName:   "disk1",
+								Provisioning: pointer.P(snapshotv1.VolumeProvisioning("invalid")),
+							},
+						},
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.volumeRestoreOverrides[0].provisioning"))
+			})
+
+			DescribeTable("should validate the access modes of a volume override", func(accessMode corev1.PersistentVolumeAccessMode, allowed bool) {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotName: vmSnapshotName,
+						VolumeRestoreOverrides: []snapshotv1.VolumeRestoreOverride{
+							{
+								VolumeName:  "disk1",
+								AccessModes: []corev1.PersistentVolumeAccessMode{accessMode},
+							},
+						},
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+
+				Expect(resp.Allowed).To(Equal(allowed))
+				if !allowed {
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.volumeRestoreOverrides[0].accessModes[0]"))
+				}
+			},
+				Entry("with ReadWriteMany", corev1.ReadWriteMany, true),
+				Entry("with an unknown access mode", corev1.PersistentVolumeAccessMode("invalid"), false),
+			)
+
 			It("should accept correct volume restore policy", func() {
 				restore := &snapshotv1.VirtualMachineRestore{
 					ObjectMeta: metav1.ObjectMeta{
@@ -503,6 +664,38 @@ var _ = Describe("Validating VirtualMachineRestore Admitter", func() {
 				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.volumeRestorePolicy"))
 			})
 
+			DescribeTable("should only allow autoSnapshotBeforeInPlaceRestore with policy InPlace", func(policy *snapshotv1.VolumeRestorePolicy, allowed bool) {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotName:       vmSnapshotName,
+						VolumeRestorePolicy:              policy,
+						AutoSnapshotBeforeInPlaceRestore: pointer.P(true),
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+
+				Expect(resp.Allowed).To(Equal(allowed))
+				if !allowed {
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.autoSnapshotBeforeInPlaceRestore"))
+				}
+			},
+				Entry("with InPlace", pointer.P(snapshotv1.VolumeRestorePolicyInPlace), true),
+				Entry("with RandomizeNames", pointer.P(snapshotv1.VolumeRestorePolicyRandomizeNames), false),
+				Entry("without policy", nil, false),
+			)
+
 			It("should accept correct volume ownership policy", func() {
 				restore := &snapshotv1.VirtualMachineRestore{
 					ObjectMeta: metav1.ObjectMeta{
@@ -551,6 +744,380 @@ var _ = Describe("Validating VirtualMachineRestore Admitter", func() {
 				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.volumeOwnershipPolicy"))
 			})
 
+			DescribeTable("should accept valid metadata restore policy", func(policy *snapshotv1.MetadataRestorePolicy) {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotName: vmSnapshotName,
+						MetadataRestorePolicy:      policy,
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(BeTrue())
+			},
+				Entry("All", &snapshotv1.MetadataRestorePolicy{Type: snapshotv1.MetadataRestorePolicyAll}),
+				Entry("None", &snapshotv1.MetadataRestorePolicy{Type: snapshotv1.MetadataRestorePolicyNone}),
+				Entry("Selective with keys", &snapshotv1.MetadataRestorePolicy{
+					Type:           snapshotv1.MetadataRestorePolicySelective,
+					LabelKeys:      []string{"app"},
+					AnnotationKeys: []string{"note"},
+				}),
+			)
+
+			DescribeTable("should reject invalid metadata restore policy", func(policy *snapshotv1.MetadataRestorePolicy, expectedField string) {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotName: vmSnapshotName,
+						MetadataRestorePolicy:      policy,
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal(expectedField))
+			},
+				Entry("with unknown type", &snapshotv1.MetadataRestorePolicy{
+					Type: "invalid",
+				}, "spec.metadataRestorePolicy.type"),
+				Entry("with keys and type All", &snapshotv1.MetadataRestorePolicy{
+					Type:      snapshotv1.MetadataRestorePolicyAll,
+					LabelKeys: []string{"app"},
+				}, "spec.metadataRestorePolicy"),
+			)
+
+			Context("with a target namespace", func() {
+				const targetNamespace = "staging"
+
+				namespace := &k8sv1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: targetNamespace,
+					},
+				}
+
+				newRestore := func() *snapshotv1.VirtualMachineRestore {
+					return &snapshotv1.VirtualMachineRestore{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "restore",
+							Namespace: "default",
+						},
+						Spec: snapshotv1.VirtualMachineRestoreSpec{
+							Target: corev1.TypedLocalObjectReference{
+								APIGroup: &apiGroup,
+								Kind:     "VirtualMachine",
+								Name:     vmName,
+							},
+							VirtualMachineSnapshotName: vmSnapshotName,
+							TargetNamespace:            pointer.P(targetNamespace),
+						},
+					}
+				}
+
+				admit := func(restore *snapshotv1.VirtualMachineRestore, user string, objs ...runtime.Object) *admissionv1.AdmissionResponse {
+					ar := createRestoreAdmissionReview(restore)
+					ar.Request.UserInfo.Username = user
+					return createTestVMRestoreAdmitter(config, append(objs, vm, snapshot)...).Admit(context.Background(), ar)
+				}
+
+				It("should accept when the user may create the VM and its volumes", func() {
+					resp := admit(newRestore(), authorizedUser, namespace)
+					Expect(resp.Allowed).To(BeTrue())
+				})
+
+				It("should reject when the namespace does not exist", func() {
+					resp := admit(newRestore(), authorizedUser)
+					Expect(resp.Allowed).To(BeFalse())
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.targetNamespace"))
+					Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("does not exist"))
+				})
+
+				It("should reject when the user may not create the VM and its volumes", func() {
+					resp := admit(newRestore(), "other-user", namespace)
+					Expect(resp.Allowed).To(BeFalse())
+					Expect(resp.Result.Details.Causes).To(HaveLen(2))
+					Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("not allowed to create virtualmachines"))
+					Expect(resp.Result.Details.Causes[1].Message).To(ContainSubstring("not allowed to create persistentvolumeclaims"))
+				})
+
+				It("should reject volume restore policy InPlace", func() {
+					restore := newRestore()
+					restore.Spec.VolumeRestorePolicy = pointer.P(snapshotv1.VolumeRestorePolicyInPlace)
+					resp := admit(restore, authorizedUser, namespace)
+					Expect(resp.Allowed).To(BeFalse())
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.targetNamespace"))
+				})
+
+				It("should look up remapped ConfigMaps in the target namespace", func() {
+					restore := newRestore()
+					restore.Spec.ResourceRemapping = []snapshotv1.ResourceRemapping{
+						{Kind: snapshotv1.ResourceRemappingConfigMap, SourceName: "old-config", TargetName: "new-config"},
+					}
+					sourceConfigMap := &k8sv1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "new-config",
+							Namespace: "default",
+						},
+					}
+
+					resp := admit(restore, authorizedUser, namespace, sourceConfigMap)
+					Expect(resp.Allowed).To(BeFalse())
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.resourceRemapping[0].targetName"))
+					Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring(`namespace "staging"`))
+
+					targetConfigMap := sourceConfigMap.DeepCopy()
+					targetConfigMap.Namespace = targetNamespace
+					resp = admit(restore, authorizedUser, namespace, targetConfigMap)
+					Expect(resp.Allowed).To(BeTrue())
+				})
+
+				It("should not treat a restore of the same target in another namespace as in progress", func() {
+					inProgress := newRestore()
+					inProgress.Name = "other-restore"
+					inProgress.Spec.TargetNamespace = nil
+
+					resp := admit(newRestore(), authorizedUser, namespace, inProgress)
+					Expect(resp.Allowed).To(BeTrue())
+				})
+			})
+
+			Context("with resource remapping", func() {
+				newConfigMap := func(name string) *k8sv1.ConfigMap {
+					return &k8sv1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: "default",
+						},
+					}
+				}
+
+				newRestore := func(remapping ...snapshotv1.ResourceRemapping) *snapshotv1.VirtualMachineRestore {
+					return &snapshotv1.VirtualMachineRestore{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "restore",
+							Namespace: "default",
+						},
+						Spec: snapshotv1.VirtualMachineRestoreSpec{
+							Target: corev1.TypedLocalObjectReference{
+								APIGroup: &apiGroup,
+								Kind:     "VirtualMachine",
+								Name:     vmName,
+							},
+							VirtualMachineSnapshotName: vmSnapshotName,
+							ResourceRemapping:          remapping,
+						},
+					}
+				}
+
+				It("should accept remapping to existing targets", func() {
+					restore := newRestore(
+						snapshotv1.ResourceRemapping{Kind: snapshotv1.ResourceRemappingConfigMap, SourceName: "old-config", TargetName: "new-config"},
+						snapshotv1.ResourceRemapping{Kind: snapshotv1.ResourceRemappingSecret, SourceName: "old-secret", TargetName: "new-secret"},
+					)
+
+					ar := createRestoreAdmissionReview(restore)
+					resp := createTestVMRestoreAdmitter(config, vm, snapshot, newConfigMap("new-config")).Admit(context.Background(), ar)
+					Expect(resp.Allowed).To(BeTrue())
+					Expect(resp.Warnings).To(BeEmpty())
+				})
+
+				DescribeTable("should reject invalid remapping", func(remapping snapshotv1.ResourceRemapping, expectedField string) {
+					ar := createRestoreAdmissionReview(newRestore(remapping))
+					resp := createTestVMRestoreAdmitter(config, vm, snapshot, newConfigMap("new-config")).Admit(context.Background(), ar)
+
+					Expect(resp.Allowed).To(BeFalse())
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal(expectedField))
+				},
+					Entry("with unknown kind",
+						snapshotv1.ResourceRemapping{Kind: "Pod", SourceName: "old", TargetName: "new"},
+						"spec.resourceRemapping[0].kind"),
+					Entry("without source name",
+						snapshotv1.ResourceRemapping{Kind: snapshotv1.ResourceRemappingConfigMap, TargetName: "new-config"},
+						"spec.resourceRemapping[0].sourceName"),
+					Entry("without target name",
+						snapshotv1.ResourceRemapping{Kind: snapshotv1.ResourceRemappingSecret, SourceName: "old-secret"},
+						"spec.resourceRemapping[0].targetName"),
+					Entry("with missing ConfigMap target",
+						snapshotv1.ResourceRemapping{Kind: snapshotv1.ResourceRemappingConfigMap, SourceName: "old-config", TargetName: "missing"},
+						"spec.resourceRemapping[0].targetName"),
+				)
+
+				It("should warn about unmapped ConfigMaps which do not exist", func() {
+					snapshotVM := &snapshotv1.VirtualMachine{
+						Spec: v1.VirtualMachineSpec{
+							Template: &v1.VirtualMachineInstanceTemplateSpec{
+								Spec: v1.VirtualMachineInstanceSpec{
+									Volumes: []v1.Volume{
+										{
+											Name: "mapped",
+											VolumeSource: v1.VolumeSource{
+												ConfigMap: &v1.ConfigMapVolumeSource{
+													LocalObjectReference: k8sv1.LocalObjectReference{Name: "old-config"},
+												},
+											},
+										},
+										{
+											Name: "present",
+											VolumeSource: v1.VolumeSource{
+												ConfigMap: &v1.ConfigMapVolumeSource{
+													LocalObjectReference: k8sv1.LocalObjectReference{Name: "present-config"},
+												},
+											},
+										},
+										{
+											Name: "absent",
+											VolumeSource: v1.VolumeSource{
+												ConfigMap: &v1.ConfigMapVolumeSource{
+													LocalObjectReference: k8sv1.LocalObjectReference{Name: "absent-config"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					}
+					vmSnapshotContent := &snapshotv1.VirtualMachineSnapshotContent{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "snapshot-content",
+							Namespace: "default",
+						},
+						Spec: snapshotv1.VirtualMachineSnapshotContentSpec{
+							Source: snapshotv1.SourceSpec{
+								VirtualMachine: snapshotVM,
+							},
+						},
+					}
+					snapshotWithContent := snapshot.DeepCopy()
+					snapshotWithContent.Status.VirtualMachineSnapshotContentName = pointer.P(vmSnapshotContent.Name)
+
+					restore := newRestore(
+						snapshotv1.ResourceRemapping{Kind: snapshotv1.ResourceRemappingConfigMap, SourceName: "old-config", TargetName: "new-config"},
+					)
+
+					ar := createRestoreAdmissionReview(restore)
+					resp := createTestVMRestoreAdmitter(config, vm, snapshotWithContent, vmSnapshotContent,
+						newConfigMap("new-config"), newConfigMap("present-config")).Admit(context.Background(), ar)
+					Expect(resp.Allowed).To(BeTrue())
+					Expect(resp.Warnings).To(ConsistOf(
+						`ConfigMap "absent-config" referenced by the snapshotted VirtualMachine has no remapping and does not exist`,
+					))
+				})
+			})
+
+			Context("with volume selection", func() {
+				var (
+					snapshotWithContent *snapshotv1.VirtualMachineSnapshot
+					vmSnapshotContent   *snapshotv1.VirtualMachineSnapshotContent
+				)
+
+				BeforeEach(func() {
+					vmSnapshotContent = &snapshotv1.VirtualMachineSnapshotContent{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "snapshot-content",
+							Namespace: "default",
+						},
+						Spec: snapshotv1.VirtualMachineSnapshotContentSpec{
+							VolumeBackups: []snapshotv1.VolumeBackup{
+								{VolumeName: "rootdisk"},
+								{VolumeName: "datadisk"},
+							},
+						},
+					}
+					snapshotWithContent = snapshot.DeepCopy()
+					snapshotWithContent.Status.VirtualMachineSnapshotContentName = pointer.P(vmSnapshotContent.Name)
+				})
+
+				newRestore := func(includeVolumes, excludeVolumes []string) *snapshotv1.VirtualMachineRestore {
+					return &snapshotv1.VirtualMachineRestore{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "restore",
+							Namespace: "default",
+						},
+						Spec: snapshotv1.VirtualMachineRestoreSpec{
+							Target: corev1.TypedLocalObjectReference{
+								APIGroup: &apiGroup,
+								Kind:     "VirtualMachine",
+								Name:     vmName,
+							},
+							VirtualMachineSnapshotName: vmSnapshotName,
+							IncludeVolumes:             includeVolumes,
+							ExcludeVolumes:             excludeVolumes,
+						},
+					}
+				}
+
+				DescribeTable("should accept volumes which are part of the snapshot", func(includeVolumes, excludeVolumes []string) {
+					ar := createRestoreAdmissionReview(newRestore(includeVolumes, excludeVolumes))
+					resp := createTestVMRestoreAdmitter(config, vm, snapshotWithContent, vmSnapshotContent).Admit(context.Background(), ar)
+					Expect(resp.Allowed).To(BeTrue())
+				},
+					Entry("in includeVolumes", []string{"rootdisk"}, nil),
+					Entry("in excludeVolumes", nil, []string{"datadisk"}),
+				)
+
+				DescribeTable("should reject", func(includeVolumes, excludeVolumes []string, expectedField string) {
+					ar := createRestoreAdmissionReview(newRestore(includeVolumes, excludeVolumes))
+					resp := createTestVMRestoreAdmitter(config, vm, snapshotWithContent, vmSnapshotContent).Admit(context.Background(), ar)
+					Expect(resp.Allowed).To(BeFalse())
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal(expectedField))
+				},
+					Entry("both includeVolumes and excludeVolumes", []string{"rootdisk"}, []string{"datadisk"}, "spec.excludeVolumes"),
+					Entry("an included volume which is not part of the snapshot", []string{"rootdisk", "missing"}, nil, "spec.includeVolumes[1]"),
+					Entry("an excluded volume which is not part of the snapshot", nil, []string{"missing"}, "spec.excludeVolumes[0]"),
+				)
+			})
+
+			It("should reject unknown placement policy", func() {
+				restore := &snapshotv1.VirtualMachineRestore{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "restore",
+						Namespace: "default",
+					},
+					Spec: snapshotv1.VirtualMachineRestoreSpec{
+						Target: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						VirtualMachineSnapshotName: vmSnapshotName,
+						PlacementPolicy:            pointer.P(snapshotv1.PlacementPolicy("Keep")),
+					},
+				}
+
+				ar := createRestoreAdmissionReview(restore)
+				resp := createTestVMRestoreAdmitter(config, vm, snapshot).Admit(context.Background(), ar)
+
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.placementPolicy"))
+			})
+
 			DescribeTable("Should reject restore when using backend storage and restoring to different VM", func(doesTargetExist bool) {
 				const targetVMName = "new-test-vm"
 				targetVM := &v1.VirtualMachine{}
@@ -734,6 +1301,8 @@ func createRestoreUpdateAdmissionReview(old, current *snapshotv1.VirtualMachineR
 	return ar
 }
 
+const authorizedUser = "authorized-user"
+
 func createTestVMRestoreAdmitter(
 	config *virtconfig.ClusterConfig,
 	objs ...runtime.Object,
@@ -741,12 +1310,30 @@ func createTestVMRestoreAdmitter(
 	ctrl := gomock.NewController(GinkgoT())
 	virtClient := kubecli.NewMockKubevirtClient(ctrl)
 	vmInterface := kubecli.NewMockVirtualMachineInterface(ctrl)
-	kubevirtClient := kubevirtfake.NewSimpleClientset(objs...)
+
+	var kubevirtObjs, k8sObjs []runtime.Object
+	for _, obj := range objs {
+		switch obj.(type) {
+		case *k8sv1.ConfigMap, *k8sv1.Namespace:
+			k8sObjs = append(k8sObjs, obj)
+		default:
+			kubevirtObjs = append(kubevirtObjs, obj)
+		}
+	}
+	kubevirtClient := kubevirtfake.NewSimpleClientset(kubevirtObjs...)
+	k8sClient := k8sfake.NewSimpleClientset(k8sObjs...)
+	k8sClient.Fake.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.User == authorizedUser
+		return true, sar, nil
+	})
 
 	virtClient.EXPECT().VirtualMachineSnapshot("default").
 		Return(kubevirtClient.SnapshotV1beta1().VirtualMachineSnapshots("default")).AnyTimes()
 	virtClient.EXPECT().VirtualMachine(gomock.Any()).Return(vmInterface).AnyTimes()
 	virtClient.EXPECT().VirtualMachineSnapshotContent("default").Return(kubevirtClient.SnapshotV1beta1().VirtualMachineSnapshotContents("default")).AnyTimes()
+	virtClient.EXPECT().CoreV1().Return(k8sClient.CoreV1()).AnyTimes()
+	virtClient.EXPECT().AuthorizationV1().Return(k8sClient.AuthorizationV1()).AnyTimes()
 
 	restoreInformer, _ := testutils.NewFakeInformerFor(&snapshotv1.VirtualMachineRestore{})
 	for _, obj := range objs {
@@ -23,13 +23,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 
 	"kubevirt.io/api/core"
+	v1 "kubevirt.io/api/core/v1"
 
 	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
 	"kubevirt.io/client-go/kubecli"
@@ -38,6 +41,12 @@ import (
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
+const (
+	// maxSnapshotHookTimeout is the longest a snapshot hook may run, the
+	// freeze and unfreeze requests wait for the hooks
+	maxSnapshotHookTimeout = 2 * time.Minute
+)
+
 // VMSnapshotAdmitter validates VirtualMachineSnapshots
 type VMSnapshotAdmitter struct {
 	Config *virtconfig.ClusterConfig
@@ -108,6 +117,38 @@ func (admitter *VMSnapshotAdmitter) Admit(ctx context.Context, ar *admissionv1.A
 			}
 		}
 
+		if snapshotType := vmSnapshot.Spec.SnapshotType; snapshotType != nil &&
+			*snapshotType != snapshotv1.SnapshotTypeFull && *snapshotType != snapshotv1.SnapshotTypeDefinitionOnly {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("invalid snapshotType %q", *snapshotType),
+				Field:   k8sfield.NewPath("spec", "snapshotType").String(),
+			})
+		}
+
+		if includeMemoryDump := vmSnapshot.Spec.IncludeMemoryDump; includeMemoryDump != nil && *includeMemoryDump &&
+			vmSnapshot.Spec.SnapshotType != nil && *vmSnapshot.Spec.SnapshotType == snapshotv1.SnapshotTypeDefinitionOnly {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "includeMemoryDump is not supported by DefinitionOnly snapshots",
+				Field:   k8sfield.NewPath("spec", "includeMemoryDump").String(),
+			})
+		}
+
+		if ttl := vmSnapshot.Spec.TTLAfterSuccess; ttl != nil && ttl.Duration < 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "ttlAfterSuccess must not be negative",
+				Field:   k8sfield.NewPath("spec", "ttlAfterSuccess").String(),
+			})
+		}
+
+		hookCauses, err := admitter.validateSnapshotHooks(ctx, ar.Request, vmSnapshot)
+		if err != nil {
+			return webhookutils.ToAdmissionResponseError(err)
+		}
+		causes = append(causes, hookCauses...)
+
 	case admissionv1.Update:
 		prevObj := &snapshotv1.VirtualMachineSnapshot{}
 		err = json.Unmarshal(ar.Request.OldObject.Raw, prevObj)
@@ -115,7 +156,10 @@ func (admitter *VMSnapshotAdmitter) Admit(ctx context.Context, ar *admissionv1.A
 			return webhookutils.ToAdmissionResponseError(err)
 		}
 
-		if !equality.Semantic.DeepEqual(prevObj.Spec, vmSnapshot.Spec) {
+		// forceDelete is meant to be set once the deletion is stuck
+		prevSpec, spec := prevObj.Spec.DeepCopy(), vmSnapshot.Spec.DeepCopy()
+		prevSpec.ForceDelete, spec.ForceDelete = false, false
+		if !equality.Semantic.DeepEqual(prevSpec, spec) {
 			causes = []metav1.StatusCause{
 				{
 					Type:    metav1.CauseTypeFieldValueInvalid,
@@ -137,3 +181,72 @@ func (admitter *VMSnapshotAdmitter) Admit(ctx context.Context, ar *admissionv1.A
 	}
 	return &reviewResponse
 }
+
+// validateSnapshotHooks requires a command and a name, unique within its
+// list, for every hook, as failures are reported by hook name. The hooks are
+// run with the freeze of the source VM, so the user creating the snapshot must
+// be allowed to freeze it.
+func (admitter *VMSnapshotAdmitter) validateSnapshotHooks(ctx context.Context, request *admissionv1.AdmissionRequest, vmSnapshot *snapshotv1.VirtualMachineSnapshot) ([]metav1.StatusCause, error) {
+	hooks := vmSnapshot.Spec.Hooks
+	if hooks == nil {
+		return nil, nil
+	}
+
+	hooksField := k8sfield.NewPath("spec", "hooks")
+	var causes []metav1.StatusCause
+	for _, stage := range []struct {
+		field *k8sfield.Path
+		hooks []snapshotv1.SnapshotHook
+	}{
+		{hooksField.Child("preFreeze"), hooks.PreFreeze},
+		{hooksField.Child("postThaw"), hooks.PostThaw},
+	} {
+		names := map[string]struct{}{}
+		for i, hook := range stage.hooks {
+			hookField := stage.field.Index(i)
+			if _, exists := names[hook.Name]; exists || hook.Name == "" {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("hook name %q is empty or not unique", hook.Name),
+					Field:   hookField.Child("name").String(),
+				})
+			}
+			names[hook.Name] = struct{}{}
+			if hook.Command == "" {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueRequired,
+					Message: "hook command is required",
+					Field:   hookField.Child("command").String(),
+				})
+			}
+			if hook.Timeout != nil && (hook.Timeout.Duration < time.Second || hook.Timeout.Duration > maxSnapshotHookTimeout) {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("hook timeout must be between 1s and %s", maxSnapshotHookTimeout),
+					Field:   hookField.Child("timeout").String(),
+				})
+			}
+		}
+	}
+
+	allowed, err := userAllowed(ctx, admitter.Client, request.UserInfo, authv1.ResourceAttributes{
+		Namespace:   request.Namespace,
+		Verb:        "update",
+		Group:       v1.SubresourceGroupName,
+		Resource:    "virtualmachineinstances",
+		Subresource: "freeze",
+		Name:        vmSnapshot.Spec.Source.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("user %q is not allowed to freeze VirtualMachine %q", request.UserInfo.Username, vmSnapshot.Spec.Source.Name),
+			Field:   hooksField.String(),
+		})
+	}
+
+	return causes, nil
+}
@@ -22,17 +22,21 @@ package admitters
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	v1 "kubevirt.io/api/core/v1"
 	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
@@ -192,6 +196,50 @@ var _ = Describe("Validating VirtualMachineSnapshot Admitter", func() {
 			Expect(resp.Allowed).To(BeTrue())
 		})
 
+		It("should allow setting forceDelete after creation", func() {
+			oldSnapshot := &snapshotv1.VirtualMachineSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: pointer.P(metav1.Now()),
+				},
+				Spec: snapshotv1.VirtualMachineSnapshotSpec{
+					Source: corev1.TypedLocalObjectReference{
+						APIGroup: &apiGroup,
+						Kind:     "VirtualMachine",
+						Name:     vmName,
+					},
+				},
+			}
+
+			snapshot := oldSnapshot.DeepCopy()
+			snapshot.Spec.ForceDelete = true
+
+			ar := createSnapshotUpdateAdmissionReview(oldSnapshot, snapshot)
+			resp := createTestVMSnapshotAdmitter(config, nil).Admit(context.Background(), ar)
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject a spec update along with forceDelete", func() {
+			oldSnapshot := &snapshotv1.VirtualMachineSnapshot{
+				Spec: snapshotv1.VirtualMachineSnapshotSpec{
+					Source: corev1.TypedLocalObjectReference{
+						APIGroup: &apiGroup,
+						Kind:     "VirtualMachine",
+						Name:     vmName,
+					},
+				},
+			}
+
+			snapshot := oldSnapshot.DeepCopy()
+			snapshot.Spec.ForceDelete = true
+			snapshot.Spec.Source.Name = "baz"
+
+			ar := createSnapshotUpdateAdmissionReview(oldSnapshot, snapshot)
+			resp := createTestVMSnapshotAdmitter(config, nil).Admit(context.Background(), ar)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec"))
+		})
+
 		Context("when VirtualMachine exists", func() {
 			var vm *v1.VirtualMachine
 
@@ -262,6 +310,134 @@ var _ = Describe("Validating VirtualMachineSnapshot Admitter", func() {
 				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.source.apiGroup"))
 			})
 
+			It("should reject invalid snapshotType", func() {
+				snapshot := &snapshotv1.VirtualMachineSnapshot{
+					Spec: snapshotv1.VirtualMachineSnapshotSpec{
+						Source: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						SnapshotType: pointer.P(snapshotv1.SnapshotType("Partial")),
+					},
+				}
+
+				ar := createSnapshotAdmissionReview(snapshot)
+				resp := createTestVMSnapshotAdmitter(config, vm).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.snapshotType"))
+			})
+
+			It("should reject includeMemoryDump for a DefinitionOnly snapshot", func() {
+				snapshot := &snapshotv1.VirtualMachineSnapshot{
+					Spec: snapshotv1.VirtualMachineSnapshotSpec{
+						Source: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						SnapshotType:      pointer.P(snapshotv1.SnapshotTypeDefinitionOnly),
+						IncludeMemoryDump: pointer.P(true),
+					},
+				}
+
+				ar := createSnapshotAdmissionReview(snapshot)
+				resp := createTestVMSnapshotAdmitter(config, vm).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.includeMemoryDump"))
+			})
+
+			It("should reject a negative ttlAfterSuccess", func() {
+				snapshot := &snapshotv1.VirtualMachineSnapshot{
+					Spec: snapshotv1.VirtualMachineSnapshotSpec{
+						Source: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						TTLAfterSuccess: &metav1.Duration{Duration: -time.Hour},
+					},
+				}
+
+				ar := createSnapshotAdmissionReview(snapshot)
+				resp := createTestVMSnapshotAdmitter(config, vm).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.ttlAfterSuccess"))
+			})
+
+			It("should reject hooks without a unique name or a command", func() {
+				snapshot := &snapshotv1.VirtualMachineSnapshot{
+					Spec: snapshotv1.VirtualMachineSnapshotSpec{
+						Source: corev1.TypedLocalObjectReference{
+							APIGroup: &apiGroup,
+							Kind:     "VirtualMachine",
+							Name:     vmName,
+						},
+						Hooks: &snapshotv1.SnapshotHooks{
+							PreFreeze: []snapshotv1.SnapshotHook{
+								{Name: "flush", Command: "/usr/bin/flush"},
+								{Name: "flush", Command: "/usr/bin/lock"},
+							},
+							PostThaw: []snapshotv1.SnapshotHook{
+								{Name: "unlock", Timeout: &metav1.Duration{}},
+								{Name: "resume", Command: "/usr/bin/resume", Timeout: &metav1.Duration{Duration: time.Hour}},
+							},
+						},
+					},
+				}
+
+				ar := createSnapshotAdmissionReview(snapshot)
+				ar.Request.UserInfo.Username = authorizedUser
+				resp := createTestVMSnapshotAdmitter(config, vm).Admit(context.Background(), ar)
+				Expect(resp.Allowed).To(BeFalse())
+				Expect(resp.Result.Details.Causes).To(HaveLen(4))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.hooks.preFreeze[1].name"))
+				Expect(resp.Result.Details.Causes[1].Field).To(Equal("spec.hooks.postThaw[0].command"))
+				Expect(resp.Result.Details.Causes[2].Field).To(Equal("spec.hooks.postThaw[0].timeout"))
+				Expect(resp.Result.Details.Causes[3].Field).To(Equal("spec.hooks.postThaw[1].timeout"))
+			})
+
+			Context("with hooks", func() {
+				var snapshot *snapshotv1.VirtualMachineSnapshot
+
+				BeforeEach(func() {
+					snapshot = &snapshotv1.VirtualMachineSnapshot{
+						Spec: snapshotv1.VirtualMachineSnapshotSpec{
+							Source: corev1.TypedLocalObjectReference{
+								APIGroup: &apiGroup,
+								Kind:     "VirtualMachine",
+								Name:     vmName,
+							},
+							Hooks: &snapshotv1.SnapshotHooks{
+								PreFreeze: []snapshotv1.SnapshotHook{
+									{Name: "flush", Command: "/usr/bin/flush"},
+								},
+							},
+						},
+					}
+				})
+
+				It("should reject hooks of a user not allowed to freeze the vm", func() {
+					ar := createSnapshotAdmissionReview(snapshot)
+					ar.Request.UserInfo.Username = "unauthorized-user"
+					resp := createTestVMSnapshotAdmitter(config, vm).Admit(context.Background(), ar)
+					Expect(resp.Allowed).To(BeFalse())
+					Expect(resp.Result.Details.Causes).To(HaveLen(1))
+					Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.hooks"))
+					Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring(`user "unauthorized-user" is not allowed`))
+				})
+
+				It("should accept hooks of a user allowed to freeze the vm", func() {
+					ar := createSnapshotAdmissionReview(snapshot)
+					ar.Request.UserInfo.Username = authorizedUser
+					resp := createTestVMSnapshotAdmitter(config, vm).Admit(context.Background(), ar)
+					Expect(resp.Allowed).To(BeTrue())
+				})
+			})
+
 			DescribeTable("should accept persistent storage with both offline and online snapshot", func(runStrategy v1.VirtualMachineRunStrategy) {
 				vm.Spec.Template = &v1.VirtualMachineInstanceTemplateSpec{
 					Spec: v1.VirtualMachineInstanceSpec{
@@ -311,6 +487,7 @@ var _ = Describe("Validating VirtualMachineSnapshot Admitter", func() {
 				Expect(resp.Allowed).To(BeTrue())
 			})
 		})
+
 	})
 })
 
@@ -363,6 +540,14 @@ func createTestVMSnapshotAdmitter(config *virtconfig.ClusterConfig, vm *v1.Virtu
 	virtClient := kubecli.NewMockKubevirtClient(ctrl)
 	vmInterface := kubecli.NewMockVirtualMachineInterface(ctrl)
 	virtClient.EXPECT().VirtualMachine(gomock.Any()).Return(vmInterface).AnyTimes()
+	k8sClient := k8sfake.NewSimpleClientset()
+	k8sClient.Fake.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authv1.SubjectAccessReview)
+		Expect(sar.Spec.ResourceAttributes.Subresource).To(Equal("freeze"))
+		sar.Status.Allowed = sar.Spec.User == authorizedUser
+		return true, sar, nil
+	})
+	virtClient.EXPECT().AuthorizationV1().Return(k8sClient.AuthorizationV1()).AnyTimes()
 	if vm == nil {
 		err := errors.NewNotFound(schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachines"}, "foo")
 		vmInterface.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, err).AnyTimes()
@@ -20,10 +20,19 @@
 package snapshot
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"maps"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"slices"
 	"strings"
 	"time"
 
@@ -36,9 +45,11 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	validation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/cache"
 
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
@@ -58,6 +69,10 @@ import (
 const (
 	RestoreNameAnnotation = "restore.kubevirt.io/name"
 
+	// RestoreNamespaceAnnotation is set on the objects created in the target
+	// namespace of a cross-namespace restore, to find the restore they belong to
+	RestoreNamespaceAnnotation = "restore.kubevirt.io/namespace"
+
 	vmRestoreFinalizer = "snapshot.kubevirt.io/vmrestore-protection"
 
 	populatedForPVCAnnotation = "cdi.kubevirt.io/storage.populatedFor"
@@ -78,10 +93,34 @@ const (
 
 	restoreDataVolumeCreateErrorEvent = "RestoreDataVolumeCreateError"
 
+	restoreAccessCredentialsRequiredEvent = "RestoreAccessCredentialsRequired"
+
+	restorePlacementUnschedulableEvent = "RestorePlacementUnschedulable"
+
+	restorePVCNotBoundEvent = "RestorePVCNotBound"
+
 	restoreOwnedByVMLabel = "restore.kubevirt.io/owned-by-vm"
 
+	// restoreProvisioningAnnotation passes the VolumeRestoreOverride
+	// provisioning hint to the provisioner of the restored PVC
+	restoreProvisioningAnnotation = "restore.kubevirt.io/provisioning"
+
+	// storageClassProvisioningAnnotation lists, comma separated, the
+	// provisioning hints a StorageClass is able to honor
+	storageClassProvisioningAnnotation = "restore.kubevirt.io/supported-provisioning"
+
+	restoreProvisioningUnsupportedEvent = "RestoreProvisioningUnsupported"
+
+	restorePreRestoreSnapshotCreatedEvent = "RestorePreRestoreSnapshotCreated"
+
 	defaultPvcRestorePrefix = "restore"
 
+	preRestoreSnapshotPrefix = "pre-restore"
+
+	restoreValidationPrefix = "restore-validation-"
+
+	postRestoreCloudInitVolumeName = "post-restore-cloudinit"
+
 	waitEventuallyMessage = "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore"
 	stopTargetMessage     = "Automatically stopping restore target for restore operation"
 
@@ -90,6 +129,15 @@ const (
 
 	restoreFailedEvent           = "Operation failed"
 	errorRestoreToExistingTarget = "restore source and restore target are different but restore target already exists"
+
+	// restorePVCBindingTimeout is how long a restored PVC with immediate
+	// binding may stay Pending before the restore warns about it. The
+	// restore keeps waiting, provisioning large volumes may take longer
+	restorePVCBindingTimeout = 10 * time.Minute
+
+	// targetNotReadyRetryInterval is how often a restore waiting for its
+	// target to be ready checks it again, besides the VM updates
+	targetNotReadyRetryInterval = 30 * time.Second
 )
 
 var (
@@ -109,6 +157,7 @@ type restoreTarget interface {
 	UID() types.UID
 	VirtualMachine() *kubevirtv1.VirtualMachine
 	TargetRestored() bool
+	InvalidPatch() (string, error)
 }
 
 type vmRestoreTarget struct {
@@ -126,6 +175,14 @@ var restoreAnnotationsToDelete = []string{
 	"k8s.io/CloneOf",
 }
 
+// restoreStrippedVMAnnotations are KubeVirt managed annotations which are never
+// restored from the snapshotted VM when a MetadataRestorePolicy is set
+var restoreStrippedVMAnnotations = []string{
+	kubevirtv1.ControllerAPILatestVersionObservedAnnotation,
+	kubevirtv1.ControllerAPIStorageVersionObservedAnnotation,
+	lastRestoreAnnotation,
+}
+
 // getRestoreNameOverride returns the overridden name for a volume restore
 func getRestoreNameOverride(vmRestore *snapshotv1.VirtualMachineRestore, volumeName string) string {
 	for _, override := range vmRestore.Spec.VolumeRestoreOverrides {
@@ -239,7 +296,7 @@ func (ctrl *VMRestoreController) updateVMRestore(vmRestoreIn *snapshotv1.Virtual
 		return 0, ctrl.doUpdateError(vmRestoreIn, err)
 	}
 	if !ready {
-		return 0, ctrl.handleVMRestoreTargetNotReady(vmRestoreOut, target)
+		return ctrl.handleVMRestoreTargetNotReady(vmRestoreOut, target)
 	}
 
 	vmSnapshot, err := ctrl.getVMSnapshot(vmRestoreOut)
@@ -256,6 +313,65 @@ func (ctrl *VMRestoreController) updateVMRestore(vmRestoreIn *snapshotv1.Virtual
 		return 0, ctrl.doUpdateError(vmRestoreIn, fmt.Errorf(errorRestoreToExistingTarget))
 	}
 
+	if len(vmRestoreOut.Status.Restores) == 0 && !target.TargetRestored() {
+		missingStorageClass, err := ctrl.missingOverrideStorageClass(vmRestoreOut)
+		if err != nil {
+			logger.Reason(err).Error("Error checking overridden storage classes")
+			return 0, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+		if missingStorageClass != "" {
+			logger.Error(missingStorageClass)
+			return 0, ctrl.doUpdateErrorWithFailure(vmRestoreIn, missingStorageClass, true)
+		}
+
+		unknownVolume, err := ctrl.unknownSelectedVolume(vmRestoreOut, vmSnapshot)
+		if err != nil {
+			logger.Reason(err).Error("Error checking volumes selected for restore")
+			return 0, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+		if unknownVolume != "" {
+			logger.Error(unknownVolume)
+			return 0, ctrl.doUpdateErrorWithFailure(vmRestoreIn, unknownVolume, true)
+		}
+
+		unsupportedCloudInit, err := ctrl.unsupportedPostRestoreCloudInit(vmRestoreOut, vmSnapshot)
+		if err != nil {
+			logger.Reason(err).Error("Error checking post restore cloud-init")
+			return 0, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+		if unsupportedCloudInit != "" {
+			logger.Error(unsupportedCloudInit)
+			return 0, ctrl.doUpdateErrorWithFailure(vmRestoreIn, unsupportedCloudInit, true)
+		}
+
+		invalidPatch, err := target.InvalidPatch()
+		if err != nil {
+			logger.Reason(err).Error("Error validating restore patches")
+			return 0, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+		if invalidPatch != "" {
+			logger.Error(invalidPatch)
+			return 0, ctrl.doUpdateErrorWithFailure(vmRestoreIn, invalidPatch, true)
+		}
+
+		incompatibility, err := ctrl.validateSnapshotVMSpec(vmRestoreOut, target, vmSnapshot)
+		if err != nil {
+			logger.Reason(err).Error("Error validating snapshot VM spec")
+			return 0, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+		if incompatibility != "" {
+			logger.Error(incompatibility)
+			return 0, ctrl.doUpdateErrorWithFailure(vmRestoreIn, incompatibility, true)
+		}
+	}
+
+	if needsPreRestoreSnapshot(vmRestoreOut, target) {
+		done, err := ctrl.handlePreRestoreSnapshot(vmRestoreIn, vmRestoreOut, target)
+		if err != nil || !done {
+			return 0, err
+		}
+	}
+
 	err = target.UpdateRestoreInProgress()
 	if err != nil {
 		return 0, err
@@ -272,6 +388,20 @@ func (ctrl *VMRestoreController) updateVMRestore(vmRestoreIn *snapshotv1.Virtual
 		return 0, ctrl.doUpdateStatus(vmRestoreIn, vmRestoreOut)
 	}
 
+	if err = ctrl.updateRestoreProgress(vmRestoreOut, vmSnapshot); err != nil {
+		logger.Reason(err).Error("Error computing restore progress")
+		return 0, ctrl.doUpdateError(vmRestoreIn, err)
+	}
+
+	pendingPVCs, err := ctrl.restorePVCsPendingBinding(vmRestoreOut)
+	if err != nil {
+		logger.Reason(err).Error("Error checking restored PVCs binding")
+		return 0, ctrl.doUpdateError(vmRestoreIn, err)
+	}
+	if len(pendingPVCs) > 0 {
+		return ctrl.handleRestorePVCsPendingBinding(vmRestoreIn, vmRestoreOut, pendingPVCs)
+	}
+
 	updated, err = target.Reconcile()
 	if err != nil {
 		logger.Reason(err).Error("Error reconciling target")
@@ -294,16 +424,48 @@ func (ctrl *VMRestoreController) updateVMRestore(vmRestoreIn *snapshotv1.Virtual
 		return 0, ctrl.doUpdateError(vmRestoreIn, err)
 	}
 
+	if err = ctrl.hotplugRestoredVolumes(vmSnapshot, vmRestoreOut, target); err != nil {
+		logger.Reason(err).Error("Error hotplugging restored volumes")
+		return 0, ctrl.doUpdateError(vmRestoreIn, err)
+	}
+
+	vmRestoreOut.Status.MemoryDump, err = ctrl.memoryDumpLocation(vmSnapshot)
+	if err != nil {
+		logger.Reason(err).Error("Error getting the memory dump of the snapshot")
+		return 0, ctrl.doUpdateError(vmRestoreIn, err)
+	}
+
+	if secrets := accessCredentialSecrets(target.VirtualMachine()); len(secrets) > 0 {
+		msg := fmt.Sprintf("Access credential secrets %s of the restored VM are not part of the snapshot and must exist in namespace %s",
+			strings.Join(secrets, ", "), target.VirtualMachine().Namespace)
+		ctrl.Recorder.Event(vmRestoreOut, corev1.EventTypeWarning, restoreAccessCredentialsRequiredEvent, msg)
+		updateRestoreCondition(vmRestoreOut, newAccessCredentialsRequiredCondition(corev1.ConditionTrue, msg))
+	}
+
+	if placementPreserved(vmRestoreOut) {
+		msg, err := ctrl.unschedulablePlacement(target.VirtualMachine())
+		if err != nil {
+			logger.Reason(err).Error("Error checking restored VM placement")
+			return 0, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+		if msg != "" {
+			ctrl.Recorder.Event(vmRestoreOut, corev1.EventTypeWarning, restorePlacementUnschedulableEvent, msg)
+			updateRestoreCondition(vmRestoreOut, newPlacementUnschedulableCondition(corev1.ConditionTrue, msg))
+		}
+	}
+
 	ctrl.Recorder.Eventf(
 		vmRestoreOut,
 		corev1.EventTypeNormal,
 		restoreCompleteEvent,
-		"Successfully completed VirtualMachineRestore %s",
+		"Successfully completed VirtualMachineRestore %s, %s",
 		vmRestoreOut.Name,
+		restoredResourcesSummary(vmRestoreOut, target.VirtualMachine()),
 	)
 
 	t := true
 	vmRestoreOut.Status.Complete = &t
+	vmRestoreOut.Status.Progress = pointer.P(int32(100))
 	vmRestoreOut.Status.RestoreTime = currentTime()
 	updateRestoreCondition(vmRestoreOut, newProgressingCondition(corev1.ConditionFalse, "Operation complete"))
 	updateRestoreCondition(vmRestoreOut, newReadyCondition(corev1.ConditionTrue, "Operation complete"))
@@ -311,6 +473,167 @@ func (ctrl *VMRestoreController) updateVMRestore(vmRestoreIn *snapshotv1.Virtual
 	return 0, ctrl.doUpdateStatus(vmRestoreIn, vmRestoreOut)
 }
 
+// memoryDumpLocation returns where the guest memory dump captured by the
+// snapshot is kept, or nil if the snapshot has none. The dump is not loaded
+// on restore, it is only reported.
+func (ctrl *VMRestoreController) memoryDumpLocation(vmSnapshot *snapshotv1.VirtualMachineSnapshot) (*snapshotv1.MemoryDumpLocation, error) {
+	content, err := ctrl.getSnapshotContent(vmSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	memoryDumpBackup := content.Spec.MemoryDumpBackup
+	if memoryDumpBackup == nil {
+		return nil, nil
+	}
+
+	for _, vb := range content.Spec.VolumeBackups {
+		if vb.VolumeName == memoryDumpBackup.VolumeName && vb.VolumeSnapshotName != nil {
+			return &snapshotv1.MemoryDumpLocation{
+				VolumeSnapshotName: *vb.VolumeSnapshotName,
+				FileName:           memoryDumpBackup.FileName,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// hotplugRestoredVolumes hotplugs the volumes restored from volumes which
+// were hotplugged to the source VMI when the snapshot was taken, rather than
+// baking them into the template of the restored VM
+func (ctrl *VMRestoreController) hotplugRestoredVolumes(vmSnapshot *snapshotv1.VirtualMachineSnapshot, vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) error {
+	content, err := ctrl.getSnapshotContent(vmSnapshot)
+	if err != nil {
+		return err
+	}
+
+	vm := target.VirtualMachine()
+	attached := sets.NewString()
+	if vm.Spec.Template != nil {
+		for _, volume := range vm.Spec.Template.Spec.Volumes {
+			attached.Insert(volume.Name)
+		}
+	}
+	for _, request := range vm.Status.VolumeRequests {
+		if request.AddVolumeOptions != nil {
+			attached.Insert(request.AddVolumeOptions.Name)
+		}
+	}
+
+	for _, vb := range content.Spec.VolumeBackups {
+		if !vb.Hotplug || attached.Has(vb.VolumeName) {
+			continue
+		}
+
+		for _, vr := range vmRestore.Status.Restores {
+			if vr.VolumeName != vb.VolumeName {
+				continue
+			}
+
+			log.Log.Object(vmRestore).Infof("hotplugging restored volume %s to VM %s/%s", vr.VolumeName, vm.Namespace, vm.Name)
+			err := ctrl.Client.VirtualMachine(vm.Namespace).AddVolume(context.Background(), vm.Name, &kubevirtv1.AddVolumeOptions{
+				Name: vr.VolumeName,
+				Disk: restoredHotplugDisk(vb),
+				VolumeSource: &kubevirtv1.HotplugVolumeSource{
+					PersistentVolumeClaim: &kubevirtv1.PersistentVolumeClaimVolumeSource{
+						PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: vr.PersistentVolumeClaimName,
+						},
+						Hotpluggable: true,
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// restoredHotplugDisk returns the disk of the source VMI the volume was
+// hotplugged with, falling back to a SCSI disk for content captured before
+// the disk was recorded
+func restoredHotplugDisk(vb snapshotv1.VolumeBackup) *kubevirtv1.Disk {
+	if vb.Disk != nil {
+		disk := vb.Disk.DeepCopy()
+		disk.Name = vb.VolumeName
+		return disk
+	}
+
+	return &kubevirtv1.Disk{
+		Name: vb.VolumeName,
+		DiskDevice: kubevirtv1.DiskDevice{
+			Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusSCSI},
+		},
+	}
+}
+
+// restoredResourcesSummary describes the VM, PVCs and DataVolumes a restore
+// produced, for the completion event
+func restoredResourcesSummary(vmRestore *snapshotv1.VirtualMachineRestore, vm *kubevirtv1.VirtualMachine) string {
+	var pvcNames, dvNames []string
+	for _, restore := range vmRestore.Status.Restores {
+		pvcNames = append(pvcNames, restore.PersistentVolumeClaimName)
+		if restore.DataVolumeName != nil {
+			dvNames = append(dvNames, *restore.DataVolumeName)
+		}
+	}
+
+	return fmt.Sprintf("restored VirtualMachine %s with %d PVC(s) [%s] and %d DataVolume(s) [%s]",
+		vm.Name, len(pvcNames), strings.Join(pvcNames, ", "), len(dvNames), strings.Join(dvNames, ", "))
+}
+
+// accessCredentialSecrets returns the secrets referenced by the VM access
+// credentials. Secrets are not part of the snapshot, and virt-controller is
+// not allowed to read them, so the user is reminded to provide them before
+// the restored VM boots
+func accessCredentialSecrets(vm *kubevirtv1.VirtualMachine) []string {
+	if vm == nil || vm.Spec.Template == nil {
+		return nil
+	}
+
+	var secrets []string
+	for _, accessCredential := range vm.Spec.Template.Spec.AccessCredentials {
+		switch {
+		case accessCredential.SSHPublicKey != nil && accessCredential.SSHPublicKey.Source.Secret != nil:
+			secrets = append(secrets, accessCredential.SSHPublicKey.Source.Secret.SecretName)
+		case accessCredential.UserPassword != nil && accessCredential.UserPassword.Source.Secret != nil:
+			secrets = append(secrets, accessCredential.UserPassword.Source.Secret.SecretName)
+		}
+	}
+
+	return secrets
+}
+
+func placementPreserved(vmRestore *snapshotv1.VirtualMachineRestore) bool {
+	return vmRestore.Spec.PlacementPolicy == nil || *vmRestore.Spec.PlacementPolicy == snapshotv1.PlacementPolicyPreserve
+}
+
+// unschedulablePlacement describes why the nodeSelector of the restored VM
+// matches no node of the cluster, it returns an empty string otherwise
+func (ctrl *VMRestoreController) unschedulablePlacement(vm *kubevirtv1.VirtualMachine) (string, error) {
+	if vm == nil || vm.Spec.Template == nil || len(vm.Spec.Template.Spec.NodeSelector) == 0 {
+		return "", nil
+	}
+
+	selector := labels.SelectorFromSet(vm.Spec.Template.Spec.NodeSelector)
+	matches := 0
+	err := cache.ListAll(ctrl.NodeInformer.GetStore(), selector, func(interface{}) {
+		matches++
+	})
+	if err != nil {
+		return "", err
+	}
+	if matches > 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("No node matches the nodeSelector %s of the restored VM", selector.String()), nil
+}
+
 func (ctrl *VMRestoreController) doUpdateError(restore *snapshotv1.VirtualMachineRestore, err error) error {
 	if updateErr := ctrl.doUpdateErrorWithFailure(restore, err.Error(), false); updateErr != nil {
 		return updateErr
@@ -385,7 +708,9 @@ func (ctrl *VMRestoreController) handleVMRestoreDeletion(vmRestore *snapshotv1.V
 	return err
 }
 
-func (ctrl *VMRestoreController) handleVMRestoreTargetNotReady(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) error {
+// handleVMRestoreTargetNotReady applies the TargetReadinessPolicy of a restore
+// whose target is not ready, and returns when the target should be checked again
+func (ctrl *VMRestoreController) handleVMRestoreTargetNotReady(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) (time.Duration, error) {
 	vmRestoreCpy := vmRestore.DeepCopy()
 
 	// Default targetReadinessPolicy is having a grace period for the user the make
@@ -396,38 +721,51 @@ func (ctrl *VMRestoreController) handleVMRestoreTargetNotReady(vmRestore *snapsh
 	}
 
 	var reason, eventMsg string
+	var retry time.Duration
 
 	switch targetReadinessPolicy {
 	case snapshotv1.VirtualMachineRestoreWaitEventually:
+		// there is no timeout, keep checking until the target is ready
 		reason = waitEventuallyMessage
 		eventMsg = vmiExistsEventMessage
+		retry = targetNotReadyRetryInterval
 	case snapshotv1.VirtualMachineRestoreStopTarget:
-		return ctrl.stopTarget(vmRestore, target)
+		return targetNotReadyRetryInterval, ctrl.stopTarget(vmRestore, target)
 	case snapshotv1.VirtualMachineRestoreWaitGracePeriodAndFail:
-		if vmRestoreTargetReadyGracePeriodExceeded(vmRestore) {
-			return ctrl.doUpdateErrorWithFailure(vmRestore, restoreGracePeriodExceededError, true)
+		remaining := timeUntilTargetReadyGracePeriodExceeded(vmRestore)
+		if remaining < 0 {
+			return 0, ctrl.doUpdateErrorWithFailure(vmRestore, targetNotReadyReason(targetReadinessPolicy, restoreGracePeriodExceededError), true)
 		}
 
 		reason = waitGracePeriodMessage
 		eventMsg = vmiExistsEventMessage
+		retry = min(remaining, targetNotReadyRetryInterval)
 	case snapshotv1.VirtualMachineRestoreFailImmediate:
-		return ctrl.doUpdateErrorWithFailure(vmRestore, targetNotReadyFailureMessage, true)
+		return 0, ctrl.doUpdateErrorWithFailure(vmRestore, targetNotReadyReason(targetReadinessPolicy, targetNotReadyFailureMessage), true)
 	default:
-		return fmt.Errorf("unknown targetReadinessPolicy: %v", targetReadinessPolicy)
+		return 0, fmt.Errorf("unknown targetReadinessPolicy: %v", targetReadinessPolicy)
 	}
 
+	reason = targetNotReadyReason(targetReadinessPolicy, reason)
 	ctrl.Recorder.Event(vmRestoreCpy, corev1.EventTypeWarning, restoreVMNotReadyEvent, eventMsg)
 	updateRestoreCondition(vmRestoreCpy, newProgressingCondition(corev1.ConditionFalse, reason))
 	updateRestoreCondition(vmRestoreCpy, newReadyCondition(corev1.ConditionFalse, reason))
 
-	return ctrl.doUpdateStatus(vmRestore, vmRestoreCpy)
+	return retry, ctrl.doUpdateStatus(vmRestore, vmRestoreCpy)
+}
+
+// targetNotReadyReason names the TargetReadinessPolicy in effect in the
+// reason a restore is waiting for, or failed because of, its target
+func targetNotReadyReason(policy snapshotv1.TargetReadinessPolicy, reason string) string {
+	return fmt.Sprintf("%s (targetReadinessPolicy %s)", reason, policy)
 }
 
 func (ctrl *VMRestoreController) stopTarget(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) error {
 	vmRestoreCpy := vmRestore.DeepCopy()
+	reason := targetNotReadyReason(snapshotv1.VirtualMachineRestoreStopTarget, stopTargetMessage)
 	ctrl.Recorder.Event(vmRestoreCpy, corev1.EventTypeWarning, restoreVMNotReadyEvent, stopTargetMessage)
-	updateRestoreCondition(vmRestoreCpy, newProgressingCondition(corev1.ConditionFalse, stopTargetMessage))
-	updateRestoreCondition(vmRestoreCpy, newReadyCondition(corev1.ConditionFalse, stopTargetMessage))
+	updateRestoreCondition(vmRestoreCpy, newProgressingCondition(corev1.ConditionFalse, reason))
+	updateRestoreCondition(vmRestoreCpy, newReadyCondition(corev1.ConditionFalse, reason))
 
 	// Stop the restore target
 	err := target.Stop()
@@ -438,9 +776,222 @@ func (ctrl *VMRestoreController) stopTarget(vmRestore *snapshotv1.VirtualMachine
 	return ctrl.doUpdateStatus(vmRestore, vmRestoreCpy)
 }
 
-func vmRestoreTargetReadyGracePeriodExceeded(vmRestore *snapshotv1.VirtualMachineRestore) bool {
-	deadline := vmRestore.CreationTimestamp.Add(snapshotv1.DefaultGracePeriod)
-	return time.Until(deadline) < 0
+func needsPreRestoreSnapshot(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) bool {
+	return isVolumeRestorePolicyInPlace(vmRestore) &&
+		vmRestore.Spec.AutoSnapshotBeforeInPlaceRestore != nil && *vmRestore.Spec.AutoSnapshotBeforeInPlaceRestore &&
+		target.Exists()
+}
+
+// handlePreRestoreSnapshot takes a snapshot of the existing target before an InPlace
+// restore replaces its volumes, and returns true once that snapshot succeeded
+func (ctrl *VMRestoreController) handlePreRestoreSnapshot(vmRestoreIn, vmRestoreOut *snapshotv1.VirtualMachineRestore, target restoreTarget) (bool, error) {
+	created := false
+	if vmRestoreOut.Status.PreRestoreSnapshotName == nil {
+		name, err := ctrl.createPreRestoreSnapshot(vmRestoreOut, target)
+		if err != nil {
+			return false, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+		vmRestoreOut.Status.PreRestoreSnapshotName = &name
+		created = true
+	}
+
+	name := *vmRestoreOut.Status.PreRestoreSnapshotName
+	obj, exists, err := ctrl.VMSnapshotInformer.GetStore().GetByKey(cacheKeyFunc(vmRestoreOut.Namespace, name))
+	if err != nil {
+		return false, ctrl.doUpdateError(vmRestoreIn, err)
+	}
+	var vmSnapshot *snapshotv1.VirtualMachineSnapshot
+	if exists {
+		vmSnapshot = obj.(*snapshotv1.VirtualMachineSnapshot)
+	} else if !created {
+		// the informer may not have caught up with the creation yet, so
+		// make sure the snapshot is really gone before failing the restore
+		vmSnapshot, err = ctrl.Client.VirtualMachineSnapshot(vmRestoreOut.Namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return false, ctrl.doUpdateErrorWithFailure(vmRestoreOut, fmt.Sprintf("pre-restore VirtualMachineSnapshot %s was deleted", name), true)
+		}
+		if err != nil {
+			return false, ctrl.doUpdateError(vmRestoreIn, err)
+		}
+	}
+
+	if vmSnapshot != nil {
+		if vmSnapshotDeleting(vmSnapshot) {
+			return false, ctrl.doUpdateErrorWithFailure(vmRestoreOut, fmt.Sprintf("pre-restore VirtualMachineSnapshot %s is being deleted", name), true)
+		}
+		if vmSnapshotFailed(vmSnapshot) {
+			return false, ctrl.doUpdateErrorWithFailure(vmRestoreOut, fmt.Sprintf("pre-restore VirtualMachineSnapshot %s failed", name), true)
+		}
+		if VmSnapshotReady(vmSnapshot) {
+			return true, nil
+		}
+	}
+
+	msg := fmt.Sprintf("Waiting for pre-restore VirtualMachineSnapshot %s", name)
+	updateRestoreCondition(vmRestoreOut, newProgressingCondition(corev1.ConditionTrue, msg))
+	updateRestoreCondition(vmRestoreOut, newReadyCondition(corev1.ConditionFalse, msg))
+
+	return false, ctrl.doUpdateStatus(vmRestoreIn, vmRestoreOut)
+}
+
+func (ctrl *VMRestoreController) createPreRestoreSnapshot(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) (string, error) {
+	vmSnapshot := &snapshotv1.VirtualMachineSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", preRestoreSnapshotPrefix, vmRestore.UID),
+			Namespace: vmRestore.Namespace,
+			Annotations: map[string]string{
+				RestoreNameAnnotation: vmRestore.Name,
+			},
+		},
+		Spec: snapshotv1.VirtualMachineSnapshotSpec{
+			Source: corev1.TypedLocalObjectReference{
+				APIGroup: pointer.P(kubevirtv1.VirtualMachineGroupVersionKind.Group),
+				Kind:     kubevirtv1.VirtualMachineGroupVersionKind.Kind,
+				Name:     target.VirtualMachine().Name,
+			},
+		},
+	}
+
+	_, err := ctrl.Client.VirtualMachineSnapshot(vmSnapshot.Namespace).Create(context.Background(), vmSnapshot, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return "", err
+	}
+
+	ctrl.Recorder.Eventf(
+		vmRestore,
+		corev1.EventTypeNormal,
+		restorePreRestoreSnapshotCreatedEvent,
+		"Created VirtualMachineSnapshot %s of the target before restoring in place",
+		vmSnapshot.Name,
+	)
+
+	return vmSnapshot.Name, nil
+}
+
+// missingOverrideStorageClass returns the reason the restore can't proceed
+// when a VolumeRestoreOverride requests a storage class which does not
+// exist, or an empty string if all of them exist
+func (ctrl *VMRestoreController) missingOverrideStorageClass(vmRestore *snapshotv1.VirtualMachineRestore) (string, error) {
+	for _, override := range vmRestore.Spec.VolumeRestoreOverrides {
+		if override.StorageClassName == nil {
+			continue
+		}
+
+		_, exists, err := ctrl.StorageClassInformer.GetStore().GetByKey(*override.StorageClassName)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return fmt.Sprintf("StorageClass %s requested for volume %s does not exist", *override.StorageClassName, override.VolumeName), nil
+		}
+	}
+
+	return "", nil
+}
+
+// unknownSelectedVolume returns the reason the restore can't proceed when
+// IncludeVolumes or ExcludeVolumes name a volume which the snapshot content
+// has no backup of, or an empty string if all of them are backed up
+func (ctrl *VMRestoreController) unknownSelectedVolume(vmRestore *snapshotv1.VirtualMachineRestore, vmSnapshot *snapshotv1.VirtualMachineSnapshot) (string, error) {
+	selected := append(slices.Clone(vmRestore.Spec.IncludeVolumes), vmRestore.Spec.ExcludeVolumes...)
+	if len(selected) == 0 {
+		return "", nil
+	}
+
+	content, err := ctrl.getSnapshotContent(vmSnapshot)
+	if err != nil {
+		return "", err
+	}
+
+	for _, volumeName := range selected {
+		if _, err := getRestoreVolumeBackup(volumeName, content); err != nil {
+			return fmt.Sprintf("Volume %s selected for restore is not part of snapshot %s", volumeName, vmSnapshot.Name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// unsupportedPostRestoreCloudInit returns the reason the post restore cloud-init
+// user data can't be applied to the restored VM, or an empty string if it can.
+// cloud-init only runs it under a new instance ID, which a VM restored under
+// the name of the snapshotted VM does not get.
+func (ctrl *VMRestoreController) unsupportedPostRestoreCloudInit(vmRestore *snapshotv1.VirtualMachineRestore, vmSnapshot *snapshotv1.VirtualMachineSnapshot) (string, error) {
+	if vmRestore.Spec.PostRestoreCloudInit == nil {
+		return "", nil
+	}
+
+	content, err := ctrl.getSnapshotContent(vmSnapshot)
+	if err != nil {
+		return "", err
+	}
+
+	snapshotVM := content.Spec.Source.VirtualMachine
+	if snapshotVM == nil {
+		return "", fmt.Errorf("unexpected snapshot source")
+	}
+
+	if snapshotVM.Name == vmRestore.Spec.Target.Name {
+		return fmt.Sprintf("PostRestoreCloudInit requires restoring VirtualMachine %s under a new name, cloud-init does not run it again for the same instance ID", snapshotVM.Name), nil
+	}
+
+	spec := snapshotVM.Spec.DeepCopy()
+	if err := injectPostRestoreCloudInit(spec, vmRestore.Spec.PostRestoreCloudInit); err != nil {
+		return fmt.Sprintf("PostRestoreCloudInit can't be applied: %v", err), nil
+	}
+
+	return "", nil
+}
+
+// validateSnapshotVMSpec submits the captured VM spec as a dry run create,
+// so that a spec the current API no longer accepts fails the restore before
+// anything is changed. It returns the reason the spec is incompatible, or an
+// empty string if the API accepted it.
+func (ctrl *VMRestoreController) validateSnapshotVMSpec(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget, vmSnapshot *snapshotv1.VirtualMachineSnapshot) (string, error) {
+	content, err := ctrl.getSnapshotContent(vmSnapshot)
+	if err != nil {
+		return "", err
+	}
+
+	snapshotVM := content.Spec.Source.VirtualMachine
+	if snapshotVM == nil {
+		return "", fmt.Errorf("unexpected snapshot source")
+	}
+
+	// The ControllerRevisions of instance types and preferences are only
+	// restored together with the target, so such a spec can't be checked yet
+	if snapshotVM.Spec.Instancetype != nil || snapshotVM.Spec.Preference != nil {
+		return "", nil
+	}
+
+	vm := &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: restoreValidationPrefix,
+			Namespace:    restoreTargetNamespace(vmRestore),
+		},
+		Spec: *snapshotVM.Spec.DeepCopy(),
+	}
+	remapResources(&vm.Spec, vmRestore.Spec.ResourceRemapping)
+	if err := injectPostRestoreCloudInit(&vm.Spec, vmRestore.Spec.PostRestoreCloudInit); err != nil {
+		return "", err
+	}
+	if !target.Exists() {
+		vm, err = patchVM(vm, vmRestore.Spec.Patches)
+		if err != nil {
+			return "", fmt.Errorf("error patching VM %s: %v", snapshotVM.Name, err)
+		}
+	}
+
+	_, err = ctrl.Client.VirtualMachine(vm.Namespace).Create(context.Background(), vm, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if k8serrors.IsInvalid(err) || k8serrors.IsBadRequest(err) {
+		return fmt.Sprintf("VirtualMachine spec of snapshot %s is not compatible with the current API: %v", vmSnapshot.Name, err), nil
+	}
+
+	return "", err
+}
+
+func timeUntilTargetReadyGracePeriodExceeded(vmRestore *snapshotv1.VirtualMachineRestore) time.Duration {
+	return time.Until(vmRestore.CreationTimestamp.Add(snapshotv1.DefaultGracePeriod))
 }
 
 func (ctrl *VMRestoreController) reconcileVolumeRestores(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget, vmSnapshot *snapshotv1.VirtualMachineSnapshot) (bool, error) {
@@ -456,7 +1007,7 @@ func (ctrl *VMRestoreController) reconcileVolumeRestores(vmRestore *snapshotv1.V
 
 	var restores []snapshotv1.VolumeRestore
 	for _, vb := range content.Spec.VolumeBackups {
-		if noRestore.Has(vb.VolumeName) {
+		if noRestore.Has(vb.VolumeName) || !volumeSelectedForRestore(vmRestore, vb.VolumeName) {
 			continue
 		}
 
@@ -496,11 +1047,10 @@ func (ctrl *VMRestoreController) reconcileVolumeRestores(vmRestore *snapshotv1.V
 
 	createdPVC := false
 	deletedPVC := false
-	waitingPVC := false
 	waitingDVNameUpdate := false
 
 	for i, restore := range restores {
-		pvc, err := ctrl.getPVC(vmRestore.Namespace, restore.PersistentVolumeClaimName)
+		pvc, err := ctrl.getPVC(restoreTargetNamespace(vmRestore), restore.PersistentVolumeClaimName)
 		if err != nil {
 			return false, err
 		}
@@ -532,7 +1082,7 @@ func (ctrl *VMRestoreController) reconcileVolumeRestores(vmRestore *snapshotv1.V
 			}
 
 			if ownerDV != "" {
-				log.Log.Object(vmRestore).Infof("marking datavolume %s/%s as prepopulated before deleting its PVC", vmRestore.Namespace, ownerDV)
+				log.Log.Object(vmRestore).Infof("marking datavolume %s/%s as prepopulated before deleting its PVC", restoreTargetNamespace(vmRestore), ownerDV)
 
 				// We update the status of the volume to note that it belongs to a DataVolume.
 				// We'll need this information later to restore the PVC with annotations to rebind it
@@ -543,33 +1093,162 @@ func (ctrl *VMRestoreController) reconcileVolumeRestores(vmRestore *snapshotv1.V
 					continue
 				}
 
-				if err := ctrl.prepopulateDataVolume(vmRestore.Namespace, ownerDV, vmRestore.Name); err != nil {
+				if err := ctrl.prepopulateDataVolume(restoreTargetNamespace(vmRestore), ownerDV, vmRestore.Name); err != nil {
 					return false, err
 				}
 			}
 
 			// If we're here, the PVC associated with that volume exists, and needs to be wiped before we restore in its place
-			log.Log.Object(vmRestore).Infof("deleting %s/%s to replace volume due to policy InPlace", vmRestore.Namespace, pvc.Name)
-			if err = ctrl.Client.CoreV1().PersistentVolumeClaims(vmRestore.Namespace).
+			log.Log.Object(vmRestore).Infof("deleting %s/%s to replace volume due to policy InPlace", restoreTargetNamespace(vmRestore), pvc.Name)
+			if err = ctrl.Client.CoreV1().PersistentVolumeClaims(restoreTargetNamespace(vmRestore)).
 				Delete(context.Background(), pvc.Name, metav1.DeleteOptions{}); err != nil {
 				return false, err
 			}
 
 			deletedPVC = true
-		} else if pvc.Status.Phase == corev1.ClaimPending {
-			bindingMode, err := ctrl.getBindingMode(pvc)
-			if err != nil {
-				return false, err
-			}
+		} else if pvc.Status.Phase != corev1.ClaimPending && pvc.Status.Phase != corev1.ClaimBound {
+			return false, fmt.Errorf("PVC %s/%s in status %q", pvc.Namespace, pvc.Name, pvc.Status.Phase)
+		}
+	}
+	return createdPVC || deletedPVC || waitingDVNameUpdate, nil
+}
+
+// updateRestoreProgress sets the progress of the restore from the share of
+// restored volumes which are ready, weighted by their capacity when every
+// volume has one. The progress never decreases, and only reaches 100 once
+// the restore is complete.
+func (ctrl *VMRestoreController) updateRestoreProgress(vmRestore *snapshotv1.VirtualMachineRestore, vmSnapshot *snapshotv1.VirtualMachineSnapshot) error {
+	if len(vmRestore.Status.Restores) == 0 {
+		return nil
+	}
+
+	content, err := ctrl.getSnapshotContent(vmSnapshot)
+	if err != nil {
+		return err
+	}
+
+	weights := make([]int64, len(vmRestore.Status.Restores))
+	weighByCapacity := true
+	for i, restore := range vmRestore.Status.Restores {
+		backup, err := getRestoreVolumeBackup(restore.VolumeName, content)
+		if err != nil {
+			return err
+		}
+
+		capacity, ok := backup.PersistentVolumeClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+		if !ok || capacity.Value() <= 0 {
+			weighByCapacity = false
+			break
+		}
+		weights[i] = capacity.Value()
+	}
+
+	var total, ready int64
+	for i, restore := range vmRestore.Status.Restores {
+		weight := int64(1)
+		if weighByCapacity {
+			weight = weights[i]
+		}
+		total += weight
+
+		pvc, err := ctrl.getPVC(restoreTargetNamespace(vmRestore), restore.PersistentVolumeClaimName)
+		if err != nil {
+			return err
+		}
+
+		pvcReady, err := ctrl.restorePVCReady(pvc)
+		if err != nil {
+			return err
+		}
+		if pvcReady {
+			ready += weight
+		}
+	}
+
+	// 100 is reserved for the completion of the restore
+	progress := int32(ready * 99 / total)
+	if vmRestore.Status.Progress == nil || *vmRestore.Status.Progress < progress {
+		vmRestore.Status.Progress = &progress
+	}
+
+	return nil
+}
+
+// restorePVCReady returns true if the restored PVC is bound, or is pending
+// only until its first consumer is scheduled
+func (ctrl *VMRestoreController) restorePVCReady(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc == nil {
+		return false, nil
+	}
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimBound:
+		return true, nil
+	case corev1.ClaimPending:
+		bindingMode, err := ctrl.getBindingMode(pvc)
+		if err != nil {
+			return false, err
+		}
+		return bindingMode != nil && *bindingMode == storagev1.VolumeBindingWaitForFirstConsumer, nil
+	}
+
+	return false, nil
+}
+
+// restorePVCsPendingBinding returns the restored PVCs which are still Pending
+// although they are expected to bind without a consumer. PVCs with
+// WaitForFirstConsumer binding only bind once the VM is started.
+func (ctrl *VMRestoreController) restorePVCsPendingBinding(vmRestore *snapshotv1.VirtualMachineRestore) ([]*corev1.PersistentVolumeClaim, error) {
+	var pending []*corev1.PersistentVolumeClaim
+	for _, restore := range vmRestore.Status.Restores {
+		pvc, err := ctrl.getPVC(restoreTargetNamespace(vmRestore), restore.PersistentVolumeClaimName)
+		if err != nil {
+			return nil, err
+		}
+
+		if pvc == nil || pvc.Status.Phase != corev1.ClaimPending {
+			continue
+		}
+
+		bindingMode, err := ctrl.getBindingMode(pvc)
+		if err != nil {
+			return nil, err
+		}
 
-			if bindingMode == nil || *bindingMode == storagev1.VolumeBindingImmediate {
-				waitingPVC = true
-			}
-		} else if pvc.Status.Phase != corev1.ClaimBound {
-			return false, fmt.Errorf("PVC %s/%s in status %q", pvc.Namespace, pvc.Name, pvc.Status.Phase)
+		if bindingMode == nil || *bindingMode == storagev1.VolumeBindingImmediate {
+			pending = append(pending, pvc)
+		}
+	}
+	return pending, nil
+}
+
+func (ctrl *VMRestoreController) handleRestorePVCsPendingBinding(vmRestoreIn, vmRestoreOut *snapshotv1.VirtualMachineRestore, pvcs []*corev1.PersistentVolumeClaim) (time.Duration, error) {
+	var (
+		requeue  time.Duration
+		notBound []string
+	)
+	for _, pvc := range pvcs {
+		remaining := time.Until(pvc.CreationTimestamp.Add(restorePVCBindingTimeout))
+		if remaining <= 0 {
+			notBound = append(notBound, fmt.Sprintf("%s/%s", pvc.Namespace, pvc.Name))
+			continue
 		}
+		if requeue == 0 || remaining < requeue {
+			requeue = remaining
+		}
+	}
+
+	pvc := pvcs[0]
+	reason := fmt.Sprintf("Waiting for PVC %s/%s to be bound", pvc.Namespace, pvc.Name)
+	if len(notBound) > 0 {
+		// PVC updates trigger a new reconcile, keep waiting for them
+		reason = fmt.Sprintf("Waiting for PVC %s which is not bound after %s", strings.Join(notBound, ", "), restorePVCBindingTimeout)
+		ctrl.Recorder.Event(vmRestoreOut, corev1.EventTypeWarning, restorePVCNotBoundEvent, reason)
 	}
-	return createdPVC || deletedPVC || waitingPVC || waitingDVNameUpdate, nil
+	updateRestoreCondition(vmRestoreOut, newProgressingCondition(corev1.ConditionTrue, reason))
+	updateRestoreCondition(vmRestoreOut, newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs to be bound"))
+
+	return requeue, ctrl.doUpdateStatus(vmRestoreIn, vmRestoreOut)
 }
 
 func (ctrl *VMRestoreController) getBindingMode(pvc *corev1.PersistentVolumeClaim) (*storagev1.VolumeBindingMode, error) {
@@ -767,7 +1446,7 @@ func (t *vmRestoreTarget) removeBackendLabelFromPVC(pvc *corev1.PersistentVolume
 func (t *vmRestoreTarget) updateRestorePVCWithBackendLabel(originalPVC *corev1.PersistentVolumeClaim) (bool, error) {
 	for _, vr := range t.vmRestore.Status.Restores {
 		if vr.VolumeName == storageutils.BackendPVCVolumeName(t.vmRestore.Spec.Target.Name) {
-			restorePVC, err := t.controller.getPVC(t.vmRestore.Namespace, vr.PersistentVolumeClaimName)
+			restorePVC, err := t.controller.getPVC(restoreTargetNamespace(t.vmRestore), vr.PersistentVolumeClaimName)
 			if err != nil {
 				return false, err
 			}
@@ -831,6 +1510,40 @@ func (t *vmRestoreTarget) getSnapshotVM() (*snapshotv1.VirtualMachine, error) {
 	return snapshotVM, nil
 }
 
+// InvalidPatch applies the patches of a restore which creates its target to
+// the restored VM in memory, and returns why the first patch which can't be
+// applied, or changes the apiVersion or kind of the VM, is invalid
+func (t *vmRestoreTarget) InvalidPatch() (string, error) {
+	if t.Exists() || len(t.vmRestore.Spec.Patches) == 0 {
+		return "", nil
+	}
+
+	snapshotVM, err := t.getSnapshotVM()
+	if err != nil {
+		return "", err
+	}
+
+	restoredVM, err := t.generateRestoredVMSpec(snapshotVM)
+	if err != nil {
+		return "", err
+	}
+	restoredVM.APIVersion = kubevirtv1.VirtualMachineGroupVersionKind.GroupVersion().String()
+	restoredVM.Kind = kubevirtv1.VirtualMachineGroupVersionKind.Kind
+
+	for i, patch := range t.vmRestore.Spec.Patches {
+		patchedVM, err := patchVM(restoredVM, []string{patch})
+		if err != nil {
+			return fmt.Sprintf("Patch %d is invalid: %v", i, err), nil
+		}
+		if patchedVM.APIVersion != restoredVM.APIVersion || patchedVM.Kind != restoredVM.Kind {
+			return fmt.Sprintf("Patch %d changes the apiVersion or kind of the restore target %s", i, restoredVM.Kind), nil
+		}
+		restoredVM = patchedVM
+	}
+
+	return "", nil
+}
+
 func (t *vmRestoreTarget) updateVMRestoreRestores(snapshotVM *snapshotv1.VirtualMachine) (bool, error) {
 	var restores = make([]snapshotv1.VolumeRestore, len(t.vmRestore.Status.Restores))
 	for i, t := range t.vmRestore.Status.Restores {
@@ -848,17 +1561,19 @@ func (t *vmRestoreTarget) updateVMRestoreRestores(snapshotVM *snapshotv1.Virtual
 			if volume.Name != restore.VolumeName {
 				continue
 			}
-			if volume.DataVolume != nil {
+			// restored PVCs adopted by a DataVolume would be owned by the
+			// VM through its DataVolumeTemplates
+			if volume.DataVolume != nil && !isVolumeOwnershipPolicyNone(t.vmRestore) {
 				templateIndex := findDVTemplateIndex(volume.DataVolume.Name, snapshotVM)
 				if templateIndex >= 0 {
 					dvName := restoreDVName(t.vmRestore, restore.VolumeName, volume.DataVolume.Name)
-					pvc, err := t.controller.getPVC(t.vmRestore.Namespace, restore.PersistentVolumeClaimName)
+					pvc, err := t.controller.getPVC(restoreTargetNamespace(t.vmRestore), restore.PersistentVolumeClaimName)
 					if err != nil {
 						return false, err
 					}
 
 					if pvc == nil {
-						return false, fmt.Errorf("pvc %s/%s does not exist and should", t.vmRestore.Namespace, restore.PersistentVolumeClaimName)
+						return false, fmt.Errorf("pvc %s/%s does not exist and should", restoreTargetNamespace(t.vmRestore), restore.PersistentVolumeClaimName)
 					}
 
 					if err = t.updatePVCPopulatedForAnnotation(pvc, dvName); err != nil {
@@ -885,6 +1600,11 @@ func (t *vmRestoreTarget) generateRestoredVMSpec(snapshotVM *snapshotv1.VirtualM
 	log.Log.Object(t.vmRestore).V(3).Info("generating restored VM spec")
 	var newTemplates = make([]kubevirtv1.DataVolumeTemplateSpec, len(snapshotVM.Spec.DataVolumeTemplates))
 	var newVolumes []kubevirtv1.Volume
+	// templates and volumes of the snapshot which are not restored, and the
+	// templates of the target which back the volumes kept in their place
+	var keptTemplates []kubevirtv1.DataVolumeTemplateSpec
+	skippedTemplates := sets.NewInt()
+	droppedVolumes := sets.NewString()
 
 	for i, t := range snapshotVM.Spec.DataVolumeTemplates {
 		t.DeepCopyInto(&newTemplates[i])
@@ -898,6 +1618,27 @@ func (t *vmRestoreTarget) generateRestoredVMSpec(snapshotVM *snapshotv1.VirtualM
 	}
 
 	for _, v := range volumes {
+		if restorableVolume(&v) && !volumeSelectedForRestore(t.vmRestore, v.Name) {
+			if v.DataVolume != nil {
+				if templateIndex := findDVTemplateIndex(v.DataVolume.Name, snapshotVM); templateIndex >= 0 {
+					skippedTemplates.Insert(templateIndex)
+				}
+			}
+
+			targetVolume := t.targetVolume(v.Name)
+			if targetVolume == nil {
+				droppedVolumes.Insert(v.Name)
+				continue
+			}
+			if targetVolume.DataVolume != nil {
+				if dvt := t.targetDVTemplate(targetVolume.DataVolume.Name); dvt != nil {
+					keptTemplates = append(keptTemplates, *dvt)
+				}
+			}
+			newVolumes = append(newVolumes, *targetVolume)
+			continue
+		}
+
 		nv := v.DeepCopy()
 		if nv.DataVolume != nil || nv.PersistentVolumeClaim != nil {
 			for _, vr := range t.vmRestore.Status.Restores {
@@ -911,6 +1652,12 @@ func (t *vmRestoreTarget) generateRestoredVMSpec(snapshotVM *snapshotv1.VirtualM
 				}
 
 				templateIndex := findDVTemplateIndex(v.DataVolume.Name, snapshotVM)
+				if templateIndex >= 0 && vr.DataVolumeName == nil && isVolumeOwnershipPolicyNone(t.vmRestore) {
+					// the restored PVC is not owned by any entity, drop the
+					// template which would adopt it
+					skippedTemplates.Insert(templateIndex)
+					templateIndex = -1
+				}
 				if templateIndex >= 0 {
 					if vr.DataVolumeName == nil {
 						return nil, fmt.Errorf("DataVolumeName for dv %s should have been updated already", v.DataVolume.Name)
@@ -918,6 +1665,7 @@ func (t *vmRestoreTarget) generateRestoredVMSpec(snapshotVM *snapshotv1.VirtualM
 
 					dv := snapshotVM.Spec.DataVolumeTemplates[templateIndex].DeepCopy()
 					dv.Name = *vr.DataVolumeName
+					applyDataVolumeTemplateOverride(dv, vr.VolumeName, t.vmRestore.Spec.VolumeRestoreOverrides)
 					newTemplates[templateIndex] = *dv
 
 					nv.DataVolume.Name = *vr.DataVolumeName
@@ -935,6 +1683,12 @@ func (t *vmRestoreTarget) generateRestoredVMSpec(snapshotVM *snapshotv1.VirtualM
 					}
 				}
 			}
+		} else if nv.Ephemeral != nil && nv.Ephemeral.PersistentVolumeClaim != nil {
+			for _, vr := range t.vmRestore.Status.Restores {
+				if vr.VolumeName == nv.Name {
+					nv.Ephemeral.PersistentVolumeClaim.ClaimName = vr.PersistentVolumeClaimName
+				}
+			}
 		} else if nv.MemoryDump != nil {
 			// don't restore memory dump volume in the new spec
 			continue
@@ -947,7 +1701,7 @@ func (t *vmRestoreTarget) generateRestoredVMSpec(snapshotVM *snapshotv1.VirtualM
 		newVM = &kubevirtv1.VirtualMachine{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:        t.vmRestore.Spec.Target.Name,
-				Namespace:   t.vmRestore.Namespace,
+				Namespace:   restoreTargetNamespace(t.vmRestore),
 				Labels:      snapshotVM.Labels,
 				Annotations: snapshotVM.Annotations,
 			},
@@ -976,16 +1730,295 @@ func (t *vmRestoreTarget) generateRestoredVMSpec(snapshotVM *snapshotv1.VirtualM
 		}
 	}
 
-	newVM.Spec.DataVolumeTemplates = newTemplates
+	if policy := t.vmRestore.Spec.MetadataRestorePolicy; policy != nil {
+		applyMetadataRestorePolicy(policy, snapshotVM, newVM, t.Exists())
+	}
+
+	restoredTemplates := make([]kubevirtv1.DataVolumeTemplateSpec, 0, len(newTemplates)+len(keptTemplates))
+	for i, dvt := range newTemplates {
+		if !skippedTemplates.Has(i) {
+			restoredTemplates = append(restoredTemplates, dvt)
+		}
+	}
+	newVM.Spec.DataVolumeTemplates = append(restoredTemplates, keptTemplates...)
 	newVM.Spec.Template.Spec.Volumes = newVolumes
+	removeVolumeDevices(&newVM.Spec, droppedVolumes)
+	remapResources(&newVM.Spec, t.vmRestore.Spec.ResourceRemapping)
+	if err := injectPostRestoreCloudInit(&newVM.Spec, t.vmRestore.Spec.PostRestoreCloudInit); err != nil {
+		return nil, err
+	}
+	if policy := t.vmRestore.Spec.PlacementPolicy; policy != nil && *policy == snapshotv1.PlacementPolicyClear {
+		clearPlacement(&newVM.Spec)
+	}
 	setLastRestoreAnnotation(t.vmRestore, newVM)
 	if snapshotVM.Name == newVM.Name {
 		setLegacyFirmwareUUID(newVM)
+	} else if t.vmRestore.Spec.PostRestoreCloudInit != nil {
+		// cloud-init uses the firmware UUID as instance ID, a new one makes
+		// it run the post restore user data on the first boot
+		if newVM.Spec.Template.Spec.Domain.Firmware != nil {
+			newVM.Spec.Template.Spec.Domain.Firmware.UUID = ""
+		}
+		setLegacyFirmwareUUID(newVM)
 	}
 
 	return newVM, nil
 }
 
+// targetVolume returns the volume of the existing target with the given name
+func (t *vmRestoreTarget) targetVolume(name string) *kubevirtv1.Volume {
+	if !t.Exists() {
+		return nil
+	}
+	for _, volume := range t.vm.Spec.Template.Spec.Volumes {
+		if volume.Name == name {
+			return volume.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// targetDVTemplate returns the DataVolumeTemplate of the existing target with the given name
+func (t *vmRestoreTarget) targetDVTemplate(name string) *kubevirtv1.DataVolumeTemplateSpec {
+	if !t.Exists() {
+		return nil
+	}
+	for _, dvt := range t.vm.Spec.DataVolumeTemplates {
+		if dvt.Name == name {
+			return dvt.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// removeVolumeDevices removes the disks and filesystems of the given volumes,
+// so that the spec does not reference volumes which were left out of it
+func removeVolumeDevices(spec *kubevirtv1.VirtualMachineSpec, volumeNames sets.String) {
+	if volumeNames.Len() == 0 {
+		return
+	}
+
+	devices := &spec.Template.Spec.Domain.Devices
+	devices.Disks = slices.DeleteFunc(devices.Disks, func(disk kubevirtv1.Disk) bool {
+		return volumeNames.Has(disk.Name)
+	})
+	devices.Filesystems = slices.DeleteFunc(devices.Filesystems, func(filesystem kubevirtv1.Filesystem) bool {
+		return volumeNames.Has(filesystem.Name)
+	})
+}
+
+// applyMetadataRestorePolicy sets the snapshotted VM labels and annotations selected by
+// the policy on the restored VM. An existing target keeps its own metadata and only gets
+// the selected keys merged in, a new target gets exactly the selected keys.
+func applyMetadataRestorePolicy(policy *snapshotv1.MetadataRestorePolicy, snapshotVM *snapshotv1.VirtualMachine, newVM *kubevirtv1.VirtualMachine, exists bool) {
+	labels := selectRestoredMetadata(policy, snapshotVM.Labels, policy.LabelKeys, nil)
+	annotations := selectRestoredMetadata(policy, snapshotVM.Annotations, policy.AnnotationKeys, restoreStrippedVMAnnotations)
+
+	if !exists {
+		newVM.Labels = labels
+		newVM.Annotations = annotations
+		return
+	}
+
+	if len(labels) > 0 {
+		if newVM.Labels == nil {
+			newVM.Labels = make(map[string]string)
+		}
+		maps.Copy(newVM.Labels, labels)
+	}
+	if len(annotations) > 0 {
+		if newVM.Annotations == nil {
+			newVM.Annotations = make(map[string]string)
+		}
+		maps.Copy(newVM.Annotations, annotations)
+	}
+}
+
+// remapResources points the Secret and ConfigMap references of the restored VM spec
+// to the targets given in the restore resource remapping
+func remapResources(spec *kubevirtv1.VirtualMachineSpec, remappings []snapshotv1.ResourceRemapping) {
+	if len(remappings) == 0 {
+		return
+	}
+
+	storageutils.VisitResourceReferences(spec, func(kind snapshotv1.ResourceRemappingKind, name *string) {
+		for _, remapping := range remappings {
+			if remapping.Kind == kind && remapping.SourceName == *name {
+				*name = remapping.TargetName
+				return
+			}
+		}
+	})
+}
+
+// injectPostRestoreCloudInit adds the user data to the cloud-init volume of the
+// restored VM spec as a part of a MIME multipart message which keeps its
+// original user data, or adds a NoCloud volume with it when the spec has none
+func injectPostRestoreCloudInit(spec *kubevirtv1.VirtualMachineSpec, userData *string) error {
+	if userData == nil || spec.Template == nil {
+		return nil
+	}
+
+	for i := range spec.Template.Spec.Volumes {
+		volume := &spec.Template.Spec.Volumes[i]
+		switch {
+		case volume.CloudInitNoCloud != nil:
+			source := volume.CloudInitNoCloud
+			return mergeCloudInitUserData(&source.UserData, &source.UserDataBase64, source.UserDataSecretRef, *userData)
+		case volume.CloudInitConfigDrive != nil:
+			source := volume.CloudInitConfigDrive
+			return mergeCloudInitUserData(&source.UserData, &source.UserDataBase64, source.UserDataSecretRef, *userData)
+		}
+	}
+
+	spec.Template.Spec.Volumes = append(spec.Template.Spec.Volumes, kubevirtv1.Volume{
+		Name: postRestoreCloudInitVolumeName,
+		VolumeSource: kubevirtv1.VolumeSource{
+			CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{
+				UserData: *userData,
+			},
+		},
+	})
+	spec.Template.Spec.Domain.Devices.Disks = append(spec.Template.Spec.Domain.Devices.Disks, kubevirtv1.Disk{
+		Name: postRestoreCloudInitVolumeName,
+		DiskDevice: kubevirtv1.DiskDevice{
+			Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusVirtio},
+		},
+	})
+
+	return nil
+}
+
+// mergeCloudInitUserData adds the post restore user data to the inline or base64
+// encoded user data of a cloud-init volume, keeping the field it was set in
+func mergeCloudInitUserData(userData, userDataBase64 *string, secretRef *corev1.LocalObjectReference, postRestore string) error {
+	switch {
+	case secretRef != nil:
+		return fmt.Errorf("user data from Secret %s can't be merged with the post restore cloud-init user data", secretRef.Name)
+	case *userDataBase64 != "":
+		decoded, err := base64.StdEncoding.DecodeString(*userDataBase64)
+		if err != nil {
+			return fmt.Errorf("error decoding cloud-init user data: %v", err)
+		}
+		merged, err := multipartUserData(string(decoded), postRestore)
+		if err != nil {
+			return err
+		}
+		*userDataBase64 = base64.StdEncoding.EncodeToString([]byte(merged))
+	case *userData != "":
+		merged, err := multipartUserData(*userData, postRestore)
+		if err != nil {
+			return err
+		}
+		*userData = merged
+	default:
+		*userData = postRestore
+	}
+
+	return nil
+}
+
+// multipartUserData builds a MIME multipart message cloud-init processes part
+// by part, with the parts of the original user data followed by the post
+// restore one. The boundary is derived from the content so the result is
+// the same on every reconcile.
+func multipartUserData(original, postRestore string) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.SetBoundary(fmt.Sprintf("%x", sha256.Sum256([]byte(original+postRestore)))); err != nil {
+		return "", err
+	}
+
+	if err := writeUserDataParts(writer, original); err != nil {
+		return "", err
+	}
+	if err := writeUserDataPart(writer, plainTextPartHeader(), postRestore); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\nMIME-Version: 1.0\n\n%s", writer.Boundary(), body.String()), nil
+}
+
+// writeUserDataParts copies the parts of user data which already is a MIME
+// multipart message, or adds the user data as a single part otherwise
+func writeUserDataParts(writer *multipart.Writer, userData string) error {
+	msg, err := mail.ReadMessage(strings.NewReader(userData))
+	if err != nil {
+		return writeUserDataPart(writer, plainTextPartHeader(), userData)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return writeUserDataPart(writer, plainTextPartHeader(), userData)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading cloud-init user data: %v", err)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return fmt.Errorf("error reading cloud-init user data: %v", err)
+		}
+		if err := writeUserDataPart(writer, part.Header, string(content)); err != nil {
+			return err
+		}
+	}
+}
+
+func writeUserDataPart(writer *multipart.Writer, header textproto.MIMEHeader, content string) error {
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+// plainTextPartHeader lets cloud-init detect the type of the part from its
+// first line, e.g. #cloud-config or #!
+func plainTextPartHeader() textproto.MIMEHeader {
+	return textproto.MIMEHeader{"Content-Type": {`text/plain; charset="utf-8"`}}
+}
+
+// clearPlacement removes the placement constraints of the restored VM spec so
+// it can be scheduled on any node
+func clearPlacement(spec *kubevirtv1.VirtualMachineSpec) {
+	if spec.Template == nil {
+		return
+	}
+
+	spec.Template.Spec.NodeSelector = nil
+	spec.Template.Spec.Affinity = nil
+	spec.Template.Spec.Tolerations = nil
+}
+
+func selectRestoredMetadata(policy *snapshotv1.MetadataRestorePolicy, source map[string]string, keys, stripped []string) map[string]string {
+	if policy.Type == snapshotv1.MetadataRestorePolicyNone {
+		return nil
+	}
+
+	selected := make(map[string]string)
+	for k, v := range source {
+		if slices.Contains(stripped, k) {
+			continue
+		}
+		if policy.Type == snapshotv1.MetadataRestorePolicySelective && !slices.Contains(keys, k) {
+			continue
+		}
+		selected[k] = v
+	}
+
+	return selected
+}
+
 func (t *vmRestoreTarget) reconcileSpec(restoredVM *kubevirtv1.VirtualMachine) (bool, error) {
 	log.Log.Object(t.vmRestore).V(3).Info("Reconcile new VM spec")
 
@@ -999,7 +2032,7 @@ func (t *vmRestoreTarget) reconcileSpec(restoredVM *kubevirtv1.VirtualMachine) (
 		if err != nil {
 			return false, fmt.Errorf("error patching VM %s: %v", restoredVM.Name, err)
 		}
-		restoredVM, err = t.controller.Client.VirtualMachine(t.vmRestore.Namespace).Create(context.Background(), restoredVM, metav1.CreateOptions{})
+		restoredVM, err = t.controller.Client.VirtualMachine(restoreTargetNamespace(t.vmRestore)).Create(context.Background(), restoredVM, metav1.CreateOptions{})
 	} else {
 		restoredVM, err = t.controller.Client.VirtualMachine(restoredVM.Namespace).Update(context.Background(), restoredVM, metav1.UpdateOptions{})
 	}
@@ -1026,7 +2059,7 @@ func (t *vmRestoreTarget) updateRestorePVCOwnership() error {
 	}
 	for _, volume := range t.VirtualMachine().Spec.Template.Spec.Volumes {
 		if volume.PersistentVolumeClaim != nil {
-			pvc, err := t.controller.Client.CoreV1().PersistentVolumeClaims(t.vmRestore.Namespace).Get(context.Background(), volume.PersistentVolumeClaim.ClaimName, metav1.GetOptions{})
+			pvc, err := t.controller.Client.CoreV1().PersistentVolumeClaims(restoreTargetNamespace(t.vmRestore)).Get(context.Background(), volume.PersistentVolumeClaim.ClaimName, metav1.GetOptions{})
 			if err != nil {
 				return err
 			}
@@ -1193,8 +2226,17 @@ func (t *vmRestoreTarget) restoreInstancetypeControllerRevision(vmSnapshotRevisi
 }
 
 func (t *vmRestoreTarget) restoreInstancetypeControllerRevisions(vm *kubevirtv1.VirtualMachine) error {
+	vmSnapshotName := t.vmRestore.Spec.VirtualMachineSnapshotName
+	if t.vmRestore.Spec.VirtualMachineSnapshotContentName != nil {
+		vmSnapshot, err := t.controller.getVMSnapshot(t.vmRestore)
+		if err != nil {
+			return err
+		}
+		vmSnapshotName = vmSnapshot.Name
+	}
+
 	if vm.Spec.Instancetype != nil && vm.Spec.Instancetype.RevisionName != "" {
-		restoredCR, err := t.restoreInstancetypeControllerRevision(vm.Spec.Instancetype.RevisionName, t.vmRestore.Spec.VirtualMachineSnapshotName, vm)
+		restoredCR, err := t.restoreInstancetypeControllerRevision(vm.Spec.Instancetype.RevisionName, vmSnapshotName, vm)
 		if err != nil {
 			return err
 		}
@@ -1202,7 +2244,7 @@ func (t *vmRestoreTarget) restoreInstancetypeControllerRevisions(vm *kubevirtv1.
 	}
 
 	if vm.Spec.Preference != nil && vm.Spec.Preference.RevisionName != "" {
-		restoredCR, err := t.restoreInstancetypeControllerRevision(vm.Spec.Preference.RevisionName, t.vmRestore.Spec.VirtualMachineSnapshotName, vm)
+		restoredCR, err := t.restoreInstancetypeControllerRevision(vm.Spec.Preference.RevisionName, vmSnapshotName, vm)
 		if err != nil {
 			return err
 		}
@@ -1251,7 +2293,7 @@ func (t *vmRestoreTarget) createDataVolume(restoredVM *kubevirtv1.VirtualMachine
 	}
 	if pvc == nil {
 		return false, fmt.Errorf("when creating restore dv pvc %s/%s does not exist and should",
-			t.vmRestore.Namespace, dvt.Name)
+			restoreTargetNamespace(t.vmRestore), dvt.Name)
 	}
 	if pvc.Annotations[populatedForPVCAnnotation] != dvt.Name || len(pvc.OwnerReferences) > 0 {
 		return false, nil
@@ -1266,6 +2308,7 @@ func (t *vmRestoreTarget) createDataVolume(restoredVM *kubevirtv1.VirtualMachine
 		newDataVolume.Annotations = make(map[string]string)
 	}
 	newDataVolume.Annotations[RestoreNameAnnotation] = t.vmRestore.Name
+	setRestoreNamespaceAnnotation(t.vmRestore, newDataVolume)
 	newDataVolume.Annotations[cdiv1.AnnPrePopulated] = "true"
 
 	if _, err = t.controller.Client.CdiClient().CdiV1beta1().DataVolumes(restoredVM.Namespace).Create(context.Background(), newDataVolume, metav1.CreateOptions{}); err != nil {
@@ -1297,13 +2340,13 @@ func (t *vmRestoreTarget) Own(obj metav1.Object) {
 
 func (ctrl *VMRestoreController) deleteObsoleteVolumes(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) error {
 	for _, dvName := range vmRestore.Status.DeletedDataVolumes {
-		objKey := cacheKeyFunc(vmRestore.Namespace, dvName)
+		objKey := cacheKeyFunc(restoreTargetNamespace(vmRestore), dvName)
 		_, exists, err := ctrl.DataVolumeInformer.GetStore().GetByKey(objKey)
 		if err != nil {
 			return err
 		}
 		if exists {
-			err = ctrl.Client.CdiClient().CdiV1beta1().DataVolumes(vmRestore.Namespace).
+			err = ctrl.Client.CdiClient().CdiV1beta1().DataVolumes(restoreTargetNamespace(vmRestore)).
 				Delete(context.Background(), dvName, metav1.DeleteOptions{})
 			if err != nil {
 				return err
@@ -1323,7 +2366,7 @@ func (ctrl *VMRestoreController) deleteObsoleteVolumes(vmRestore *snapshotv1.Vir
 func (ctrl *VMRestoreController) deleteObsoleteBackendPVC(vmRestore *snapshotv1.VirtualMachineRestore, target restoreTarget) error {
 	// Target should always exist at this point, just nil check for safety.
 	if target.Exists() && backendstorage.IsBackendStorageNeeded(target.VirtualMachine()) {
-		pvcs, err := ctrl.Client.CoreV1().PersistentVolumeClaims(vmRestore.Namespace).List(context.Background(), metav1.ListOptions{
+		pvcs, err := ctrl.Client.CoreV1().PersistentVolumeClaims(restoreTargetNamespace(vmRestore)).List(context.Background(), metav1.ListOptions{
 			LabelSelector: fmt.Sprintf("%s=%s", restoreCleanupBackendPVCLabel, getCleanupLabelValue(vmRestore)),
 		})
 		if err != nil {
@@ -1360,6 +2403,10 @@ func sourceAndTargetAreDifferent(target restoreTarget, vmSnapshot *snapshotv1.Vi
 }
 
 func (ctrl *VMRestoreController) getVMSnapshot(vmRestore *snapshotv1.VirtualMachineRestore) (*snapshotv1.VirtualMachineSnapshot, error) {
+	if vmRestore.Spec.VirtualMachineSnapshotContentName != nil {
+		return ctrl.getVMSnapshotFromContent(vmRestore)
+	}
+
 	objKey := cacheKeyFunc(vmRestore.Namespace, vmRestore.Spec.VirtualMachineSnapshotName)
 	obj, exists, err := ctrl.VMSnapshotInformer.GetStore().GetByKey(objKey)
 	if err != nil {
@@ -1383,8 +2430,45 @@ func (ctrl *VMRestoreController) getVMSnapshot(vmRestore *snapshotv1.VirtualMach
 	return vmSnapshot, nil
 }
 
+// getVMSnapshotFromContent builds the VirtualMachineSnapshot a directly
+// referenced VirtualMachineSnapshotContent stands for, so restoring from a
+// content replicated from another cluster does not need the snapshot object
+func (ctrl *VMRestoreController) getVMSnapshotFromContent(vmRestore *snapshotv1.VirtualMachineRestore) (*snapshotv1.VirtualMachineSnapshot, error) {
+	contentName := *vmRestore.Spec.VirtualMachineSnapshotContentName
+	content, err := ctrl.getSnapshotContentByName(vmRestore.Namespace, contentName)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotVM := content.Spec.Source.VirtualMachine
+	if snapshotVM == nil {
+		return nil, fmt.Errorf("unexpected snapshot source")
+	}
+
+	vmSnapshotName := vmRestore.Spec.VirtualMachineSnapshotName
+	if content.Spec.VirtualMachineSnapshotName != nil {
+		vmSnapshotName = *content.Spec.VirtualMachineSnapshotName
+	}
+
+	return &snapshotv1.VirtualMachineSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vmSnapshotName,
+			Namespace: vmRestore.Namespace,
+		},
+		Status: &snapshotv1.VirtualMachineSnapshotStatus{
+			SourceUID:                         &snapshotVM.UID,
+			VirtualMachineSnapshotContentName: &contentName,
+			ReadyToUse:                        content.Status.ReadyToUse,
+		},
+	}, nil
+}
+
 func (ctrl *VMRestoreController) getSnapshotContent(vmSnapshot *snapshotv1.VirtualMachineSnapshot) (*snapshotv1.VirtualMachineSnapshotContent, error) {
-	objKey := cacheKeyFunc(vmSnapshot.Namespace, *vmSnapshot.Status.VirtualMachineSnapshotContentName)
+	return ctrl.getSnapshotContentByName(vmSnapshot.Namespace, *vmSnapshot.Status.VirtualMachineSnapshotContentName)
+}
+
+func (ctrl *VMRestoreController) getSnapshotContentByName(namespace, name string) (*snapshotv1.VirtualMachineSnapshotContent, error) {
+	objKey := cacheKeyFunc(namespace, name)
 	obj, exists, err := ctrl.VMSnapshotContentInformer.GetStore().GetByKey(objKey)
 	if err != nil {
 		return nil, err
@@ -1479,7 +2563,7 @@ func (ctrl *VMRestoreController) getTarget(vmRestore *snapshotv1.VirtualMachineR
 	vmRestore.Spec.Target.DeepCopy()
 	switch vmRestore.Spec.Target.Kind {
 	case "VirtualMachine":
-		vm, err := ctrl.getVM(vmRestore.Namespace, vmRestore.Spec.Target.Name)
+		vm, err := ctrl.getVM(restoreTargetNamespace(vmRestore), vmRestore.Spec.Target.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -1527,6 +2611,9 @@ func (ctrl *VMRestoreController) createRestorePVC(
 	if err != nil {
 		return err
 	}
+	if err := ctrl.checkRestoreProvisioning(vmRestore, pvc); err != nil {
+		return err
+	}
 	if pvc.Annotations == nil {
 		pvc.Annotations = make(map[string]string)
 	}
@@ -1542,7 +2629,7 @@ func (ctrl *VMRestoreController) createRestorePVC(
 		}
 	}
 
-	_, err = ctrl.Client.CoreV1().PersistentVolumeClaims(vmRestore.Namespace).Create(context.Background(), pvc, metav1.CreateOptions{})
+	_, err = ctrl.Client.CoreV1().PersistentVolumeClaims(restoreTargetNamespace(vmRestore)).Create(context.Background(), pvc, metav1.CreateOptions{})
 	if err != nil {
 		return err
 	}
@@ -1550,6 +2637,45 @@ func (ctrl *VMRestoreController) createRestorePVC(
 	return nil
 }
 
+// checkRestoreProvisioning warns when the StorageClass of a restored PVC
+// does not declare support for the requested provisioning hint, in which
+// case the provisioner is free to ignore it
+func (ctrl *VMRestoreController) checkRestoreProvisioning(vmRestore *snapshotv1.VirtualMachineRestore, pvc *corev1.PersistentVolumeClaim) error {
+	provisioning, ok := pvc.Annotations[restoreProvisioningAnnotation]
+	if !ok {
+		return nil
+	}
+
+	storageClassName := ""
+	if pvc.Spec.StorageClassName != nil {
+		storageClassName = *pvc.Spec.StorageClassName
+		obj, exists, err := ctrl.StorageClassInformer.GetStore().GetByKey(storageClassName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			sc := obj.(*storagev1.StorageClass)
+			for _, supported := range strings.Split(sc.Annotations[storageClassProvisioningAnnotation], ",") {
+				if strings.TrimSpace(supported) == provisioning {
+					return nil
+				}
+			}
+		}
+	}
+
+	ctrl.Recorder.Eventf(
+		vmRestore,
+		corev1.EventTypeWarning,
+		restoreProvisioningUnsupportedEvent,
+		"StorageClass %q of PVC %s may not honor the %s provisioning hint",
+		storageClassName,
+		pvc.Name,
+		provisioning,
+	)
+
+	return nil
+}
+
 func sourcePVCOwnedBySourceVM(volumeBackup *snapshotv1.VolumeBackup, sourceVm *snapshotv1.VirtualMachine) bool {
 	ownerReferences := volumeBackup.PersistentVolumeClaim.OwnerReferences
 	owned := false
@@ -1660,6 +2786,14 @@ func CreateRestorePVCDefFromVMRestore(vmRestore *snapshotv1.VirtualMachineRestor
 	pvc.Labels[restoreSourceNameLabel] = sourceVmName
 	pvc.Labels[restoreSourceNamespaceLabel] = sourceVmNamespace
 	pvc.Annotations[RestoreNameAnnotation] = vmRestore.Name
+	setRestoreNamespaceAnnotation(vmRestore, pvc)
+
+	// A PVC in another namespace can only reference the VolumeSnapshot
+	// through a cross-namespace dataSourceRef
+	if restoreTargetNamespace(vmRestore) != vmRestore.Namespace {
+		pvc.Spec.DataSource = nil
+		pvc.Spec.DataSourceRef.Namespace = pointer.P(vmRestore.Namespace)
+	}
 
 	// Mark the ID of the restore job on the PVC
 	// Used to determine if the PVC has already been deleted for InPlace restores
@@ -1695,6 +2829,22 @@ func (ctrl *VMRestoreController) volumesNotForRestore(content *snapshotv1.Virtua
 	return noRestore, nil
 }
 
+// volumeSelectedForRestore returns whether IncludeVolumes and ExcludeVolumes
+// of the restore select the volume to be restored
+func volumeSelectedForRestore(vmRestore *snapshotv1.VirtualMachineRestore, volumeName string) bool {
+	if len(vmRestore.Spec.IncludeVolumes) > 0 {
+		return slices.Contains(vmRestore.Spec.IncludeVolumes, volumeName)
+	}
+	return !slices.Contains(vmRestore.Spec.ExcludeVolumes, volumeName)
+}
+
+// restorableVolume returns whether the volume is backed by a PVC which the
+// restore can replace with one restored from the snapshot
+func restorableVolume(volume *kubevirtv1.Volume) bool {
+	return volume.DataVolume != nil || volume.PersistentVolumeClaim != nil ||
+		(volume.Ephemeral != nil && volume.Ephemeral.PersistentVolumeClaim != nil)
+}
+
 func getRestoreVolumeBackup(volName string, content *snapshotv1.VirtualMachineSnapshotContent) (*snapshotv1.VolumeBackup, error) {
 	for _, vb := range content.Spec.VolumeBackups {
 		if vb.VolumeName == volName {
@@ -1731,6 +2881,19 @@ func applyVolumeRestoreOverride(restorePVC *corev1.PersistentVolumeClaim, volume
 			if restorePVC.Annotations != nil && override.Annotations != nil {
 				maps.Copy(restorePVC.Annotations, override.Annotations)
 			}
+
+			if restorePVC.Annotations != nil && override.Provisioning != nil {
+				restorePVC.Annotations[restoreProvisioningAnnotation] = string(*override.Provisioning)
+			}
+
+			// Override where the volume lands
+			if override.StorageClassName != nil {
+				restorePVC.Spec.StorageClassName = pointer.P(*override.StorageClassName)
+			}
+
+			if len(override.AccessModes) > 0 {
+				restorePVC.Spec.AccessModes = slices.Clone(override.AccessModes)
+			}
 			break
 		}
 	}
@@ -1738,6 +2901,36 @@ func applyVolumeRestoreOverride(restorePVC *corev1.PersistentVolumeClaim, volume
 	return nil
 }
 
+// applyDataVolumeTemplateOverride applies the storage class and access modes
+// a volume is restored with to the DataVolumeTemplate adopting its PVC, so
+// the template matches the restored PVC
+func applyDataVolumeTemplateOverride(dvt *kubevirtv1.DataVolumeTemplateSpec, volumeName string, overrides []snapshotv1.VolumeRestoreOverride) {
+	for _, override := range overrides {
+		if override.VolumeName != volumeName {
+			continue
+		}
+
+		if pvc := dvt.Spec.PVC; pvc != nil {
+			if override.StorageClassName != nil {
+				pvc.StorageClassName = pointer.P(*override.StorageClassName)
+			}
+			if len(override.AccessModes) > 0 {
+				pvc.AccessModes = slices.Clone(override.AccessModes)
+			}
+		}
+
+		if storage := dvt.Spec.Storage; storage != nil {
+			if override.StorageClassName != nil {
+				storage.StorageClassName = pointer.P(*override.StorageClassName)
+			}
+			if len(override.AccessModes) > 0 {
+				storage.AccessModes = slices.Clone(override.AccessModes)
+			}
+		}
+		return
+	}
+}
+
 // isVolumeRestorePolicyInPlace determines if the VolumeRestorePolicy is set to "InPlace"
 // If this is the case, we'll have to try to restore the volumes over the original ones, which means
 // deleting the original volumes first, if they already exist.
@@ -1749,6 +2942,31 @@ func isVolumeRestorePolicyInPlace(vmRestore *snapshotv1.VirtualMachineRestore) b
 	return *vmRestore.Spec.VolumeRestorePolicy == snapshotv1.VolumeRestorePolicyInPlace
 }
 
+// restoreTargetNamespace returns the namespace the restored VM and its
+// volumes are created in, which defaults to the namespace of the restore
+func restoreTargetNamespace(vmRestore *snapshotv1.VirtualMachineRestore) string {
+	if vmRestore.Spec.TargetNamespace != nil && *vmRestore.Spec.TargetNamespace != "" {
+		return *vmRestore.Spec.TargetNamespace
+	}
+
+	return vmRestore.Namespace
+}
+
+// setRestoreNamespaceAnnotation records the namespace of the restore on an
+// object created in another namespace
+func setRestoreNamespaceAnnotation(vmRestore *snapshotv1.VirtualMachineRestore, obj metav1.Object) {
+	if restoreTargetNamespace(vmRestore) == vmRestore.Namespace {
+		return
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[RestoreNamespaceAnnotation] = vmRestore.Namespace
+	obj.SetAnnotations(annotations)
+}
+
 // prepopulateDataVolume marks a DataVolume as already populated, effectively blocking it
 // from creating new PVCs. This function is useful when deleting the PVCs associated with DVs
 // during a restore process, as we want to create the new PVCs ourselves and don't want the CDI
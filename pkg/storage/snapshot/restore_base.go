@@ -24,6 +24,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -53,6 +54,7 @@ type VMRestoreController struct {
 	PVCInformer               cache.SharedIndexInformer
 	StorageClassInformer      cache.SharedIndexInformer
 	CRInformer                cache.SharedIndexInformer
+	NodeInformer              cache.SharedIndexInformer
 
 	VolumeSnapshotProvider VolumeSnapshotProvider
 
@@ -100,6 +102,17 @@ func (ctrl *VMRestoreController) Init() error {
 		return err
 	}
 
+	_, err = ctrl.VMSnapshotInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.handleVMSnapshot,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.handleVMSnapshot(newObj) },
+			DeleteFunc: ctrl.handleVMSnapshot,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
 	_, err = ctrl.VMInformer.AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    ctrl.handleVM,
@@ -130,6 +143,7 @@ func (ctrl *VMRestoreController) Run(threadiness int, stopCh <-chan struct{}) er
 		ctrl.VMIInformer.HasSynced,
 		ctrl.DataVolumeInformer.HasSynced,
 		ctrl.PVCInformer.HasSynced,
+		ctrl.NodeInformer.HasSynced,
 	) {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
@@ -194,7 +208,7 @@ func (ctrl *VMRestoreController) handleDataVolume(obj interface{}) {
 			return
 		}
 
-		objName := cacheKeyFunc(dv.Namespace, restoreName)
+		objName := cacheKeyFunc(restoreNamespace(dv), restoreName)
 
 		log.Log.V(3).Infof("Handling DV %s/%s, Restore %s", dv.Namespace, dv.Name, objName)
 		ctrl.vmRestoreQueue.Add(objName)
@@ -212,13 +226,41 @@ func (ctrl *VMRestoreController) handlePVC(obj interface{}) {
 			return
 		}
 
-		objName := cacheKeyFunc(pvc.Namespace, restoreName)
+		objName := cacheKeyFunc(restoreNamespace(pvc), restoreName)
 
 		log.Log.V(3).Infof("Handling PVC %s/%s, Restore %s", pvc.Namespace, pvc.Name, objName)
 		ctrl.vmRestoreQueue.Add(objName)
 	}
 }
 
+// restoreNamespace returns the namespace of the restore an object was
+// created by, which differs from its own for cross-namespace restores
+func restoreNamespace(obj metav1.Object) string {
+	if namespace, ok := obj.GetAnnotations()[RestoreNamespaceAnnotation]; ok {
+		return namespace
+	}
+
+	return obj.GetNamespace()
+}
+
+func (ctrl *VMRestoreController) handleVMSnapshot(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+
+	if vmSnapshot, ok := obj.(*snapshotv1.VirtualMachineSnapshot); ok {
+		restoreName, ok := vmSnapshot.Annotations[RestoreNameAnnotation]
+		if !ok {
+			return
+		}
+
+		objName := cacheKeyFunc(vmSnapshot.Namespace, restoreName)
+
+		log.Log.V(3).Infof("Handling VMSnapshot %s/%s, Restore %s", vmSnapshot.Namespace, vmSnapshot.Name, objName)
+		ctrl.vmRestoreQueue.Add(objName)
+	}
+}
+
 func (ctrl *VMRestoreController) handleVM(obj interface{}) {
 	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
 		obj = unknown.Obj
@@ -21,7 +21,12 @@ package snapshot
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
 	"reflect"
 	"strings"
 	"time"
@@ -33,6 +38,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -274,6 +280,7 @@ var _ = Describe("Restore controller", func() {
 			pvcInformer, _ := testutils.NewFakeInformerFor(&corev1.PersistentVolumeClaim{})
 			storageClassInformer, _ := testutils.NewFakeInformerFor(&storagev1.StorageClass{})
 			crInformer, _ := testutils.NewFakeInformerWithIndexersFor(&appsv1.ControllerRevision{}, virtcontroller.GetControllerRevisionInformerIndexers())
+			nodeInformer, _ := testutils.NewFakeInformerFor(&corev1.Node{})
 
 			recorder = record.NewFakeRecorder(100)
 			recorder.IncludeObject = true
@@ -295,6 +302,7 @@ var _ = Describe("Restore controller", func() {
 				Recorder:                  recorder,
 				VolumeSnapshotProvider:    fakeVolumeSnapshotProvider,
 				CRInformer:                crInformer,
+				NodeInformer:              nodeInformer,
 			}
 			controller.Init()
 
@@ -463,8 +471,8 @@ var _ = Describe("Restore controller", func() {
 				rc2.Status = &snapshotv1.VirtualMachineRestoreStatus{
 					Complete: pointer.P(false),
 					Conditions: []snapshotv1.Condition{
-						newProgressingCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore, or the operation will fail after 5m0s"),
-						newReadyCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore, or the operation will fail after 5m0s"),
+						newProgressingCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore, or the operation will fail after 5m0s (targetReadinessPolicy WaitGracePeriod)"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore, or the operation will fail after 5m0s (targetReadinessPolicy WaitGracePeriod)"),
 					},
 				}
 				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc2)
@@ -540,6 +548,207 @@ var _ = Describe("Restore controller", func() {
 				Expect(*updateStatusCalls).To(Equal(1))
 			})
 
+			It("should fail if the snapshot VM spec is not accepted by the current API", func() {
+				r := createRestoreWithOwner()
+				vm := createRestoreInProgressVM()
+				invalidErr := k8serrors.NewInvalid(schema.GroupKind{Group: "kubevirt.io", Kind: "VirtualMachine"}, "", nil)
+				kubevirtClient.Fake.PrependReactor("create", "virtualmachines", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					create, ok := action.(testing.CreateAction)
+					Expect(ok).To(BeTrue())
+					Expect(create.GetObject().(*kubevirtv1.VirtualMachine).GenerateName).To(Equal(restoreValidationPrefix))
+					return true, nil, invalidErr
+				})
+
+				errMsg := fmt.Sprintf("VirtualMachine spec of snapshot %s is not compatible with the current API: %v", vmSnapshotName, invalidErr)
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, errMsg),
+						newReadyCondition(corev1.ConditionFalse, errMsg),
+						newFailureCondition(corev1.ConditionTrue, errMsg),
+					},
+				}
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "Operation failed")
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should fail if an overridden storage class does not exist", func() {
+				r := createRestoreWithOwner()
+				r.Spec.VolumeRestoreOverrides = []snapshotv1.VolumeRestoreOverride{
+					{
+						VolumeName:       diskName,
+						StorageClassName: pointer.P("nonexistent"),
+					},
+				}
+				vm := createRestoreInProgressVM()
+
+				errMsg := fmt.Sprintf("StorageClass nonexistent requested for volume %s does not exist", diskName)
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, errMsg),
+						newReadyCondition(corev1.ConditionFalse, errMsg),
+						newFailureCondition(corev1.ConditionTrue, errMsg),
+					},
+				}
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "Operation failed")
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should fail if a volume selected for restore is not part of the snapshot", func() {
+				r := createRestoreWithOwner()
+				r.Spec.IncludeVolumes = []string{"unknown"}
+				vm := createRestoreInProgressVM()
+
+				errMsg := fmt.Sprintf("Volume unknown selected for restore is not part of snapshot %s", s.Name)
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, errMsg),
+						newReadyCondition(corev1.ConditionFalse, errMsg),
+						newFailureCondition(corev1.ConditionTrue, errMsg),
+					},
+				}
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "Operation failed")
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should fail if post restore cloud-init is set for a restore under the same name", func() {
+				r := createRestoreWithOwner()
+				r.Spec.PostRestoreCloudInit = pointer.P("#cloud-config\nhostname: restored\n")
+				vm := createRestoreInProgressVM()
+
+				errMsg := fmt.Sprintf("PostRestoreCloudInit requires restoring VirtualMachine %s under a new name, cloud-init does not run it again for the same instance ID", vmName)
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, errMsg),
+						newReadyCondition(corev1.ConditionFalse, errMsg),
+						newFailureCondition(corev1.ConditionTrue, errMsg),
+					},
+				}
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "Operation failed")
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should fail if a patch can't be applied to the new VM", func() {
+				r := createRestoreWithOwner()
+				r.Spec.Target.Name = "nonexistent-vm"
+				missingPathPatch := `{"op": "replace", "path": "/spec/template/spec/nonexistent/path", "value": "value"}`
+				r.Spec.Patches = []string{missingPathPatch}
+
+				errMsg := fmt.Sprintf("Patch 0 is invalid: failed to apply patch for VM [\n%s\n]: replace operation does not apply: doc is missing path: /spec/template/spec/nonexistent/path: missing value", missingPathPatch)
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, errMsg),
+						newReadyCondition(corev1.ConditionFalse, errMsg),
+						newFailureCondition(corev1.ConditionTrue, errMsg),
+					},
+				}
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "Operation failed")
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			DescribeTable("should only create volume restores for the selected volumes", func(includeVolumes, excludeVolumes []string, expectedVolumes []string) {
+				r := createRestoreWithOwner()
+				r.Spec.IncludeVolumes = includeVolumes
+				r.Spec.ExcludeVolumes = excludeVolumes
+				vm := createRestoreInProgressVM()
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				target, err := controller.getTarget(r)
+				Expect(err).ToNot(HaveOccurred())
+
+				updated, err := controller.reconcileVolumeRestores(r, target, s)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updated).To(Equal(len(expectedVolumes) > 0))
+
+				var restoredVolumes []string
+				for _, restore := range r.Status.Restores {
+					restoredVolumes = append(restoredVolumes, restore.VolumeName)
+				}
+				Expect(restoredVolumes).To(Equal(expectedVolumes))
+			},
+				Entry("all volumes without a selection", nil, nil, []string{diskName}),
+				Entry("included volumes", []string{diskName}, nil, []string{diskName}),
+				Entry("no excluded volumes", nil, []string{diskName}, nil),
+			)
+
+			It("should restore from a directly referenced snapshot content when the snapshot does not exist", func() {
+				r := createRestoreWithOwner()
+				r.Spec.VirtualMachineSnapshotContentName = &sc.Name
+				vm := createRestoreInProgressVM()
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+					},
+				}
+				addInitialVolumeRestores(rc)
+				Expect(controller.VMSnapshotInformer.GetStore().Delete(s)).To(Succeed())
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should error if the directly referenced snapshot content is not ready", func() {
+				r := createRestoreWithOwner()
+				r.Spec.VirtualMachineSnapshotContentName = &sc.Name
+				vm := createRestoreInProgressVM()
+				expectedError := fmt.Sprintf("VMSnapshotContent %s/%s not ready", sc.Namespace, sc.Name)
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, expectedError),
+						newReadyCondition(corev1.ConditionFalse, expectedError),
+					},
+				}
+				sc.Status.ReadyToUse = pointer.P(false)
+				Expect(controller.VMSnapshotContentInformer.GetStore().Update(sc)).To(Succeed())
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "VirtualMachineRestoreError")
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
 			It("should return error if volumesnapshot doesnt exist", func() {
 				r := createRestoreWithOwner()
 				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
@@ -591,6 +800,127 @@ var _ = Describe("Restore controller", func() {
 				Expect(*calls).To(Equal(1))
 			})
 
+			It("should create restore PVCs with the overridden storage class and access modes", func() {
+				r := createRestoreWithOwner()
+				r.Spec.VolumeRestoreOverrides = []snapshotv1.VolumeRestoreOverride{
+					{
+						VolumeName:       diskName,
+						StorageClassName: pointer.P("cheap"),
+						AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					},
+				}
+				vm := createRestoreInProgressVM()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+					},
+				}
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				addVolumeRestores(r)
+				vs := createVolumeSnapshot(r.Status.Restores[0].VolumeSnapshotName, resource.MustParse("2Gi"))
+				fakeVolumeSnapshotProvider.Add(vs)
+
+				calls := 0
+				k8sClient.Fake.PrependReactor("create", "persistentvolumeclaims", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					create, ok := action.(testing.CreateAction)
+					Expect(ok).To(BeTrue())
+
+					pvc := create.GetObject().(*corev1.PersistentVolumeClaim)
+					Expect(pvc.Spec.StorageClassName).To(HaveValue(Equal("cheap")))
+					Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+
+					calls++
+					return true, pvc, nil
+				})
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				Expect(calls).To(Equal(1))
+			})
+
+			It("should create restore PVCs in the target namespace", func() {
+				const targetNamespace = "staging"
+
+				r := createRestoreWithOwner()
+				r.Spec.TargetNamespace = pointer.P(targetNamespace)
+				vm := createRestoreInProgressVM()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+					},
+				}
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				addVolumeRestores(r)
+				vs := createVolumeSnapshot(r.Status.Restores[0].VolumeSnapshotName, resource.MustParse("2Gi"))
+				fakeVolumeSnapshotProvider.Add(vs)
+
+				calls := 0
+				k8sClient.Fake.PrependReactor("create", "persistentvolumeclaims", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					create, ok := action.(testing.CreateAction)
+					Expect(ok).To(BeTrue())
+					Expect(create.GetNamespace()).To(Equal(targetNamespace))
+
+					pvc := create.GetObject().(*corev1.PersistentVolumeClaim)
+					Expect(pvc.Spec.DataSource).To(BeNil())
+					Expect(pvc.Spec.DataSourceRef.Namespace).To(HaveValue(Equal(testNamespace)))
+					Expect(pvc.Annotations).To(HaveKeyWithValue(RestoreNamespaceAnnotation, testNamespace))
+
+					calls++
+					return true, pvc, nil
+				})
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				Expect(calls).To(Equal(1))
+			})
+
+			DescribeTable("should pass the provisioning hint to restored PVCs", func(supportedProvisioning string, expectWarning bool) {
+				Expect(controller.StorageClassInformer.GetStore().Add(&storagev1.StorageClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: *sc.Spec.VolumeBackups[0].PersistentVolumeClaim.Spec.StorageClassName,
+						Annotations: map[string]string{
+							storageClassProvisioningAnnotation: supportedProvisioning,
+						},
+					},
+				})).To(Succeed())
+
+				r := createRestoreWithOwner()
+				r.Spec.VolumeRestoreOverrides = []snapshotv1.VolumeRestoreOverride{
+					{
+						VolumeName:   diskName,
+						Provisioning: pointer.P(snapshotv1.VolumeProvisioningThin),
+					},
+				}
+				vm := createRestoreInProgressVM()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+					},
+				}
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				addVolumeRestores(r)
+				vs := createVolumeSnapshot(r.Status.Restores[0].VolumeSnapshotName, resource.MustParse("2Gi"))
+				fakeVolumeSnapshotProvider.Add(vs)
+				calls := expectPVCCreatesWithMetadata(k8sClient, r, nil, map[string]string{
+					restoreProvisioningAnnotation: string(snapshotv1.VolumeProvisioningThin),
+				})
+				addVirtualMachineRestore(r)
+				controller.processVMRestoreWorkItem()
+				Expect(*calls).To(Equal(1))
+				if expectWarning {
+					testutils.ExpectEvent(recorder, restoreProvisioningUnsupportedEvent)
+				} else {
+					Expect(recorder.Events).ToNot(Receive(ContainSubstring(restoreProvisioningUnsupportedEvent)))
+				}
+			},
+				Entry("supported by the storage class", "Thin,Thick", false),
+				Entry("not supported by the storage class", "Thick", true),
+			)
+
 			It("should create pvcs for both datavolume and pvc restore volumes", func() {
 				r := createRestoreWithOwner()
 				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
@@ -720,14 +1050,103 @@ var _ = Describe("Restore controller", func() {
 				}
 				addVolumeRestores(r)
 
+				ur := r.DeepCopy()
+				ur.ResourceVersion = "1"
+				ur.Status.Progress = pointer.P(int32(0))
+				ur.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionTrue, fmt.Sprintf("Waiting for PVC %s/%s to be bound", testNamespace, r.Status.Restores[0].PersistentVolumeClaimName)),
+					newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs to be bound"),
+				}
+
+				vm := createRestoreInProgressVM()
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				addVirtualMachineRestore(r)
+				for _, pvc := range getRestorePVCs(r) {
+					pvc.CreationTimestamp = metav1.Now()
+					pvc.Status.Phase = corev1.ClaimPending
+					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+				}
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+				controller.processVMRestoreWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			Context("restore progress", func() {
+				var r *snapshotv1.VirtualMachineRestore
+
+				BeforeEach(func() {
+					backup := sc.Spec.VolumeBackups[0]
+					backup.PersistentVolumeClaim.Spec.Resources.Requests = corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Gi"),
+					}
+					largeBackup := *backup.DeepCopy()
+					largeBackup.VolumeName = "disk2"
+					largeBackup.PersistentVolumeClaim.Spec.Resources.Requests = corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("3Gi"),
+					}
+					sc.Spec.VolumeBackups = []snapshotv1.VolumeBackup{backup, largeBackup}
+					Expect(controller.VMSnapshotContentInformer.GetStore().Update(sc)).To(Succeed())
+
+					r = createRestoreWithOwner()
+					addVolumeRestores(r)
+					r.Status.Restores = append(r.Status.Restores, snapshotv1.VolumeRestore{
+						VolumeName:                "disk2",
+						PersistentVolumeClaimName: "restore-uid-disk2",
+						VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-disk2",
+					})
+					for _, pvc := range getRestorePVCs(r) {
+						pvc.Status.Phase = corev1.ClaimPending
+						if pvc.Name == "restore-uid-disk2" {
+							pvc.Status.Phase = corev1.ClaimBound
+						}
+						Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+					}
+				})
+
+				It("should weight the progress by the capacity of the restored volumes", func() {
+					Expect(controller.updateRestoreProgress(r, s)).To(Succeed())
+					Expect(r.Status.Progress).To(HaveValue(Equal(int32(74))))
+				})
+
+				It("should never decrease the progress", func() {
+					r.Status.Progress = pointer.P(int32(80))
+					Expect(controller.updateRestoreProgress(r, s)).To(Succeed())
+					Expect(r.Status.Progress).To(HaveValue(Equal(int32(80))))
+				})
+			})
+
+			It("should keep waiting and warn when a restored PVC is not bound in time", func() {
+				r := createRestoreWithOwner()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete: pointer.P(false),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+					},
+				}
+				addVolumeRestores(r)
+
+				reason := fmt.Sprintf("Waiting for PVC %s/%s which is not bound after 10m0s", testNamespace, r.Status.Restores[0].PersistentVolumeClaimName)
+				ur := r.DeepCopy()
+				ur.ResourceVersion = "1"
+				ur.Status.Progress = pointer.P(int32(0))
+				ur.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionTrue, reason),
+					newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs to be bound"),
+				}
+
 				vm := createRestoreInProgressVM()
 				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
 				addVirtualMachineRestore(r)
 				for _, pvc := range getRestorePVCs(r) {
+					pvc.CreationTimestamp = metav1.NewTime(time.Now().Add(-restorePVCBindingTimeout))
 					pvc.Status.Phase = corev1.ClaimPending
 					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
 				}
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
 				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "RestorePVCNotBound")
+				Expect(*updateStatusCalls).To(Equal(1))
 			})
 
 			It("should keep existing VM runstrategy as before the restore", func() {
@@ -746,6 +1165,7 @@ var _ = Describe("Restore controller", func() {
 				}
 				ur := r.DeepCopy()
 				ur.ResourceVersion = "1"
+				ur.Status.Progress = pointer.P(int32(99))
 				ur.Status.Conditions = []snapshotv1.Condition{
 					newProgressingCondition(corev1.ConditionTrue, "Updating target spec"),
 					newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
@@ -862,38 +1282,182 @@ var _ = Describe("Restore controller", func() {
 					},
 				}
 
-				pvc := getRestorePVCs(r)[0]
-				pvc.OwnerReferences = []metav1.OwnerReference{
-					*metav1.NewControllerRef(dv, schema.GroupVersionKind{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "DataVolume"}),
-				}
+				pvc := getRestorePVCs(r)[0]
+				pvc.OwnerReferences = []metav1.OwnerReference{
+					*metav1.NewControllerRef(dv, schema.GroupVersionKind{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "DataVolume"}),
+				}
+
+				Expect(controller.DataVolumeInformer.GetStore().Add(dv)).To(Succeed())
+				Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+
+				ur := r.DeepCopy()
+				ur.ResourceVersion = "1"
+				ur.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
+					newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+				}
+				ur.Status.Restores = []snapshotv1.VolumeRestore{
+					{
+						VolumeName:                diskName,
+						PersistentVolumeClaimName: "alpine-dv", // Name is identical to the original PVC
+						VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-disk1",
+						DataVolumeName:            pointer.P("alpine-dv"),
+					},
+				}
+
+				addVirtualMachineRestore(r)
+
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+				patchDataVolumeCalls := expectDataVolumePatch(cdiClient, dv, dv)
+				deletePVCCalls := expectPVCDeletion(k8sClient, r)
+				controller.processVMRestoreWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))    // Restore is updated to mark DV owns the PVC
+				Expect(*patchDataVolumeCalls).To(Equal(0)) // DV not set to prepopulated yet
+				Expect(*deletePVCCalls).To(Equal(0))       // PVC hasn't been deleted yet
+			})
+
+			Context("with AutoSnapshotBeforeInPlaceRestore", func() {
+				preRestoreSnapshotName := fmt.Sprintf("pre-restore-%s", uid)
+				waitingMsg := fmt.Sprintf("Waiting for pre-restore VirtualMachineSnapshot %s", preRestoreSnapshotName)
+
+				createAutoSnapshotRestore := func() *snapshotv1.VirtualMachineRestore {
+					r := createRestoreWithOwner()
+					r.Spec.VolumeRestorePolicy = pointer.P(snapshotv1.VolumeRestorePolicyInPlace)
+					r.Spec.AutoSnapshotBeforeInPlaceRestore = pointer.P(true)
+					return r
+				}
+
+				createPreRestoreSnapshot := func(phase snapshotv1.VirtualMachineSnapshotPhase, ready bool) *snapshotv1.VirtualMachineSnapshot {
+					s := createSnapshotWith(phase, ready)
+					s.Name = preRestoreSnapshotName
+					return s
+				}
+
+				It("should snapshot the target before restoring", func() {
+					r := createAutoSnapshotRestore()
+					Expect(controller.VMInformer.GetStore().Add(createSnapshotVM())).To(Succeed())
+
+					ur := r.DeepCopy()
+					ur.ResourceVersion = "1"
+					ur.Status.PreRestoreSnapshotName = &preRestoreSnapshotName
+					ur.Status.Conditions = []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, waitingMsg),
+						newReadyCondition(corev1.ConditionFalse, waitingMsg),
+					}
+
+					updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+					addVirtualMachineRestore(r)
+					controller.processVMRestoreWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+					testutils.ExpectEvent(recorder, restorePreRestoreSnapshotCreatedEvent)
+
+					vmSnapshot, err := kubevirtClient.SnapshotV1beta1().VirtualMachineSnapshots(testNamespace).Get(context.Background(), preRestoreSnapshotName, metav1.GetOptions{})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vmSnapshot.Spec.Source.Kind).To(Equal("VirtualMachine"))
+					Expect(vmSnapshot.Spec.Source.Name).To(Equal(vmName))
+					Expect(vmSnapshot.Annotations).To(HaveKeyWithValue(RestoreNameAnnotation, vmRestoreName))
+				})
+
+				It("should not snapshot a target which does not exist", func() {
+					r := createAutoSnapshotRestore()
+					addVirtualMachineRestore(r)
+					controller.processVMRestoreWorkItem()
+
+					_, err := kubevirtClient.SnapshotV1beta1().VirtualMachineSnapshots(testNamespace).Get(context.Background(), preRestoreSnapshotName, metav1.GetOptions{})
+					Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				})
+
+				It("should wait for the pre-restore snapshot to succeed", func() {
+					r := createAutoSnapshotRestore()
+					r.Status.PreRestoreSnapshotName = &preRestoreSnapshotName
+					Expect(controller.VMInformer.GetStore().Add(createSnapshotVM())).To(Succeed())
+					Expect(controller.VMSnapshotInformer.GetStore().Add(createPreRestoreSnapshot(snapshotv1.InProgress, false))).To(Succeed())
+
+					ur := r.DeepCopy()
+					ur.ResourceVersion = "1"
+					ur.Status.Conditions = []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, waitingMsg),
+						newReadyCondition(corev1.ConditionFalse, waitingMsg),
+					}
+
+					updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+					addVirtualMachineRestore(r)
+					controller.processVMRestoreWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+				})
+
+				It("should fail if the pre-restore snapshot failed", func() {
+					r := createAutoSnapshotRestore()
+					r.Status.PreRestoreSnapshotName = &preRestoreSnapshotName
+					Expect(controller.VMInformer.GetStore().Add(createSnapshotVM())).To(Succeed())
+					Expect(controller.VMSnapshotInformer.GetStore().Add(createPreRestoreSnapshot(snapshotv1.Failed, false))).To(Succeed())
+
+					errMsg := fmt.Sprintf("pre-restore VirtualMachineSnapshot %s failed", preRestoreSnapshotName)
+					ur := r.DeepCopy()
+					ur.ResourceVersion = "1"
+					ur.Status.Conditions = []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, errMsg),
+						newReadyCondition(corev1.ConditionFalse, errMsg),
+						newFailureCondition(corev1.ConditionTrue, errMsg),
+					}
+
+					updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+					addVirtualMachineRestore(r)
+					controller.processVMRestoreWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+					testutils.ExpectEvent(recorder, "Operation failed")
+				})
+
+				DescribeTable("should fail if the pre-restore snapshot is gone", func(preRestoreSnapshot *snapshotv1.VirtualMachineSnapshot, errMsg string) {
+					r := createAutoSnapshotRestore()
+					r.Status.PreRestoreSnapshotName = &preRestoreSnapshotName
+					Expect(controller.VMInformer.GetStore().Add(createSnapshotVM())).To(Succeed())
+					if preRestoreSnapshot != nil {
+						Expect(controller.VMSnapshotInformer.GetStore().Add(preRestoreSnapshot)).To(Succeed())
+					}
 
-				Expect(controller.DataVolumeInformer.GetStore().Add(dv)).To(Succeed())
-				Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+					ur := r.DeepCopy()
+					ur.ResourceVersion = "1"
+					ur.Status.Conditions = []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionFalse, errMsg),
+						newReadyCondition(corev1.ConditionFalse, errMsg),
+						newFailureCondition(corev1.ConditionTrue, errMsg),
+					}
 
-				ur := r.DeepCopy()
-				ur.ResourceVersion = "1"
-				ur.Status.Conditions = []snapshotv1.Condition{
-					newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
-					newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
-				}
-				ur.Status.Restores = []snapshotv1.VolumeRestore{
-					{
-						VolumeName:                diskName,
-						PersistentVolumeClaimName: "alpine-dv", // Name is identical to the original PVC
-						VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-disk1",
-						DataVolumeName:            pointer.P("alpine-dv"),
-					},
-				}
+					updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+					addVirtualMachineRestore(r)
+					controller.processVMRestoreWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+					testutils.ExpectEvent(recorder, "Operation failed")
+				},
+					Entry("when it was deleted", nil, fmt.Sprintf("pre-restore VirtualMachineSnapshot %s was deleted", preRestoreSnapshotName)),
+					Entry("when it is being deleted", func() *snapshotv1.VirtualMachineSnapshot {
+						s := createPreRestoreSnapshot(snapshotv1.InProgress, false)
+						s.DeletionTimestamp = timeFunc()
+						return s
+					}(), fmt.Sprintf("pre-restore VirtualMachineSnapshot %s is being deleted", preRestoreSnapshotName)),
+				)
 
-				addVirtualMachineRestore(r)
+				It("should restore once the pre-restore snapshot succeeded", func() {
+					r := createAutoSnapshotRestore()
+					r.Status.PreRestoreSnapshotName = &preRestoreSnapshotName
+					Expect(controller.VMInformer.GetStore().Add(createRestoreInProgressVM())).To(Succeed())
+					Expect(controller.VMSnapshotInformer.GetStore().Add(createPreRestoreSnapshot(snapshotv1.Succeeded, true))).To(Succeed())
+
+					var updated *snapshotv1.VirtualMachineRestore
+					kubevirtClient.Fake.PrependReactor("update", "virtualmachinerestores", func(action testing.Action) (bool, runtime.Object, error) {
+						update := action.(testing.UpdateAction)
+						updated = update.GetObject().(*snapshotv1.VirtualMachineRestore)
+						return true, updated, nil
+					})
+					addVirtualMachineRestore(r)
+					controller.processVMRestoreWorkItem()
 
-				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
-				patchDataVolumeCalls := expectDataVolumePatch(cdiClient, dv, dv)
-				deletePVCCalls := expectPVCDeletion(k8sClient, r)
-				controller.processVMRestoreWorkItem()
-				Expect(*updateStatusCalls).To(Equal(1))    // Restore is updated to mark DV owns the PVC
-				Expect(*patchDataVolumeCalls).To(Equal(0)) // DV not set to prepopulated yet
-				Expect(*deletePVCCalls).To(Equal(0))       // PVC hasn't been deleted yet
+					Expect(updated).ToNot(BeNil())
+					Expect(updated.Status.PreRestoreSnapshotName).To(HaveValue(Equal(preRestoreSnapshotName)))
+					Expect(updated.Status.Restores).To(HaveLen(1))
+					Expect(updated.Status.Conditions).To(ContainElement(newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs")))
+				})
 			})
 
 			It("source volume/DV gets deleted when volume restore policy is InPlace", func() {
@@ -1147,6 +1711,7 @@ var _ = Describe("Restore controller", func() {
 				addVolumeRestores(r)
 				ur := r.DeepCopy()
 				ur.ResourceVersion = "1"
+				ur.Status.Progress = pointer.P(int32(99))
 				ur.Status.Conditions = []snapshotv1.Condition{
 					newProgressingCondition(corev1.ConditionTrue, "Updating target spec"),
 					newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
@@ -1217,52 +1782,327 @@ var _ = Describe("Restore controller", func() {
 				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
 					Complete: pointer.P(false),
 					Conditions: []snapshotv1.Condition{
-						newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
-						newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+						newProgressingCondition(corev1.ConditionTrue, "Creating new PVCs"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for new PVCs"),
+					},
+				}
+				// note here we dont add the datavolumename
+				addInitialVolumeRestores(r)
+				r.Status.Restores = append(r.Status.Restores, snapshotv1.VolumeRestore{
+					VolumeName:                "disk2",
+					PersistentVolumeClaimName: "restore-uid-disk2",
+					VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-disk2",
+				})
+				for _, pvc := range getRestorePVCs(r) {
+					pvc.Status.Phase = corev1.ClaimBound
+					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+				}
+
+				rc := r.DeepCopy()
+				rc.ResourceVersion = "1"
+				rc.Status.Progress = pointer.P(int32(99))
+				rc.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionTrue, "Updating target spec"),
+					newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
+				}
+				addVolumeRestores(rc)
+				// note here we dont expect the datavolumename
+				rc.Status.Restores = append(rc.Status.Restores, snapshotv1.VolumeRestore{
+					VolumeName:                "disk2",
+					PersistentVolumeClaimName: "restore-uid-disk2",
+					VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-disk2",
+				})
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				addVirtualMachineRestore(r)
+
+				pvcUpdateCalls := expectPVCUpdates(k8sClient, rc)
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				controller.processVMRestoreWorkItem()
+				Expect(*pvcUpdateCalls).To(Equal(1))
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should cleanup, unlock vm and mark restore completed", func() {
+				r := createRestoreWithOwner()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete:           pointer.P(false),
+					DeletedDataVolumes: getDeletedDataVolumes(createModifiedVM()),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Updating target spec"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
+					},
+				}
+				addVolumeRestores(r)
+				for i := range r.Status.Restores {
+					r.Status.Restores[i].DataVolumeName = &r.Status.Restores[i].PersistentVolumeClaimName
+				}
+				addVirtualMachineRestore(r)
+
+				vm := createRestoreInProgressVM()
+				vm.Annotations = map[string]string{lastRestoreAnnotation: "restore-uid"}
+
+				for _, n := range r.Status.DeletedDataVolumes {
+					dv := &cdiv1.DataVolume{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      n,
+							Namespace: testNamespace,
+						},
+					}
+					Expect(controller.DataVolumeInformer.GetStore().Add(dv)).To(Succeed())
+				}
+				for _, pvc := range getRestorePVCs(r) {
+					pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"] = pvc.Name
+					pvc.Status.Phase = corev1.ClaimBound
+					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+				}
+
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+
+				updatedVM := vm.DeepCopy()
+				updatedVM.Status.RestoreInProgress = nil
+
+				ur := r.DeepCopy()
+				ur.ResourceVersion = "1"
+				ur.Status.Complete = pointer.P(true)
+				ur.Status.Progress = pointer.P(int32(100))
+				ur.Status.RestoreTime = timeFunc()
+				ur.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
+					newReadyCondition(corev1.ConditionTrue, "Operation complete"),
+				}
+
+				updateVMStatusCalls := expectVMUpdateStatus(kubevirtClient, updatedVM)
+				dvDeleteCalls := expectDataVolumeDeletes(cdiClient, r.Status.DeletedDataVolumes)
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+
+				controller.processVMRestoreWorkItem()
+
+				l, err := cdiClient.CdiV1beta1().DataVolumes("").List(context.Background(), metav1.ListOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(l.Items).To(BeEmpty())
+				Expect(*updateVMStatusCalls).To(Equal(1))
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*dvDeleteCalls).To(Equal(len(r.Status.DeletedDataVolumes)))
+			})
+
+			It("should complete restore", func() {
+				r := createRestoreWithOwner()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete:           pointer.P(false),
+					DeletedDataVolumes: getDeletedDataVolumes(createModifiedVM()),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Updating target status"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
+					},
+				}
+				addVolumeRestores(r)
+				for i := range r.Status.Restores {
+					r.Status.Restores[i].DataVolumeName = &r.Status.Restores[i].PersistentVolumeClaimName
+				}
+
+				vm := &kubevirtv1.VirtualMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      vmName,
+						Namespace: testNamespace,
+						UID:       vmUID,
+						Annotations: map[string]string{
+							lastRestoreAnnotation: "restore-uid",
+						},
+					},
+				}
+
+				ur := r.DeepCopy()
+				ur.ResourceVersion = "1"
+				ur.Status.Complete = pointer.P(true)
+				ur.Status.Progress = pointer.P(int32(100))
+				ur.Status.RestoreTime = timeFunc()
+				ur.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
+					newReadyCondition(corev1.ConditionTrue, "Operation complete"),
+				}
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+
+				for _, pvc := range getRestorePVCs(r) {
+					pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"] = pvc.Name
+					pvc.Status.Phase = corev1.ClaimBound
+					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+				}
+
+				addVirtualMachineRestore(r)
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				controller.processVMRestoreWorkItem()
+				Expect(recorder.Events).To(Receive(And(
+					ContainSubstring("VirtualMachineRestoreComplete"),
+					ContainSubstring("restored VirtualMachine testvm with 1 PVC(s) [restore-uid-disk1] and 1 DataVolume(s) [restore-uid-disk1]"),
+				)))
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should complete restore and report the memory dump captured by the snapshot", func() {
+				memoryDumpBackup := *sc.Spec.VolumeBackups[0].DeepCopy()
+				memoryDumpBackup.VolumeName = "memorydump"
+				memoryDumpBackup.VolumeSnapshotName = pointer.P("vmsnapshot-snapshot-uid-volume-memorydump")
+				sc.Spec.VolumeBackups = append(sc.Spec.VolumeBackups, memoryDumpBackup)
+				vmTemplate := &sc.Spec.Source.VirtualMachine.Spec.Template.Spec
+				vmTemplate.Volumes = append(vmTemplate.Volumes, kubevirtv1.Volume{
+					Name: "memorydump",
+					VolumeSource: kubevirtv1.VolumeSource{
+						MemoryDump: &kubevirtv1.MemoryDumpVolumeSource{
+							PersistentVolumeClaimVolumeSource: kubevirtv1.PersistentVolumeClaimVolumeSource{
+								PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: "memorydump",
+								},
+							},
+						},
+					},
+				})
+				sc.Spec.MemoryDumpBackup = &snapshotv1.MemoryDumpBackup{
+					VolumeName: "memorydump",
+					FileName:   pointer.P("memory.dump"),
+				}
+				Expect(controller.VMSnapshotContentInformer.GetStore().Update(sc)).To(Succeed())
+
+				r := createRestoreWithOwner()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete:           pointer.P(false),
+					DeletedDataVolumes: getDeletedDataVolumes(createModifiedVM()),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Updating target status"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
+					},
+				}
+				addVolumeRestores(r)
+				for i := range r.Status.Restores {
+					r.Status.Restores[i].DataVolumeName = &r.Status.Restores[i].PersistentVolumeClaimName
+				}
+
+				vm := &kubevirtv1.VirtualMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      vmName,
+						Namespace: testNamespace,
+						UID:       vmUID,
+						Annotations: map[string]string{
+							lastRestoreAnnotation: "restore-uid",
+						},
+					},
+				}
+
+				ur := r.DeepCopy()
+				ur.ResourceVersion = "1"
+				ur.Status.Complete = pointer.P(true)
+				ur.Status.Progress = pointer.P(int32(100))
+				ur.Status.RestoreTime = timeFunc()
+				ur.Status.MemoryDump = &snapshotv1.MemoryDumpLocation{
+					VolumeSnapshotName: "vmsnapshot-snapshot-uid-volume-memorydump",
+					FileName:           pointer.P("memory.dump"),
+				}
+				ur.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
+					newReadyCondition(corev1.ConditionTrue, "Operation complete"),
+				}
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+
+				for _, pvc := range getRestorePVCs(r) {
+					pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"] = pvc.Name
+					pvc.Status.Phase = corev1.ClaimBound
+					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+				}
+
+				addVirtualMachineRestore(r)
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				controller.processVMRestoreWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should complete restore and hotplug the volumes which were hotplugged to the source", func() {
+				hotplugBackup := *sc.Spec.VolumeBackups[0].DeepCopy()
+				hotplugBackup.VolumeName = "hotplug-disk"
+				hotplugBackup.VolumeSnapshotName = pointer.P("vmsnapshot-snapshot-uid-volume-hotplug-disk")
+				hotplugBackup.Hotplug = true
+				hotplugBackup.Disk = &kubevirtv1.Disk{
+					Name: "hotplug-disk",
+					DiskDevice: kubevirtv1.DiskDevice{
+						Disk: &kubevirtv1.DiskTarget{Bus: kubevirtv1.DiskBusVirtio},
+					},
+					Serial: "hotplug-serial",
+				}
+				sc.Spec.VolumeBackups = append(sc.Spec.VolumeBackups, hotplugBackup)
+				Expect(controller.VMSnapshotContentInformer.GetStore().Update(sc)).To(Succeed())
+
+				r := createRestoreWithOwner()
+				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
+					Complete:           pointer.P(false),
+					DeletedDataVolumes: getDeletedDataVolumes(createModifiedVM()),
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Updating target status"),
+						newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
 					},
 				}
-				// note here we dont add the datavolumename
-				addInitialVolumeRestores(r)
+				addVolumeRestores(r)
+				for i := range r.Status.Restores {
+					r.Status.Restores[i].DataVolumeName = &r.Status.Restores[i].PersistentVolumeClaimName
+				}
 				r.Status.Restores = append(r.Status.Restores, snapshotv1.VolumeRestore{
-					VolumeName:                "disk2",
-					PersistentVolumeClaimName: "restore-uid-disk2",
-					VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-disk2",
+					VolumeName:                "hotplug-disk",
+					PersistentVolumeClaimName: "restore-uid-hotplug-disk",
+					VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-hotplug-disk",
+				})
+
+				vm := &kubevirtv1.VirtualMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      vmName,
+						Namespace: testNamespace,
+						UID:       vmUID,
+						Annotations: map[string]string{
+							lastRestoreAnnotation: "restore-uid",
+						},
+					},
+				}
+
+				ur := r.DeepCopy()
+				ur.ResourceVersion = "1"
+				ur.Status.Complete = pointer.P(true)
+				ur.Status.Progress = pointer.P(int32(100))
+				ur.Status.RestoreTime = timeFunc()
+				ur.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
+					newReadyCondition(corev1.ConditionTrue, "Operation complete"),
+				}
+				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
+
+				var addedVolumes []*kubevirtv1.AddVolumeOptions
+				kubevirtClient.Fake.PrependReactor("put", "virtualmachines", func(action testing.Action) (bool, runtime.Object, error) {
+					if action.GetSubresource() != "addvolume" {
+						return false, nil, nil
+					}
+					addedVolumes = append(addedVolumes, action.(kvtesting.PutAction[*kubevirtv1.AddVolumeOptions]).GetOptions())
+					return true, nil, nil
 				})
+
 				for _, pvc := range getRestorePVCs(r) {
+					pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"] = pvc.Name
 					pvc.Status.Phase = corev1.ClaimBound
 					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
 				}
 
-				rc := r.DeepCopy()
-				rc.ResourceVersion = "1"
-				rc.Status.Conditions = []snapshotv1.Condition{
-					newProgressingCondition(corev1.ConditionTrue, "Updating target spec"),
-					newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
-				}
-				addVolumeRestores(rc)
-				// note here we dont expect the datavolumename
-				rc.Status.Restores = append(rc.Status.Restores, snapshotv1.VolumeRestore{
-					VolumeName:                "disk2",
-					PersistentVolumeClaimName: "restore-uid-disk2",
-					VolumeSnapshotName:        "vmsnapshot-snapshot-uid-volume-disk2",
-				})
-				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
 				addVirtualMachineRestore(r)
-
-				pvcUpdateCalls := expectPVCUpdates(k8sClient, rc)
-				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, rc)
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
 				controller.processVMRestoreWorkItem()
-				Expect(*pvcUpdateCalls).To(Equal(1))
 				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(addedVolumes).To(HaveLen(1))
+				Expect(addedVolumes[0].Name).To(Equal("hotplug-disk"))
+				Expect(addedVolumes[0].VolumeSource.PersistentVolumeClaim.ClaimName).To(Equal("restore-uid-hotplug-disk"))
+				Expect(addedVolumes[0].VolumeSource.PersistentVolumeClaim.Hotpluggable).To(BeTrue())
+				Expect(addedVolumes[0].Disk).To(Equal(hotplugBackup.Disk))
 			})
 
-			It("should cleanup, unlock vm and mark restore completed", func() {
+			It("should complete restore and report the access credential secrets the restored VM requires", func() {
 				r := createRestoreWithOwner()
 				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
 					Complete:           pointer.P(false),
 					DeletedDataVolumes: getDeletedDataVolumes(createModifiedVM()),
 					Conditions: []snapshotv1.Condition{
-						newProgressingCondition(corev1.ConditionTrue, "Updating target spec"),
+						newProgressingCondition(corev1.ConditionTrue, "Updating target status"),
 						newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
 					},
 				}
@@ -1270,56 +2110,66 @@ var _ = Describe("Restore controller", func() {
 				for i := range r.Status.Restores {
 					r.Status.Restores[i].DataVolumeName = &r.Status.Restores[i].PersistentVolumeClaimName
 				}
-				addVirtualMachineRestore(r)
 
-				vm := createRestoreInProgressVM()
-				vm.Annotations = map[string]string{lastRestoreAnnotation: "restore-uid"}
-
-				for _, n := range r.Status.DeletedDataVolumes {
-					dv := &cdiv1.DataVolume{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      n,
-							Namespace: testNamespace,
-						},
+				secretSource := func(name string) kubevirtv1.SSHPublicKeyAccessCredentialSource {
+					return kubevirtv1.SSHPublicKeyAccessCredentialSource{
+						Secret: &kubevirtv1.AccessCredentialSecretSource{SecretName: name},
 					}
-					Expect(controller.DataVolumeInformer.GetStore().Add(dv)).To(Succeed())
 				}
-				for _, pvc := range getRestorePVCs(r) {
-					pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"] = pvc.Name
-					pvc.Status.Phase = corev1.ClaimBound
-					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+				vm := &kubevirtv1.VirtualMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      vmName,
+						Namespace: testNamespace,
+						UID:       vmUID,
+						Annotations: map[string]string{
+							lastRestoreAnnotation: "restore-uid",
+						},
+					},
+					Spec: kubevirtv1.VirtualMachineSpec{
+						Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+							Spec: kubevirtv1.VirtualMachineInstanceSpec{
+								AccessCredentials: []kubevirtv1.AccessCredential{
+									{SSHPublicKey: &kubevirtv1.SSHPublicKeyAccessCredential{Source: secretSource("ssh-keys")}},
+									{SSHPublicKey: &kubevirtv1.SSHPublicKeyAccessCredential{Source: secretSource("more-ssh-keys")}},
+								},
+							},
+						},
+					},
 				}
-
-				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
-
-				updatedVM := vm.DeepCopy()
-				updatedVM.Status.RestoreInProgress = nil
+				// virt-controller is not allowed to read secrets
+				k8sClient.Fake.PrependReactor("get", "secrets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					return true, nil, k8serrors.NewForbidden(corev1.Resource("secrets"), action.(testing.GetAction).GetName(), fmt.Errorf("forbidden"))
+				})
 
 				ur := r.DeepCopy()
 				ur.ResourceVersion = "1"
 				ur.Status.Complete = pointer.P(true)
+				ur.Status.Progress = pointer.P(int32(100))
 				ur.Status.RestoreTime = timeFunc()
 				ur.Status.Conditions = []snapshotv1.Condition{
 					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
 					newReadyCondition(corev1.ConditionTrue, "Operation complete"),
+					newAccessCredentialsRequiredCondition(corev1.ConditionTrue, "Access credential secrets ssh-keys, more-ssh-keys of the restored VM are not part of the snapshot and must exist in namespace default"),
 				}
-
-				updateVMStatusCalls := expectVMUpdateStatus(kubevirtClient, updatedVM)
-				dvDeleteCalls := expectDataVolumeDeletes(cdiClient, r.Status.DeletedDataVolumes)
 				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
 
-				controller.processVMRestoreWorkItem()
+				for _, pvc := range getRestorePVCs(r) {
+					pvc.Annotations["cdi.kubevirt.io/storage.populatedFor"] = pvc.Name
+					pvc.Status.Phase = corev1.ClaimBound
+					Expect(controller.PVCInformer.GetStore().Add(&pvc)).To(Succeed())
+				}
 
-				l, err := cdiClient.CdiV1beta1().DataVolumes("").List(context.Background(), metav1.ListOptions{})
-				Expect(err).ToNot(HaveOccurred())
-				Expect(l.Items).To(BeEmpty())
-				Expect(*updateVMStatusCalls).To(Equal(1))
+				addVirtualMachineRestore(r)
+				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "RestoreAccessCredentialsRequired")
+				testutils.ExpectEvent(recorder, "VirtualMachineRestoreComplete")
 				Expect(*updateStatusCalls).To(Equal(1))
-				Expect(*dvDeleteCalls).To(Equal(len(r.Status.DeletedDataVolumes)))
 			})
 
-			It("should complete restore", func() {
+			DescribeTable("should complete restore and report an unschedulable preserved placement", func(policy *snapshotv1.PlacementPolicy) {
 				r := createRestoreWithOwner()
+				r.Spec.PlacementPolicy = policy
 				r.Status = &snapshotv1.VirtualMachineRestoreStatus{
 					Complete:           pointer.P(false),
 					DeletedDataVolumes: getDeletedDataVolumes(createModifiedVM()),
@@ -1342,15 +2192,30 @@ var _ = Describe("Restore controller", func() {
 							lastRestoreAnnotation: "restore-uid",
 						},
 					},
+					Spec: kubevirtv1.VirtualMachineSpec{
+						Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+							Spec: kubevirtv1.VirtualMachineInstanceSpec{
+								NodeSelector: map[string]string{"kubernetes.io/hostname": "gone"},
+							},
+						},
+					},
 				}
+				Expect(controller.NodeInformer.GetStore().Add(&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node01",
+						Labels: map[string]string{"kubernetes.io/hostname": "node01"},
+					},
+				})).To(Succeed())
 
 				ur := r.DeepCopy()
 				ur.ResourceVersion = "1"
 				ur.Status.Complete = pointer.P(true)
+				ur.Status.Progress = pointer.P(int32(100))
 				ur.Status.RestoreTime = timeFunc()
 				ur.Status.Conditions = []snapshotv1.Condition{
 					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
 					newReadyCondition(corev1.ConditionTrue, "Operation complete"),
+					newPlacementUnschedulableCondition(corev1.ConditionTrue, "No node matches the nodeSelector kubernetes.io/hostname=gone of the restored VM"),
 				}
 				updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, ur)
 
@@ -1363,9 +2228,13 @@ var _ = Describe("Restore controller", func() {
 				addVirtualMachineRestore(r)
 				Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
 				controller.processVMRestoreWorkItem()
+				testutils.ExpectEvent(recorder, "RestorePlacementUnschedulable")
 				testutils.ExpectEvent(recorder, "VirtualMachineRestoreComplete")
 				Expect(*updateStatusCalls).To(Equal(1))
-			})
+			},
+				Entry("with the Preserve policy", pointer.P(snapshotv1.PlacementPolicyPreserve)),
+				Entry("with the default policy", nil),
+			)
 
 			It("should update status if restore deleted after completion", func() {
 				r := createRestoreWithOwner()
@@ -1577,6 +2446,34 @@ var _ = Describe("Restore controller", func() {
 					Expect(res).To(BeTrue())
 				})
 
+				It("should keep the current volume of the target for a volume which is not restored", func() {
+					r.Spec.ExcludeVolumes = []string{diskName}
+					r.Status.Restores = nil
+					vm.Spec.DataVolumeTemplates[0].Name = "current-dv"
+					vm.Spec.Template.Spec.Volumes[0].DataVolume.Name = "current-dv"
+					targetVM.UpdateTarget(vm)
+
+					restoredVM, err := targetVM.(*vmRestoreTarget).generateRestoredVMSpec(sc.Spec.Source.VirtualMachine)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(restoredVM.Spec.Template.Spec.Volumes).To(Equal(vm.Spec.Template.Spec.Volumes))
+					Expect(restoredVM.Spec.DataVolumeTemplates).To(Equal(vm.Spec.DataVolumeTemplates))
+					Expect(restoredVM.Spec.Template.Spec.Domain.Devices.Disks).To(Equal(vm.Spec.Template.Spec.Domain.Devices.Disks))
+				})
+
+				It("should leave a volume which is not restored out of a new target", func() {
+					r.Spec.IncludeVolumes = []string{"other"}
+					r.Status.Restores = nil
+					newTarget, err := controller.getTarget(r)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(newTarget.Exists()).To(BeFalse())
+
+					restoredVM, err := newTarget.(*vmRestoreTarget).generateRestoredVMSpec(sc.Spec.Source.VirtualMachine)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(restoredVM.Spec.Template.Spec.Volumes).To(BeEmpty())
+					Expect(restoredVM.Spec.DataVolumeTemplates).To(BeEmpty())
+					Expect(restoredVM.Spec.Template.Spec.Domain.Devices.Disks).To(BeEmpty())
+				})
+
 				It("should not override existing firmware UUID", func() {
 					addRestoreVolumes(true, cdiv1.Succeeded)
 					addVirtualMachineRestore(r)
@@ -1642,6 +2539,7 @@ var _ = Describe("Restore controller", func() {
 						newReadyCondition(corev1.ConditionFalse, "Waiting for target update"),
 					}
 					updatedVMRestore.ResourceVersion = "1"
+					updatedVMRestore.Status.Progress = pointer.P(int32(99))
 
 					updateStatusCalls := expectVMRestoreUpdateStatus(kubevirtClient, updatedVMRestore)
 
@@ -1763,6 +2661,100 @@ var _ = Describe("Restore controller", func() {
 						Expect(err).ShouldNot(HaveOccurred())
 						Expect(*createVMCalls).To(Equal(1))
 					})
+
+					DescribeTable("should validate the patches", func(patches []string, expectedReason string) {
+						r.Status.Restores = nil
+						r.Spec.Patches = patches
+
+						targetVM, err := controller.getTarget(r)
+						Expect(err).ShouldNot(HaveOccurred())
+						reason, err := targetVM.InvalidPatch()
+						Expect(err).ShouldNot(HaveOccurred())
+						if expectedReason == "" {
+							Expect(reason).To(BeEmpty())
+						} else {
+							Expect(reason).To(HavePrefix(expectedReason))
+						}
+					},
+						Entry("with valid patches",
+							[]string{
+								`{"op": "add", "path": "/spec/template/spec/hostname", "value": "renamed-vm"}`,
+								`{"op": "replace", "path": "/spec/template/spec/hostname", "value": "renamed-again-vm"}`,
+							},
+							"",
+						),
+						Entry("with a patch which is not valid JSON",
+							[]string{`{"op": "add", "path": "/spec/template/spec/hostname", "value": "renamed-vm"}`, `{"op": "add"`},
+							"Patch 1 is invalid: cannot decode vm patches",
+						),
+						Entry("with a patch replacing a missing path",
+							[]string{`{"op": "replace", "path": "/spec/template/spec/nonexistent/path", "value": "value"}`},
+							"Patch 0 is invalid: failed to apply patch",
+						),
+						Entry("with a patch changing the kind",
+							[]string{`{"op": "replace", "path": "/kind", "value": "VirtualMachineInstance"}`},
+							"Patch 0 changes the apiVersion or kind of the restore target VirtualMachine",
+						),
+						Entry("with a patch changing the apiVersion",
+							[]string{`{"op": "replace", "path": "/apiVersion", "value": "kubevirt.io/v1alpha3"}`},
+							"Patch 0 changes the apiVersion or kind of the restore target VirtualMachine",
+						),
+					)
+
+					It("should not validate the patches of an existing target", func() {
+						r.Spec.Target.Name = vmName
+						r.Spec.Patches = []string{`{"op": "add"`}
+						Expect(controller.VMInformer.GetStore().Add(createVirtualMachine(testNamespace, vmName))).To(Succeed())
+
+						targetVM, err := controller.getTarget(r)
+						Expect(err).ShouldNot(HaveOccurred())
+						Expect(targetVM.InvalidPatch()).To(BeEmpty())
+					})
+
+					It("should restore the volumes owned by the new VM with volume ownership policy Vm", func() {
+						r.Spec.VolumeOwnershipPolicy = pointer.P(snapshotv1.VolumeOwnershipPolicyVm)
+
+						targetVM, err := controller.getTarget(r)
+						Expect(err).ShouldNot(HaveOccurred())
+						target := targetVM.(*vmRestoreTarget)
+						snapshotVM, err := target.getSnapshotVM()
+						Expect(err).ShouldNot(HaveOccurred())
+						restoredVM, err := target.generateRestoredVMSpec(snapshotVM)
+						Expect(err).ShouldNot(HaveOccurred())
+
+						// the restored PVC is adopted by a DataVolume owned by the VM,
+						// so deleting the VM deletes the volume
+						Expect(restoredVM.Spec.DataVolumeTemplates).To(HaveLen(1))
+						Expect(restoredVM.Spec.DataVolumeTemplates[0].Name).To(Equal(*r.Status.Restores[0].DataVolumeName))
+						Expect(restoredVM.Spec.Template.Spec.Volumes[0].DataVolume).ToNot(BeNil())
+						Expect(restoredVM.Spec.Template.Spec.Volumes[0].DataVolume.Name).To(Equal(*r.Status.Restores[0].DataVolumeName))
+					})
+
+					It("should restore the volumes not owned by any entity with volume ownership policy None", func() {
+						r.Spec.VolumeOwnershipPolicy = pointer.P(snapshotv1.VolumeOwnershipPolicyNone)
+						addInitialVolumeRestores(r)
+
+						targetVM, err := controller.getTarget(r)
+						Expect(err).ShouldNot(HaveOccurred())
+						target := targetVM.(*vmRestoreTarget)
+						snapshotVM, err := target.getSnapshotVM()
+						Expect(err).ShouldNot(HaveOccurred())
+
+						updated, err := target.updateVMRestoreRestores(snapshotVM)
+						Expect(err).ShouldNot(HaveOccurred())
+						Expect(updated).To(BeFalse())
+						Expect(r.Status.Restores[0].DataVolumeName).To(BeNil())
+
+						restoredVM, err := target.generateRestoredVMSpec(snapshotVM)
+						Expect(err).ShouldNot(HaveOccurred())
+
+						// the restored PVC is not adopted by a DataVolume of the VM,
+						// so deleting the VM keeps the volume
+						Expect(restoredVM.Spec.DataVolumeTemplates).To(BeEmpty())
+						Expect(restoredVM.Spec.Template.Spec.Volumes[0].DataVolume).To(BeNil())
+						Expect(restoredVM.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim).ToNot(BeNil())
+						Expect(restoredVM.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(r.Status.Restores[0].PersistentVolumeClaimName))
+					})
 				})
 
 				It("should update condition if deleted and failed to restore", func() {
@@ -1830,8 +2822,8 @@ var _ = Describe("Restore controller", func() {
 					rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
 						Complete: pointer.P(false),
 						Conditions: []snapshotv1.Condition{
-							newProgressingCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore"),
-							newReadyCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore"),
+							newProgressingCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore (targetReadinessPolicy WaitEventually)"),
+							newReadyCondition(corev1.ConditionFalse, "Waiting for target VM to be powered off. Please stop the restore target to proceed with restore (targetReadinessPolicy WaitEventually)"),
 						},
 					}
 					Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
@@ -1841,6 +2833,23 @@ var _ = Describe("Restore controller", func() {
 					controller.processVMRestoreWorkItem()
 					testutils.ExpectEvent(recorder, "RestoreTargetNotReady")
 					Expect(*updateStatusCalls).To(Equal(1))
+					Expect(controller.vmRestoreQueue.(*testutils.MockWorkQueue[string]).GetAddAfterEnqueueCount()).To(Equal(1))
+				})
+
+				It("WaitEventually - should requeue until the target is ready", func() {
+					r := createRestoreWithOwner()
+					r.Spec.TargetReadinessPolicy = pointer.P(snapshotv1.VirtualMachineRestoreWaitEventually)
+					vm := createModifiedVM()
+					Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+					Expect(controller.VMIInformer.GetStore().Add(createVMI(vm))).To(Succeed())
+					kubevirtClient.Fake.PrependReactor("update", "virtualmachinerestores", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						return true, action.(testing.UpdateAction).GetObject(), nil
+					})
+
+					retry, err := controller.updateVMRestore(r)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(retry).To(Equal(targetNotReadyRetryInterval))
+					testutils.ExpectEvent(recorder, "RestoreTargetNotReady")
 				})
 
 				It("StopTarget - should call stop on VM target", func() {
@@ -1853,8 +2862,8 @@ var _ = Describe("Restore controller", func() {
 					rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
 						Complete: pointer.P(false),
 						Conditions: []snapshotv1.Condition{
-							newProgressingCondition(corev1.ConditionFalse, "Automatically stopping restore target for restore operation"),
-							newReadyCondition(corev1.ConditionFalse, "Automatically stopping restore target for restore operation"),
+							newProgressingCondition(corev1.ConditionFalse, "Automatically stopping restore target for restore operation (targetReadinessPolicy StopTarget)"),
+							newReadyCondition(corev1.ConditionFalse, "Automatically stopping restore target for restore operation (targetReadinessPolicy StopTarget)"),
 						},
 					}
 					Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
@@ -1867,6 +2876,29 @@ var _ = Describe("Restore controller", func() {
 					testutils.ExpectEvent(recorder, "RestoreTargetNotReady")
 					Expect(*updateStatusCalls).To(Equal(1))
 					Expect(*stopCalled).To(Equal(1))
+					Expect(controller.vmRestoreQueue.(*testutils.MockWorkQueue[string]).GetAddAfterEnqueueCount()).To(Equal(1))
+				})
+
+				It("default - GracePeriodAndFail - should requeue before the grace period passed", func() {
+					r := createRestoreWithOwner()
+					r.CreationTimestamp = metav1.Time{Time: time.Now().Add(-snapshotv1.DefaultGracePeriod + 10*time.Second)}
+					vm := createModifiedVM()
+					Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
+					Expect(controller.VMIInformer.GetStore().Add(createVMI(vm))).To(Succeed())
+
+					var updated *snapshotv1.VirtualMachineRestore
+					kubevirtClient.Fake.PrependReactor("update", "virtualmachinerestores", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						updated = action.(testing.UpdateAction).GetObject().(*snapshotv1.VirtualMachineRestore)
+						return true, updated, nil
+					})
+
+					retry, err := controller.updateVMRestore(r)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(retry).To(BeNumerically(">", 0))
+					Expect(retry).To(BeNumerically("<=", 10*time.Second))
+					testutils.ExpectEvent(recorder, "RestoreTargetNotReady")
+					Expect(updated).ToNot(BeNil())
+					Expect(updated.Status.Conditions).To(ContainElement(HaveField("Reason", ContainSubstring("targetReadinessPolicy WaitGracePeriod"))))
 				})
 
 				It("default - GracePeriodAndFail - should fail when grace period passed", func() {
@@ -1882,9 +2914,9 @@ var _ = Describe("Restore controller", func() {
 					rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
 						Complete: pointer.P(false),
 						Conditions: []snapshotv1.Condition{
-							newProgressingCondition(corev1.ConditionFalse, "Restore target failed to be ready within 5m0s. Please power off the target VM before attempting restore"),
-							newReadyCondition(corev1.ConditionFalse, "Restore target failed to be ready within 5m0s. Please power off the target VM before attempting restore"),
-							newFailureCondition(corev1.ConditionTrue, "Restore target failed to be ready within 5m0s. Please power off the target VM before attempting restore"),
+							newProgressingCondition(corev1.ConditionFalse, "Restore target failed to be ready within 5m0s. Please power off the target VM before attempting restore (targetReadinessPolicy WaitGracePeriod)"),
+							newReadyCondition(corev1.ConditionFalse, "Restore target failed to be ready within 5m0s. Please power off the target VM before attempting restore (targetReadinessPolicy WaitGracePeriod)"),
+							newFailureCondition(corev1.ConditionTrue, "Restore target failed to be ready within 5m0s. Please power off the target VM before attempting restore (targetReadinessPolicy WaitGracePeriod)"),
 						},
 					}
 					Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
@@ -1939,9 +2971,9 @@ var _ = Describe("Restore controller", func() {
 					rc.Status = &snapshotv1.VirtualMachineRestoreStatus{
 						Complete: pointer.P(false),
 						Conditions: []snapshotv1.Condition{
-							newProgressingCondition(corev1.ConditionFalse, "Restore target VMI must be powered off before restore operation"),
-							newReadyCondition(corev1.ConditionFalse, "Restore target VMI must be powered off before restore operation"),
-							newFailureCondition(corev1.ConditionTrue, "Restore target VMI must be powered off before restore operation"),
+							newProgressingCondition(corev1.ConditionFalse, "Restore target VMI must be powered off before restore operation (targetReadinessPolicy FailImmediate)"),
+							newReadyCondition(corev1.ConditionFalse, "Restore target VMI must be powered off before restore operation (targetReadinessPolicy FailImmediate)"),
+							newFailureCondition(corev1.ConditionTrue, "Restore target VMI must be powered off before restore operation (targetReadinessPolicy FailImmediate)"),
 						},
 					}
 					Expect(controller.VMInformer.GetStore().Add(vm)).To(Succeed())
@@ -2377,6 +3409,264 @@ var _ = Describe("Restore controller", func() {
 			})
 		})
 	})
+
+	It("clearPlacement should remove the placement constraints", func() {
+		spec := &kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					NodeSelector: map[string]string{"kubernetes.io/hostname": "node01"},
+					Affinity:     &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+					Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+				},
+			},
+		}
+		clearPlacement(spec)
+		Expect(spec.Template.Spec.NodeSelector).To(BeNil())
+		Expect(spec.Template.Spec.Affinity).To(BeNil())
+		Expect(spec.Template.Spec.Tolerations).To(BeNil())
+	})
+
+	Context("post-restore cloud-init", func() {
+		const userData = "#cloud-config\nhostname: restored\n"
+
+		newCloudInitSpec := func(source *kubevirtv1.CloudInitNoCloudSource) *kubevirtv1.VirtualMachineSpec {
+			return &kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+					Spec: kubevirtv1.VirtualMachineInstanceSpec{
+						Volumes: []kubevirtv1.Volume{{
+							Name: "cloudinit",
+							VolumeSource: kubevirtv1.VolumeSource{
+								CloudInitNoCloud: source,
+							},
+						}},
+					},
+				},
+			}
+		}
+
+		// readParts returns the content of the parts of a MIME multipart user data
+		readParts := func(userData string) []string {
+			msg, err := mail.ReadMessage(strings.NewReader(userData))
+			Expect(err).ToNot(HaveOccurred())
+			mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mediaType).To(Equal("multipart/mixed"))
+
+			var parts []string
+			reader := multipart.NewReader(msg.Body, params["boundary"])
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					return parts
+				}
+				Expect(err).ToNot(HaveOccurred())
+				content, err := io.ReadAll(part)
+				Expect(err).ToNot(HaveOccurred())
+				parts = append(parts, string(content))
+			}
+		}
+
+		It("should add the user data as a MIME part to the inline user data", func() {
+			const original = "#!/bin/sh\necho original\n"
+			spec := newCloudInitSpec(&kubevirtv1.CloudInitNoCloudSource{
+				UserData:    original,
+				NetworkData: "version: 2",
+			})
+			Expect(injectPostRestoreCloudInit(spec, pointer.P(userData))).To(Succeed())
+			Expect(spec.Template.Spec.Volumes).To(HaveLen(1))
+			noCloud := spec.Template.Spec.Volumes[0].CloudInitNoCloud
+			Expect(readParts(noCloud.UserData)).To(Equal([]string{original, userData}))
+			Expect(noCloud.NetworkData).To(Equal("version: 2"))
+			Expect(spec.Template.Spec.Domain.Devices.Disks).To(BeEmpty())
+
+			merged := noCloud.UserData
+			spec = newCloudInitSpec(&kubevirtv1.CloudInitNoCloudSource{UserData: original})
+			Expect(injectPostRestoreCloudInit(spec, pointer.P(userData))).To(Succeed())
+			Expect(spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserData).To(Equal(merged))
+		})
+
+		It("should keep base64 encoded user data encoded", func() {
+			const original = "#cloud-config\nusers: []\n"
+			spec := newCloudInitSpec(&kubevirtv1.CloudInitNoCloudSource{
+				UserDataBase64: base64.StdEncoding.EncodeToString([]byte(original)),
+			})
+			Expect(injectPostRestoreCloudInit(spec, pointer.P(userData))).To(Succeed())
+			noCloud := spec.Template.Spec.Volumes[0].CloudInitNoCloud
+			Expect(noCloud.UserData).To(BeEmpty())
+			decoded, err := base64.StdEncoding.DecodeString(noCloud.UserDataBase64)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readParts(string(decoded))).To(Equal([]string{original, userData}))
+		})
+
+		It("should append a part to user data which already is a MIME multipart message", func() {
+			original := "Content-Type: multipart/mixed; boundary=\"xyz\"\nMIME-Version: 1.0\n\n" +
+				"--xyz\nContent-Type: text/cloud-config\n\n#cloud-config\nusers: []\n" +
+				"--xyz\nContent-Type: text/x-shellscript\n\n#!/bin/sh\necho original\n" +
+				"--xyz--\n"
+			spec := newCloudInitSpec(&kubevirtv1.CloudInitNoCloudSource{UserData: original})
+			Expect(injectPostRestoreCloudInit(spec, pointer.P(userData))).To(Succeed())
+			Expect(readParts(spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserData)).To(Equal([]string{
+				"#cloud-config\nusers: []",
+				"#!/bin/sh\necho original",
+				userData,
+			}))
+		})
+
+		It("should set the user data of a cloud-init volume without user data", func() {
+			spec := newCloudInitSpec(&kubevirtv1.CloudInitNoCloudSource{NetworkData: "version: 2"})
+			Expect(injectPostRestoreCloudInit(spec, pointer.P(userData))).To(Succeed())
+			Expect(spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserData).To(Equal(userData))
+		})
+
+		It("should fail to merge user data from a Secret", func() {
+			spec := newCloudInitSpec(&kubevirtv1.CloudInitNoCloudSource{
+				UserDataSecretRef: &corev1.LocalObjectReference{Name: "userdata"},
+			})
+			Expect(injectPostRestoreCloudInit(spec, pointer.P(userData))).To(MatchError(ContainSubstring("Secret userdata")))
+			Expect(spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserDataSecretRef.Name).To(Equal("userdata"))
+		})
+
+		It("should add a NoCloud volume and disk when there is no cloud-init volume", func() {
+			spec := &kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{},
+			}
+			Expect(injectPostRestoreCloudInit(spec, pointer.P(userData))).To(Succeed())
+			Expect(spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(spec.Template.Spec.Volumes[0].Name).To(Equal(postRestoreCloudInitVolumeName))
+			Expect(spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserData).To(Equal(userData))
+			Expect(spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(1))
+			Expect(spec.Template.Spec.Domain.Devices.Disks[0].Name).To(Equal(postRestoreCloudInitVolumeName))
+		})
+
+		It("should leave the spec untouched when no user data is set", func() {
+			spec := &kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{},
+			}
+			Expect(injectPostRestoreCloudInit(spec, nil)).To(Succeed())
+			Expect(spec.Template.Spec.Volumes).To(BeEmpty())
+		})
+	})
+
+	Context("metadata restore policy", func() {
+		newSnapshotVM := func() *snapshotv1.VirtualMachine {
+			return &snapshotv1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app":  "db",
+						"tier": "backend",
+					},
+					Annotations: map[string]string{
+						"note": "keep",
+						"team": "storage",
+						kubevirtv1.ControllerAPILatestVersionObservedAnnotation:  "v1",
+						kubevirtv1.ControllerAPIStorageVersionObservedAnnotation: "v1",
+						lastRestoreAnnotation: "old-restore-uid",
+					},
+				},
+			}
+		}
+
+		DescribeTable("with a new VirtualMachine should apply", func(policy *snapshotv1.MetadataRestorePolicy, expectedLabels, expectedAnnotations map[string]string) {
+			newVM := &kubevirtv1.VirtualMachine{}
+			applyMetadataRestorePolicy(policy, newSnapshotVM(), newVM, false)
+			Expect(newVM.Labels).To(BeEquivalentTo(expectedLabels))
+			Expect(newVM.Annotations).To(BeEquivalentTo(expectedAnnotations))
+		},
+			Entry("all metadata except KubeVirt managed annotations",
+				&snapshotv1.MetadataRestorePolicy{Type: snapshotv1.MetadataRestorePolicyAll},
+				map[string]string{"app": "db", "tier": "backend"},
+				map[string]string{"note": "keep", "team": "storage"},
+			),
+			Entry("no metadata",
+				&snapshotv1.MetadataRestorePolicy{Type: snapshotv1.MetadataRestorePolicyNone},
+				nil, nil,
+			),
+			Entry("only the selected keys",
+				&snapshotv1.MetadataRestorePolicy{
+					Type:           snapshotv1.MetadataRestorePolicySelective,
+					LabelKeys:      []string{"app"},
+					AnnotationKeys: []string{"note", lastRestoreAnnotation},
+				},
+				map[string]string{"app": "db"},
+				map[string]string{"note": "keep"},
+			),
+		)
+
+		DescribeTable("with an existing VirtualMachine should merge", func(policy *snapshotv1.MetadataRestorePolicy, expectedLabels, expectedAnnotations map[string]string) {
+			existingVM := &kubevirtv1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "web", "owner": "me"},
+					Annotations: map[string]string{"team": "compute"},
+				},
+			}
+			applyMetadataRestorePolicy(policy, newSnapshotVM(), existingVM, true)
+			Expect(existingVM.Labels).To(Equal(expectedLabels))
+			Expect(existingVM.Annotations).To(Equal(expectedAnnotations))
+		},
+			Entry("all metadata over the existing one",
+				&snapshotv1.MetadataRestorePolicy{Type: snapshotv1.MetadataRestorePolicyAll},
+				map[string]string{"app": "db", "owner": "me", "tier": "backend"},
+				map[string]string{"note": "keep", "team": "storage"},
+			),
+			Entry("nothing",
+				&snapshotv1.MetadataRestorePolicy{Type: snapshotv1.MetadataRestorePolicyNone},
+				map[string]string{"app": "web", "owner": "me"},
+				map[string]string{"team": "compute"},
+			),
+			Entry("only the selected keys",
+				&snapshotv1.MetadataRestorePolicy{
+					Type:           snapshotv1.MetadataRestorePolicySelective,
+					AnnotationKeys: []string{"note"},
+				},
+				map[string]string{"app": "web", "owner": "me"},
+				map[string]string{"note": "keep", "team": "compute"},
+			),
+		)
+	})
+
+	Context("resource remapping", func() {
+		It("should only rewrite references with a matching kind and source name", func() {
+			spec := &kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+					Spec: kubevirtv1.VirtualMachineInstanceSpec{
+						Volumes: []kubevirtv1.Volume{
+							{
+								Name: "secret",
+								VolumeSource: kubevirtv1.VolumeSource{
+									Secret: &kubevirtv1.SecretVolumeSource{SecretName: "shared"},
+								},
+							},
+							{
+								Name: "configmap",
+								VolumeSource: kubevirtv1.VolumeSource{
+									ConfigMap: &kubevirtv1.ConfigMapVolumeSource{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "shared"},
+									},
+								},
+							},
+							{
+								Name: "other",
+								VolumeSource: kubevirtv1.VolumeSource{
+									ConfigMap: &kubevirtv1.ConfigMapVolumeSource{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "other"},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			remapResources(spec, []snapshotv1.ResourceRemapping{
+				{Kind: snapshotv1.ResourceRemappingSecret, SourceName: "shared", TargetName: "new-secret"},
+			})
+
+			volumes := spec.Template.Spec.Volumes
+			Expect(volumes[0].Secret.SecretName).To(Equal("new-secret"))
+			Expect(volumes[1].ConfigMap.Name).To(Equal("shared"))
+			Expect(volumes[2].ConfigMap.Name).To(Equal("other"))
+		})
+	})
 })
 
 func expectVMCreateFailure(client *kubevirtfake.Clientset, failureMsg string) *int {
@@ -21,7 +21,10 @@ package snapshot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -33,6 +36,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
 
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
@@ -55,6 +59,8 @@ const (
 
 	snapshotSourceNamespaceLabel = "snapshot.kubevirt.io/source-vm-namespace"
 
+	snapshotSourceUIDLabel = "snapshot.kubevirt.io/source-vm-uid"
+
 	defaultVolumeSnapshotClassAnnotation = "snapshot.storage.kubernetes.io/is-default-class"
 
 	vmSnapshotContentCreateEvent = "SuccessfulVirtualMachineSnapshotContentCreate"
@@ -63,13 +69,32 @@ const (
 
 	volumeSnapshotMissingEvent = "VolumeSnapshotMissing"
 
+	snapshotDeletionStuckEvent = "DeletionStuck"
+
+	snapshotDeletionBlockedEvent = "DeletionBlocked"
+
+	snapshotExpiredEvent = "SnapshotExpired"
+
+	vmSnapshotAliasEvent = "SnapshotAliased"
+
 	vmSnapshotDeadlineExceededError = "snapshot deadline exceeded"
 
 	snapshotRetryInterval = 5 * time.Second
 
 	contentDeletionInterval = 5 * time.Second
+
+	snapshotDeletionTimeout = 5 * time.Minute
 )
 
+// quotaExceededMessages are parts of the errors CSI drivers report when they refuse
+// a snapshot because a snapshot limit or quota is reached
+var quotaExceededMessages = []string{
+	"resourceexhausted",
+	"quota",
+	"limit exceeded",
+	"maximum number of snapshots",
+}
+
 // Indication messages
 var snapshotIndicationMessages = map[snapshotv1.Indication]string{
 	snapshotv1.VMSnapshotOnlineSnapshotIndication: "Snapshot taken while the VM was running. Consistency depends on guest-agent quiescing.",
@@ -77,6 +102,9 @@ var snapshotIndicationMessages = map[snapshotv1.Indication]string{
 	snapshotv1.VMSnapshotNoGuestAgentIndication:   "Guest agent was not available. Snapshot is crash-consistent and may not be application-consistent.",
 	snapshotv1.VMSnapshotQuiesceFailedIndication:  "Guest agent failed to quiesce the filesystem. Snapshot is crash-consistent and may not be application-consistent.",
 	snapshotv1.VMSnapshotPausedIndication:         "Snapshot taken while the VM was paused. Snapshot is crash-consistent and may not be application-consistent.",
+	snapshotv1.VMSnapshotSharedVolumeIndication:   "Snapshot includes ReadWriteMany volumes captured without coordinating with other VMs using them.",
+	snapshotv1.VMSnapshotResumedIndication:        "Capture was interrupted by a controller restart and resumed from its existing VolumeSnapshots.",
+	snapshotv1.VMSnapshotMemoryDumpIndication:     "Guest memory was dumped before the filesystem freeze and is captured with the volumes.",
 }
 
 func VmSnapshotReady(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
@@ -106,6 +134,11 @@ func vmSnapshotSucceeded(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
 	return vmSnapshot.Status != nil && vmSnapshot.Status.Phase == snapshotv1.Succeeded
 }
 
+func volumeSnapshotsMissing(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	snapErr := vmSnapshotError(vmSnapshot)
+	return snapErr != nil && snapErr.Reason != nil && *snapErr.Reason == snapshotv1.VolumeSnapshotMissingErrorReason
+}
+
 func vmSnapshotProgressing(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
 	return !vmSnapshotFailed(vmSnapshot) && !vmSnapshotSucceeded(vmSnapshot)
 }
@@ -115,8 +148,19 @@ func deleteContentPolicy(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
 		*vmSnapshot.Spec.DeletionPolicy == snapshotv1.VirtualMachineSnapshotContentDelete
 }
 
+// the content of an alias snapshot is owned by the snapshot it aliases, so it is never deleted with it
 func shouldDeleteContent(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) bool {
-	return deleteContentPolicy(vmSnapshot) || !vmSnapshotContentReady(content)
+	return !vmSnapshotAlias(vmSnapshot) && (deleteContentPolicy(vmSnapshot) || !vmSnapshotContentReady(content))
+}
+
+func vmSnapshotAlias(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	_, ok := vmSnapshot.Labels[snapshotv1.SnapshotAliasOfLabel]
+	return ok
+}
+
+func vmSnapshotContentNotReadyAnymore(content *snapshotv1.VirtualMachineSnapshotContent) bool {
+	return content.Status != nil && content.Status.Error != nil && content.Status.Error.Reason != nil &&
+		*content.Status.Error.Reason == snapshotv1.VolumeSnapshotNotReadyErrorReason
 }
 
 func vmSnapshotContentDeleting(content *snapshotv1.VirtualMachineSnapshotContent) bool {
@@ -127,8 +171,8 @@ func vmSnapshotDeleting(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
 	return vmSnapshot != nil && vmSnapshot.DeletionTimestamp != nil
 }
 
-func vmSnapshotTerminating(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
-	return vmSnapshotDeleting(vmSnapshot) || vmSnapshotDeadlineExceeded(vmSnapshot)
+func vmSnapshotTerminating(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) bool {
+	return vmSnapshotDeleting(vmSnapshot) || vmSnapshotDeadlineExceeded(vmSnapshot, content)
 }
 
 func contentDeletedIfNeeded(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) bool {
@@ -138,17 +182,17 @@ func contentDeletedIfNeeded(vmSnapshot *snapshotv1.VirtualMachineSnapshot, conte
 // can unlock source either if the snapshot was completed or if snapshot deleted/exceeded deadline and the content is deleted if it should be
 func canUnlockSource(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) bool {
 	return !vmSnapshotProgressing(vmSnapshot) ||
-		(vmSnapshotTerminating(vmSnapshot) && contentDeletedIfNeeded(vmSnapshot, content))
+		(vmSnapshotTerminating(vmSnapshot, content) && contentDeletedIfNeeded(vmSnapshot, content))
 }
 
-func vmSnapshotDeadlineExceeded(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+func vmSnapshotDeadlineExceeded(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) bool {
 	if vmSnapshotFailed(vmSnapshot) {
 		return true
 	}
 	if !vmSnapshotProgressing(vmSnapshot) {
 		return false
 	}
-	return timeUntilDeadline(vmSnapshot) < 0
+	return timeUntilDeadline(vmSnapshot, content) < 0
 }
 
 func GetVMSnapshotContentName(vmSnapshot *snapshotv1.VirtualMachineSnapshot) string {
@@ -164,10 +208,25 @@ func translateError(e *vsv1.VolumeSnapshotError) *snapshotv1.Error {
 		return nil
 	}
 
-	return &snapshotv1.Error{
+	snapErr := &snapshotv1.Error{
 		Message: e.Message,
 		Time:    e.Time,
 	}
+	if e.Message != nil && isQuotaExceededError(*e.Message) {
+		snapErr.Reason = pointer.P(snapshotv1.SnapshotQuotaExceededErrorReason)
+	}
+
+	return snapErr
+}
+
+func isQuotaExceededError(message string) bool {
+	message = strings.ToLower(message)
+	for _, quotaMessage := range quotaExceededMessages {
+		if strings.Contains(message, quotaMessage) {
+			return true
+		}
+	}
+	return false
 }
 
 func (ctrl *VMSnapshotController) updateVMSnapshot(vmSnapshot *snapshotv1.VirtualMachineSnapshot) (time.Duration, error) {
@@ -184,7 +243,26 @@ func (ctrl *VMSnapshotController) updateVMSnapshot(vmSnapshot *snapshotv1.Virtua
 		return 0, err
 	}
 
-	terminating := vmSnapshotTerminating(vmSnapshot)
+	if vmSnapshotDeleting(vmSnapshot) && VmSnapshotReady(vmSnapshot) {
+		users, err := ctrl.snapshotUsers(vmSnapshot, content)
+		if err != nil {
+			return 0, err
+		}
+		if len(users) > 0 {
+			// keep the finalizer and the content until the users are gone,
+			// the restore and snapshot informers requeue the snapshot
+			ctrl.Recorder.Eventf(
+				vmSnapshot,
+				corev1.EventTypeWarning,
+				snapshotDeletionBlockedEvent,
+				"Deletion waits for %s",
+				strings.Join(users, ", "),
+			)
+			return 0, nil
+		}
+	}
+
+	terminating := vmSnapshotTerminating(vmSnapshot, content)
 	if !terminating {
 		vmSnapshot, err = ctrl.addSnapshotFinalizer(vmSnapshot)
 		if err != nil {
@@ -197,6 +275,41 @@ func (ctrl *VMSnapshotController) updateVMSnapshot(vmSnapshot *snapshotv1.Virtua
 	if vmSnapshot.Status != nil {
 		if source != nil {
 			if vmSnapshotProgressing(vmSnapshot) && !terminating {
+				if content == nil && ctrl.DedupWindow > 0 {
+					existing, err := ctrl.findDedupSnapshot(vmSnapshot)
+					if err != nil {
+						return 0, err
+					}
+					if existing != nil {
+						vmSnapshot, err = ctrl.aliasSnapshot(vmSnapshot, existing)
+						if err != nil {
+							return 0, err
+						}
+						_, err = ctrl.updateSnapshotStatus(vmSnapshot, source)
+						return 0, err
+					}
+				}
+
+				if content == nil && !excludeUnsnapshottableVolumes(vmSnapshot) && !definitionOnlySnapshot(vmSnapshot) {
+					unsnapshottable, err := ctrl.unsnapshottableVolumes(vmSnapshot.Namespace, source)
+					if err != nil {
+						return 0, err
+					}
+					if len(unsnapshottable) > 0 {
+						return 0, ctrl.failNoVolumeSnapshotClass(vmSnapshot, unsnapshottable)
+					}
+				}
+
+				if content == nil && !definitionOnlySnapshot(vmSnapshot) {
+					incompatible, err := ctrl.incompatibleVolumes(vmSnapshot.Namespace, source)
+					if err != nil {
+						return 0, err
+					}
+					if len(incompatible) > 0 {
+						return 0, ctrl.failVolumeNotSnapshottable(vmSnapshot, incompatible)
+					}
+				}
+
 				// attempt to lock source
 				// if fails will attempt again when source is updated
 				if !source.Locked() {
@@ -211,13 +324,26 @@ func (ctrl *VMSnapshotController) updateVMSnapshot(vmSnapshot *snapshotv1.Virtua
 				} else {
 					// create content if does not exist
 					if content == nil {
-						if err := ctrl.createContent(vmSnapshot); err != nil {
+						captured, err := source.CaptureMemory()
+						if errors.Is(err, ErrMemoryDumpFailed) {
+							return 0, ctrl.failSnapshotPreflight(vmSnapshot, err.Error(), snapshotv1.MemoryDumpFailedErrorReason)
+						}
+						if err != nil {
+							return 0, err
+						}
+
+						if !captured {
+							retry = snapshotRetryInterval
+						} else if err := ctrl.createContent(vmSnapshot); err != nil {
 							return 0, err
 						}
 					}
 				}
 				canRemoveFinalizer = false
 			} else {
+				if err := source.ReleaseMemoryDump(); err != nil {
+					return 0, err
+				}
 				if canUnlockSource(vmSnapshot, content) {
 					if _, err := source.Unlock(); err != nil {
 						return 0, err
@@ -244,6 +370,16 @@ func (ctrl *VMSnapshotController) updateVMSnapshot(vmSnapshot *snapshotv1.Virtua
 		}
 	}
 
+	if vmSnapshotDeleting(vmSnapshot) && !canRemoveFinalizer {
+		canRemoveFinalizer, err = ctrl.handleStuckDeletion(vmSnapshot, source, content)
+		if err != nil {
+			return 0, err
+		}
+		if !canRemoveFinalizer {
+			retry = timeUntilDeletionRetry(vmSnapshot)
+		}
+	}
+
 	vmSnapshot, err = ctrl.updateSnapshotStatus(vmSnapshot, source)
 	if err != nil {
 		return 0, err
@@ -256,13 +392,200 @@ func (ctrl *VMSnapshotController) updateVMSnapshot(vmSnapshot *snapshotv1.Virtua
 		}
 	}
 
+	if remaining, ok := timeUntilExpiration(vmSnapshot); ok && !vmSnapshotDeleting(vmSnapshot) {
+		if remaining <= 0 {
+			return 0, ctrl.deleteExpiredSnapshot(vmSnapshot)
+		}
+		if retry == 0 || remaining < retry {
+			retry = remaining
+		}
+	}
+
 	if retry == 0 {
-		return timeUntilDeadline(vmSnapshot), nil
+		return timeUntilDeadline(vmSnapshot, content), nil
 	}
 
 	return retry, nil
 }
 
+// volumeSnapshotsProgressing returns true if all the volume snapshots of the
+// content were created and none of them reported an error
+func volumeSnapshotsProgressing(content *snapshotv1.VirtualMachineSnapshotContent) bool {
+	if content == nil || content.Status == nil || content.Status.Error != nil {
+		return false
+	}
+	if len(content.Spec.VolumeBackups) == 0 ||
+		len(content.Status.VolumeSnapshotStatus) != len(content.Spec.VolumeBackups) {
+		return false
+	}
+	for _, status := range content.Status.VolumeSnapshotStatus {
+		if status.Error != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// updateDeadlineExtendedCondition records the DeadlineGracePeriod extension
+// once the deadline passed while the volume snapshots of the content are
+// progressing. The deadline checks already grant the grace period from the
+// same content, so the condition keeps it granted whatever the content does
+// afterwards.
+func updateDeadlineExtendedCondition(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) {
+	gracePeriod := getDeadlineGracePeriod(vmSnapshot)
+	if gracePeriod == 0 || deadlineExtended(vmSnapshot) || vmSnapshotDeleting(vmSnapshot) ||
+		!vmSnapshotProgressing(vmSnapshot) || !failureDeadlinePassed(vmSnapshot) || !volumeSnapshotsProgressing(content) {
+		return
+	}
+
+	log.Log.Infof("Extending deadline of VirtualMachineSnapshot %s/%s by %s", vmSnapshot.Namespace, vmSnapshot.Name, gracePeriod)
+	updateSnapshotCondition(vmSnapshot, newDeadlineExtendedCondition(corev1.ConditionTrue,
+		fmt.Sprintf("Deadline extended by %s while volume snapshots are progressing", gracePeriod)))
+}
+
+// deleteExpiredSnapshot deletes a succeeded snapshot whose TTL elapsed, the
+// regular deletion then handles its content according to the DeletionPolicy
+func (ctrl *VMSnapshotController) deleteExpiredSnapshot(vmSnapshot *snapshotv1.VirtualMachineSnapshot) error {
+	log.Log.Object(vmSnapshot).V(2).Infof("Deleting vmsnapshot %s/%s, TTL %s elapsed", vmSnapshot.Namespace, vmSnapshot.Name, vmSnapshot.Spec.TTLAfterSuccess.Duration)
+
+	err := ctrl.Client.VirtualMachineSnapshot(vmSnapshot.Namespace).Delete(context.Background(), vmSnapshot.Name, metav1.DeleteOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ctrl.Recorder.Eventf(
+		vmSnapshot,
+		corev1.EventTypeNormal,
+		snapshotExpiredEvent,
+		"Deleting snapshot, TTL of %s after success elapsed",
+		vmSnapshot.Spec.TTLAfterSuccess.Duration,
+	)
+
+	return nil
+}
+
+// handleStuckDeletion reports a snapshot whose deletion has not finished
+// within its deletion timeout. When ForceDelete is set the source is
+// unlocked and true is returned so the snapshot finalizer can be removed.
+func (ctrl *VMSnapshotController) handleStuckDeletion(vmSnapshot *snapshotv1.VirtualMachineSnapshot, source snapshotSource, content *snapshotv1.VirtualMachineSnapshotContent) (bool, error) {
+	if timeUntilDeletionTimeout(vmSnapshot) > 0 {
+		return false, nil
+	}
+
+	blocking, err := ctrl.remainingVolumeSnapshots(content)
+	if err != nil {
+		return false, err
+	}
+
+	if len(blocking) > 0 {
+		ctrl.Recorder.Eventf(
+			vmSnapshot,
+			corev1.EventTypeWarning,
+			snapshotDeletionStuckEvent,
+			"Deletion stuck for more than %s waiting for VolumeSnapshots %s",
+			getDeletionTimeout(vmSnapshot),
+			strings.Join(blocking, ", "),
+		)
+	} else if content != nil {
+		ctrl.Recorder.Eventf(
+			vmSnapshot,
+			corev1.EventTypeWarning,
+			snapshotDeletionStuckEvent,
+			"Deletion stuck for more than %s waiting for VirtualMachineSnapshotContent %s",
+			getDeletionTimeout(vmSnapshot),
+			content.Name,
+		)
+	}
+
+	if !vmSnapshot.Spec.ForceDelete {
+		return false, nil
+	}
+
+	contentName := ""
+	if content != nil {
+		contentName = content.Name
+	}
+	log.Log.Object(vmSnapshot).Warningf("Force deleting: unlocking source and removing finalizer %s, leaving behind VirtualMachineSnapshotContent %q and VolumeSnapshots %v",
+		vmSnapshotFinalizer, contentName, blocking)
+
+	if source != nil {
+		if _, err := source.Unlock(); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// snapshotUsers returns the VirtualMachineRestores in progress that restore
+// the snapshot or its content, and the alias snapshots reusing its content.
+// The deletion of the snapshot waits for them.
+func (ctrl *VMSnapshotController) snapshotUsers(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) ([]string, error) {
+	var users []string
+
+	objs, err := ctrl.VMRestoreInformer.GetIndexer().ByIndex(cache.NamespaceIndex, vmSnapshot.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		vmRestore, ok := obj.(*snapshotv1.VirtualMachineRestore)
+		if !ok {
+			return nil, fmt.Errorf(unexpectedResourceFmt, obj)
+		}
+		if !VmRestoreProgressing(vmRestore) {
+			continue
+		}
+		if contentName := vmRestore.Spec.VirtualMachineSnapshotContentName; contentName != nil {
+			if content == nil || *contentName != content.Name {
+				continue
+			}
+		} else if vmRestore.Spec.VirtualMachineSnapshotName != vmSnapshot.Name {
+			continue
+		}
+		users = append(users, fmt.Sprintf("VirtualMachineRestore %s", vmRestore.Name))
+	}
+
+	vmSnapshots, err := SnapshotsForVM(ctrl.VMSnapshotInformer, vmSnapshot.Namespace, vmSnapshot.Spec.Source.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range vmSnapshots {
+		if alias.Labels[snapshotv1.SnapshotAliasOfLabel] != vmSnapshot.Name || vmSnapshotDeleting(alias) {
+			continue
+		}
+		users = append(users, fmt.Sprintf("VirtualMachineSnapshot %s", alias.Name))
+	}
+
+	return users, nil
+}
+
+func (ctrl *VMSnapshotController) remainingVolumeSnapshots(content *snapshotv1.VirtualMachineSnapshotContent) ([]string, error) {
+	if content == nil {
+		return nil, nil
+	}
+
+	var names []string
+	for _, volumeBackup := range content.Spec.VolumeBackups {
+		if volumeBackup.VolumeSnapshotName == nil {
+			continue
+		}
+
+		volumeSnapshot, err := ctrl.GetVolumeSnapshot(content.Namespace, *volumeBackup.VolumeSnapshotName)
+		if err != nil {
+			return nil, err
+		}
+
+		if volumeSnapshot != nil {
+			names = append(names, volumeSnapshot.Name)
+		}
+	}
+
+	return names, nil
+}
+
 func (ctrl *VMSnapshotController) unfreezeSource(vmSnapshot *snapshotv1.VirtualMachineSnapshot) error {
 	if vmSnapshot == nil {
 		return nil
@@ -339,7 +662,7 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 	log.Log.V(3).Infof("Updating VirtualMachineSnapshotContent %s/%s", content.Namespace, content.Name)
 
 	var volumeSnapshotStatus []snapshotv1.VolumeSnapshotStatus
-	var deletedSnapshots, skippedSnapshots []string
+	var deletedSnapshots, skippedSnapshots, notReadySnapshots []string
 	var didFreeze bool
 
 	vmSnapshot, err := ctrl.getVMSnapshot(content)
@@ -347,8 +670,11 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 		return 0, err
 	}
 
-	if vmSnapshot == nil || vmSnapshotTerminating(vmSnapshot) {
+	if vmSnapshot == nil || vmSnapshotTerminating(vmSnapshot, content) {
 		err = ctrl.unfreezeSource(vmSnapshot)
+		if errors.Is(err, errHooksRunning) {
+			return snapshotRetryInterval, nil
+		}
 		if err != nil {
 			log.Log.Warningf("Failed to unfreeze source for snapshot content %s/%s: %+v",
 				content.Namespace, content.Name, err)
@@ -368,6 +694,11 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 		return contentDeletionInterval, nil
 
 	}
+
+	if err := ctrl.adoptMemoryDumpPVC(content); err != nil {
+		return 0, err
+	}
+
 	contentCpy := content.DeepCopy()
 	if contentCpy.Status == nil {
 		contentCpy.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{}
@@ -388,8 +719,9 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 		}
 
 		if volumeSnapshot == nil {
-			// check if content was created and snapshot was deleted
-			if contentCreated {
+			// check if content was created, or the capture already created
+			// the snapshot before the controller restarted, and it was deleted
+			if contentCreated || volumeSnapshotCreated(content, vsName) {
 				log.Log.Warningf("VolumeSnapshot %s no longer exists", vsName)
 				ctrl.Recorder.Eventf(
 					content,
@@ -418,20 +750,23 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 					return 0, fmt.Errorf("unable to get snapshot source")
 				}
 
-				if err := source.Freeze(); err != nil {
+				err = source.Freeze()
+				if errors.Is(err, errHooksRunning) {
+					return snapshotRetryInterval, nil
+				}
+				if err != nil {
 					contentCpy.Status.Error = &snapshotv1.Error{
 						Time:    currentTime(),
 						Message: pointer.P(err.Error()),
 					}
 					contentCpy.Status.ReadyToUse = pointer.P(false)
 					// Retry again in 5 seconds
-					return 5 * time.Second, ctrl.updateVmSnapshotContentStatus(content, contentCpy)
+					return snapshotRetryInterval, ctrl.updateVmSnapshotContentStatus(content, contentCpy)
 				}
 
 				// assuming that VM is frozen once Freeze() returns
-				// which should be the case
-				// if Freeze() were async, we'd have to return
-				// and only continue when source.Frozen() == true
+				// which should be the case, a freeze running hooks
+				// returns errHooksRunning until it completed
 
 				didFreeze = true
 			}
@@ -450,6 +785,7 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 			vss.ReadyToUse = volumeSnapshot.Status.ReadyToUse
 			vss.CreationTime = volumeSnapshot.Status.CreationTime
 			vss.Error = translateError(volumeSnapshot.Status.Error)
+			vss.Duration = volumeSnapshotDuration(content, volumeSnapshot)
 		}
 
 		volumeSnapshotStatus = append(volumeSnapshotStatus, vss)
@@ -457,10 +793,14 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 
 	created, ready := true, true
 	errorMessage := ""
+	var errorReason *string
 
 	if len(deletedSnapshots) > 0 {
 		created, ready = false, false
 		errorMessage = fmt.Sprintf("VolumeSnapshots (%s) missing", strings.Join(deletedSnapshots, ","))
+		if !contentCreated {
+			errorReason = pointer.P(snapshotv1.VolumeSnapshotMissingErrorReason)
+		}
 	} else if len(skippedSnapshots) > 0 {
 		created, ready = false, false
 		errorMessage = fmt.Sprintf("VolumeSnapshots (%s) skipped because vm snapshot is deleted", strings.Join(skippedSnapshots, ","))
@@ -471,28 +811,43 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 			}
 			if vss.ReadyToUse == nil || !*vss.ReadyToUse {
 				ready = false
+				notReadySnapshots = append(notReadySnapshots, vss.VolumeSnapshotName)
 			}
 			if vss.Error != nil && vss.Error.Message != nil {
 				errorMessage = fmt.Sprintf("VolumeSnapshot %s error: %s", vss.VolumeSnapshotName, *vss.Error.Message)
+				errorReason = vss.Error.Reason
 				break
 			}
 		}
+
+		// a VolumeSnapshot can go back to not ready after the content was ready,
+		// keep reporting it until the VolumeSnapshots are ready again
+		if !ready && (vmSnapshotContentReady(content) || vmSnapshotContentNotReadyAnymore(content)) {
+			if errorMessage == "" {
+				errorMessage = fmt.Sprintf("VolumeSnapshots (%s) no longer ready to use", strings.Join(notReadySnapshots, ","))
+			}
+			errorReason = pointer.P(snapshotv1.VolumeSnapshotNotReadyErrorReason)
+		}
 	}
 
 	if created && contentCpy.Status.CreationTime == nil {
 		contentCpy.Status.CreationTime = currentTime()
 
 		err = ctrl.unfreezeSource(vmSnapshot)
+		if errors.Is(err, errHooksRunning) {
+			return snapshotRetryInterval, nil
+		}
 		if err != nil {
 			return 0, err
 		}
 	}
 
 	if errorMessage != "" && !ready {
-		if shouldUpdateError(contentCpy, errorMessage) {
+		if shouldUpdateError(contentCpy, errorMessage, errorReason) {
 			contentCpy.Status.Error = &snapshotv1.Error{
 				Time:    currentTime(),
 				Message: &errorMessage,
+				Reason:  errorReason,
 			}
 		}
 	} else if errorMessage == "" {
@@ -505,8 +860,43 @@ func (ctrl *VMSnapshotController) updateVMSnapshotContent(content *snapshotv1.Vi
 	return 0, ctrl.updateVmSnapshotContentStatus(content, contentCpy)
 }
 
-func shouldUpdateError(contentCpy *snapshotv1.VirtualMachineSnapshotContent, errorMessage string) bool {
-	return contentCpy.Status.Error == nil || contentCpy.Status.Error.Message == nil || *contentCpy.Status.Error.Message != errorMessage
+// volumeSnapshotCreated returns true if the content status already reports
+// the volume snapshot, i.e. it was created by an earlier reconcile
+func volumeSnapshotCreated(content *snapshotv1.VirtualMachineSnapshotContent, vsName string) bool {
+	if content.Status == nil {
+		return false
+	}
+
+	for _, vss := range content.Status.VolumeSnapshotStatus {
+		if vss.VolumeSnapshotName == vsName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// volumeSnapshotDuration returns how long the volume snapshot took to become
+// ready, keeping the value recorded the first time it was seen ready
+func volumeSnapshotDuration(content *snapshotv1.VirtualMachineSnapshotContent, volumeSnapshot *vsv1.VolumeSnapshot) *metav1.Duration {
+	if volumeSnapshot.Status.ReadyToUse == nil || !*volumeSnapshot.Status.ReadyToUse {
+		return nil
+	}
+
+	if content.Status != nil {
+		for _, vss := range content.Status.VolumeSnapshotStatus {
+			if vss.VolumeSnapshotName == volumeSnapshot.Name && vss.Duration != nil {
+				return vss.Duration
+			}
+		}
+	}
+
+	return &metav1.Duration{Duration: currentTime().Sub(volumeSnapshot.CreationTimestamp.Time)}
+}
+
+func shouldUpdateError(contentCpy *snapshotv1.VirtualMachineSnapshotContent, errorMessage string, errorReason *string) bool {
+	return contentCpy.Status.Error == nil || contentCpy.Status.Error.Message == nil || *contentCpy.Status.Error.Message != errorMessage ||
+		!equality.Semantic.DeepEqual(contentCpy.Status.Error.Reason, errorReason)
 }
 
 func (ctrl *VMSnapshotController) updateVmSnapshotContentStatus(oldContent, newContent *snapshotv1.VirtualMachineSnapshotContent) error {
@@ -619,6 +1009,23 @@ func (ctrl *VMSnapshotController) createContent(vmSnapshot *snapshotv1.VirtualMa
 	if err != nil {
 		return err
 	}
+	// a definition only snapshot has no volume backups, so its content
+	// is ready without any volume snapshots being taken
+	if definitionOnlySnapshot(vmSnapshot) {
+		pvcs = nil
+	}
+	hotplugVolumes, err := source.HotplugVolumes()
+	if err != nil {
+		return err
+	}
+	hotplugVolumeNames := sets.New[string]()
+	for _, volume := range hotplugVolumes {
+		hotplugVolumeNames.Insert(volume.Name)
+	}
+	hotplugDisks, err := source.HotplugDisks()
+	if err != nil {
+		return err
+	}
 	for volumeName, pvcName := range pvcs {
 		pvc, err := ctrl.getSnapshotPVC(vmSnapshot.Namespace, pvcName)
 		if err != nil {
@@ -638,6 +1045,10 @@ func (ctrl *VMSnapshotController) createContent(vmSnapshot *snapshotv1.VirtualMa
 				Spec:       *pvc.Spec.DeepCopy(),
 			},
 			VolumeSnapshotName: &volumeSnapshotName,
+			Hotplug:            hotplugVolumeNames.Has(volumeName),
+		}
+		if disk, ok := hotplugDisks[volumeName]; ok {
+			vb.Disk = &disk
 		}
 
 		volumeBackups = append(volumeBackups, vb)
@@ -647,16 +1058,25 @@ func (ctrl *VMSnapshotController) createContent(vmSnapshot *snapshotv1.VirtualMa
 	if err != nil {
 		return err
 	}
+	memoryDumpBackup, err := ctrl.memoryDumpBackup(vmSnapshot, volumeBackups)
+	if err != nil {
+		return err
+	}
+
 	content := &snapshotv1.VirtualMachineSnapshotContent{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:       GetVMSnapshotContentName(vmSnapshot),
 			Namespace:  vmSnapshot.Namespace,
 			Finalizers: []string{vmSnapshotContentFinalizer},
+			Labels: map[string]string{
+				snapshotSourceUIDLabel: string(source.UID()),
+			},
 		},
 		Spec: snapshotv1.VirtualMachineSnapshotContentSpec{
 			VirtualMachineSnapshotName: &vmSnapshot.Name,
 			Source:                     sourceSpec,
 			VolumeBackups:              volumeBackups,
+			MemoryDumpBackup:           memoryDumpBackup,
 		},
 	}
 
@@ -676,6 +1096,178 @@ func (ctrl *VMSnapshotController) createContent(vmSnapshot *snapshotv1.VirtualMa
 	return nil
 }
 
+// memoryDumpBackup returns the backup of the guest memory dump of the snapshot,
+// if the dump volume is among the volume backups
+func (ctrl *VMSnapshotController) memoryDumpBackup(vmSnapshot *snapshotv1.VirtualMachineSnapshot, volumeBackups []snapshotv1.VolumeBackup) (*snapshotv1.MemoryDumpBackup, error) {
+	if !includeMemoryDump(vmSnapshot) {
+		return nil, nil
+	}
+
+	vm, err := ctrl.getVM(vmSnapshot)
+	if err != nil || vm == nil {
+		return nil, err
+	}
+
+	request := vm.Status.MemoryDumpRequest
+	if request == nil || request.ClaimName != memoryDumpClaimName(vmSnapshot) {
+		return nil, nil
+	}
+
+	for _, vb := range volumeBackups {
+		if vb.VolumeName == request.ClaimName {
+			return &snapshotv1.MemoryDumpBackup{
+				VolumeName: vb.VolumeName,
+				FileName:   request.FileName,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// adoptMemoryDumpPVC makes the content the owner of the PVC holding the
+// guest memory dump, so the PVC is deleted with the content instead of the
+// snapshot
+func (ctrl *VMSnapshotController) adoptMemoryDumpPVC(content *snapshotv1.VirtualMachineSnapshotContent) error {
+	if content.Spec.MemoryDumpBackup == nil {
+		return nil
+	}
+
+	var claimName string
+	for _, vb := range content.Spec.VolumeBackups {
+		if vb.VolumeName == content.Spec.MemoryDumpBackup.VolumeName {
+			claimName = vb.PersistentVolumeClaim.Name
+		}
+	}
+
+	obj, exists, err := ctrl.PVCInformer.GetStore().GetByKey(cacheKeyFunc(content.Namespace, claimName))
+	if err != nil || !exists {
+		return err
+	}
+
+	pvc := obj.(*corev1.PersistentVolumeClaim)
+	if owner := metav1.GetControllerOf(pvc); owner != nil && owner.UID == content.UID {
+		return nil
+	}
+
+	ownerReferences := []metav1.OwnerReference{
+		*metav1.NewControllerRef(content, snapshotv1.SchemeGroupVersion.WithKind("VirtualMachineSnapshotContent")),
+	}
+	patchBytes, err := patch.New(
+		patch.WithTest("/metadata/ownerReferences", pvc.OwnerReferences),
+		patch.WithReplace("/metadata/ownerReferences", ownerReferences),
+	).GeneratePayload()
+	if err != nil {
+		return err
+	}
+
+	_, err = ctrl.Client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Patch(context.Background(), pvc.Name, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+// findDedupSnapshot returns the most recent ready snapshot of the same
+// unchanged VM, captured the same way within the dedup window.
+// A running VM writes to its volumes without changing its generation, so only
+// a stopped VM with the Halted run strategy, which can't be started without a
+// new generation, counts as unchanged. Volumes written to by anything else
+// than the VM, e.g. a reimported DataVolume, are not detected.
+func (ctrl *VMSnapshotController) findDedupSnapshot(vmSnapshot *snapshotv1.VirtualMachineSnapshot) (*snapshotv1.VirtualMachineSnapshot, error) {
+	vm, err := ctrl.getVM(vmSnapshot)
+	if err != nil || vm == nil {
+		return nil, err
+	}
+
+	runStrategy, err := vm.RunStrategy()
+	if err != nil || runStrategy != kubevirtv1.RunStrategyHalted {
+		return nil, nil
+	}
+
+	_, vmiExists, err := ctrl.getVMI(vm)
+	if err != nil || vmiExists {
+		return nil, err
+	}
+
+	vmSnapshots, err := SnapshotsForVM(ctrl.VMSnapshotInformer, vm.Namespace, vm.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := currentTime()
+	var found *snapshotv1.VirtualMachineSnapshot
+	var foundCreationTime *metav1.Time
+	for _, existing := range vmSnapshots {
+		if existing.UID == vmSnapshot.UID || vmSnapshotAlias(existing) || vmSnapshotDeleting(existing) ||
+			!VmSnapshotReady(existing) || !sameSnapshotCapture(vmSnapshot, existing) {
+			continue
+		}
+
+		content, err := ctrl.getContent(existing)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil || !vmSnapshotContentReady(content) || content.Status.CreationTime == nil ||
+			now.Sub(content.Status.CreationTime.Time) > ctrl.DedupWindow {
+			continue
+		}
+
+		sourceVM := content.Spec.Source.VirtualMachine
+		if sourceVM == nil || sourceVM.UID != vm.UID || sourceVM.Generation != vm.Generation {
+			continue
+		}
+
+		if found == nil || foundCreationTime.Before(content.Status.CreationTime) {
+			found = existing.DeepCopy()
+			foundCreationTime = content.Status.CreationTime
+		}
+	}
+
+	return found, nil
+}
+
+// sameSnapshotCapture returns true if both snapshots capture their source the same way
+func sameSnapshotCapture(a, b *snapshotv1.VirtualMachineSnapshot) bool {
+	return a.Spec.PauseDuringSnapshot == b.Spec.PauseDuringSnapshot &&
+		a.Spec.IncludeEphemeralVolumes == b.Spec.IncludeEphemeralVolumes &&
+		excludeUnsnapshottableVolumes(a) == excludeUnsnapshottableVolumes(b) &&
+		definitionOnlySnapshot(a) == definitionOnlySnapshot(b) &&
+		slices.Equal(a.Spec.ExcludedVolumes, b.Spec.ExcludedVolumes)
+}
+
+// aliasSnapshot labels the snapshot as an alias of the existing one and points
+// its status at the content of the existing one instead of capturing again
+func (ctrl *VMSnapshotController) aliasSnapshot(vmSnapshot, existing *snapshotv1.VirtualMachineSnapshot) (*snapshotv1.VirtualMachineSnapshot, error) {
+	labels := make(map[string]string, len(vmSnapshot.Labels)+1)
+	for k, v := range vmSnapshot.Labels {
+		labels[k] = v
+	}
+	labels[snapshotv1.SnapshotAliasOfLabel] = existing.Name
+
+	payload, err := patch.New(patch.WithAdd("/metadata/labels", labels)).GeneratePayload()
+	if err != nil {
+		return nil, err
+	}
+
+	vmSnapshot, err = ctrl.Client.VirtualMachineSnapshot(vmSnapshot.Namespace).Patch(context.Background(), vmSnapshot.Name, types.JSONPatchType, payload, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	contentName := GetVMSnapshotContentName(existing)
+	ctrl.Recorder.Eventf(
+		vmSnapshot,
+		corev1.EventTypeNormal,
+		vmSnapshotAliasEvent,
+		"Reusing VirtualMachineSnapshotContent %s of VirtualMachineSnapshot %s",
+		contentName,
+		existing.Name,
+	)
+
+	vmSnapshot.Status.VirtualMachineSnapshotContentName = &contentName
+	vmSnapshot.Status.Indications = existing.Status.Indications
+	vmSnapshot.Status.SourceIndications = existing.Status.SourceIndications
+	return vmSnapshot, nil
+}
+
 func (ctrl *VMSnapshotController) getSnapshotPVC(namespace, volumeName string) (*corev1.PersistentVolumeClaim, error) {
 	obj, exists, err := ctrl.PVCInformer.GetStore().GetByKey(cacheKeyFunc(namespace, volumeName))
 	if err != nil {
@@ -712,6 +1304,149 @@ func (ctrl *VMSnapshotController) getSnapshotPVC(namespace, volumeName string) (
 	return nil, nil
 }
 
+func excludeUnsnapshottableVolumes(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	return vmSnapshot.Spec.ExcludeUnsnapshottableVolumes == nil || *vmSnapshot.Spec.ExcludeUnsnapshottableVolumes
+}
+
+func definitionOnlySnapshot(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	return vmSnapshot.Spec.SnapshotType != nil && *vmSnapshot.Spec.SnapshotType == snapshotv1.SnapshotTypeDefinitionOnly
+}
+
+func includeMemoryDump(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	return vmSnapshot.Spec.IncludeMemoryDump != nil && *vmSnapshot.Spec.IncludeMemoryDump && !definitionOnlySnapshot(vmSnapshot)
+}
+
+// memoryDumpClaimName is the name of the PVC, and of the VM volume, the
+// guest memory is dumped to for the snapshot
+func memoryDumpClaimName(vmSnapshot *snapshotv1.VirtualMachineSnapshot) string {
+	return fmt.Sprintf("vmsnapshot-%s-memory", vmSnapshot.UID)
+}
+
+// unsnapshottableVolumes describes the bound PVC volumes of the source whose
+// storage class has no VolumeSnapshotClass, naming the volume and provisioner
+func (ctrl *VMSnapshotController) unsnapshottableVolumes(namespace string, source snapshotSource) ([]string, error) {
+	pvcs, err := source.PersistentVolumeClaims()
+	if err != nil {
+		return nil, err
+	}
+
+	var unsnapshottable []string
+	for volumeName, pvcName := range pvcs {
+		obj, exists, err := ctrl.PVCInformer.GetStore().GetByKey(cacheKeyFunc(namespace, pvcName))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		pvc := obj.(*corev1.PersistentVolumeClaim)
+		if pvc.Status.Phase != corev1.ClaimBound || pvc.Spec.StorageClassName == nil {
+			continue
+		}
+
+		volumeSnapshotClass, err := ctrl.getVolumeSnapshotClassName(*pvc.Spec.StorageClassName)
+		if err != nil {
+			return nil, err
+		}
+		if volumeSnapshotClass != "" {
+			continue
+		}
+
+		provisioner := "unknown"
+		obj, exists, err = ctrl.StorageClassInformer.GetStore().GetByKey(*pvc.Spec.StorageClassName)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			provisioner = obj.(*storagev1.StorageClass).Provisioner
+		}
+		unsnapshottable = append(unsnapshottable, fmt.Sprintf("%s (StorageClass %s, provisioner %s)", volumeName, *pvc.Spec.StorageClassName, provisioner))
+	}
+	sort.Strings(unsnapshottable)
+
+	return unsnapshottable, nil
+}
+
+// incompatibleVolumes describes the bound PVC volumes of the source which
+// would be snapshotted with a VolumeSnapshotClass whose driver is not the
+// provisioner of their storage class, the CSI driver would never act on them
+func (ctrl *VMSnapshotController) incompatibleVolumes(namespace string, source snapshotSource) ([]string, error) {
+	pvcs, err := source.PersistentVolumeClaims()
+	if err != nil {
+		return nil, err
+	}
+
+	var incompatible []string
+	for volumeName, pvcName := range pvcs {
+		pvc, err := ctrl.getSnapshotPVC(namespace, pvcName)
+		if err != nil {
+			return nil, err
+		}
+		if pvc == nil {
+			continue
+		}
+
+		obj, exists, err := ctrl.StorageClassInformer.GetStore().GetByKey(*pvc.Spec.StorageClassName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		provisioner := obj.(*storagev1.StorageClass).Provisioner
+
+		volumeSnapshotClassName, err := ctrl.getVolumeSnapshotClassName(*pvc.Spec.StorageClassName)
+		if err != nil || volumeSnapshotClassName == "" {
+			return nil, err
+		}
+		volumeSnapshotClass, err := ctrl.getVolumeSnapshotClass(volumeSnapshotClassName)
+		if err != nil {
+			return nil, err
+		}
+		if volumeSnapshotClass == nil || volumeSnapshotClass.Driver == provisioner {
+			continue
+		}
+
+		incompatible = append(incompatible, fmt.Sprintf("%s (VolumeSnapshotClass %s, driver %s, provisioner %s)",
+			volumeName, volumeSnapshotClass.Name, volumeSnapshotClass.Driver, provisioner))
+	}
+	sort.Strings(incompatible)
+
+	return incompatible, nil
+}
+
+// failNoVolumeSnapshotClass fails the snapshot before anything is captured
+// when the user asked not to exclude volumes without a VolumeSnapshotClass
+func (ctrl *VMSnapshotController) failNoVolumeSnapshotClass(vmSnapshot *snapshotv1.VirtualMachineSnapshot, unsnapshottable []string) error {
+	message := fmt.Sprintf("No VolumeSnapshotClass for volumes %s", strings.Join(unsnapshottable, ", "))
+	return ctrl.failSnapshotPreflight(vmSnapshot, message, snapshotv1.NoVolumeSnapshotClassErrorReason)
+}
+
+// failVolumeNotSnapshottable fails the snapshot before anything is captured
+// when an included volume cannot be snapshotted by its CSI driver
+func (ctrl *VMSnapshotController) failVolumeNotSnapshottable(vmSnapshot *snapshotv1.VirtualMachineSnapshot, incompatible []string) error {
+	message := fmt.Sprintf("Volumes not snapshottable %s", strings.Join(incompatible, ", "))
+	return ctrl.failSnapshotPreflight(vmSnapshot, message, snapshotv1.VolumeNotSnapshottableErrorReason)
+}
+
+func (ctrl *VMSnapshotController) failSnapshotPreflight(vmSnapshot *snapshotv1.VirtualMachineSnapshot, message, reason string) error {
+	log.Log.Object(vmSnapshot).Warning(message)
+
+	vmSnapshotCpy := vmSnapshot.DeepCopy()
+	vmSnapshotCpy.Status.Phase = snapshotv1.Failed
+	vmSnapshotCpy.Status.Error = &snapshotv1.Error{
+		Time:    currentTime(),
+		Message: &message,
+		Reason:  &reason,
+	}
+	updateSnapshotCondition(vmSnapshotCpy, newFailureCondition(corev1.ConditionTrue, message))
+	updateSnapshotCondition(vmSnapshotCpy, newProgressingCondition(corev1.ConditionFalse, "Operation failed"))
+	updateSnapshotCondition(vmSnapshotCpy, newReadyCondition(corev1.ConditionFalse, "Not ready"))
+
+	_, err := ctrl.Client.VirtualMachineSnapshot(vmSnapshotCpy.Namespace).UpdateStatus(context.Background(), vmSnapshotCpy, metav1.UpdateOptions{})
+	return err
+}
+
 func (ctrl *VMSnapshotController) getVolumeSnapshotClassName(storageClassName string) (string, error) {
 	obj, exists, err := ctrl.StorageClassInformer.GetStore().GetByKey(storageClassName)
 	if !exists || err != nil {
@@ -772,6 +1507,7 @@ func (ctrl *VMSnapshotController) updateSnapshotStatus(vmSnapshot *snapshotv1.Vi
 			ReadyToUse: &f,
 		}
 	}
+	normalizeSnapshotIndications(vmSnapshotCpy)
 
 	content, err := ctrl.getContent(vmSnapshot)
 	if err != nil {
@@ -782,6 +1518,7 @@ func (ctrl *VMSnapshotController) updateSnapshotStatus(vmSnapshot *snapshotv1.Vi
 		uid := source.UID()
 		vmSnapshotCpy.Status.SourceUID = &uid
 	}
+	vmSnapshotCpy.Status.Description = vmSnapshotCpy.Spec.Description
 
 	if content != nil && content.Status != nil {
 		// content exists and is initialized
@@ -791,15 +1528,29 @@ func (ctrl *VMSnapshotController) updateSnapshotStatus(vmSnapshot *snapshotv1.Vi
 		vmSnapshotCpy.Status.Error = content.Status.Error
 	}
 
+	// a capture whose VolumeSnapshots are gone cannot complete anymore
+	if vmSnapshotProgressing(vmSnapshotCpy) && vmSnapshotCpy.Status.CreationTime == nil && volumeSnapshotsMissing(vmSnapshotCpy) {
+		vmSnapshotCpy.Status.Phase = snapshotv1.Failed
+	}
+
+	updateDeadlineExtendedCondition(vmSnapshotCpy, content)
+
 	// terminal phase 1 - failed
-	if vmSnapshotDeadlineExceeded(vmSnapshotCpy) {
+	if vmSnapshotDeadlineExceeded(vmSnapshotCpy, content) {
+		failureReason := vmSnapshotDeadlineExceededError
+		if err := vmSnapshotCpy.Status.Error; err != nil && err.Reason != nil && err.Message != nil {
+			failureReason = *err.Message
+		}
 		vmSnapshotCpy.Status.Phase = snapshotv1.Failed
-		updateSnapshotCondition(vmSnapshotCpy, newFailureCondition(corev1.ConditionTrue, vmSnapshotDeadlineExceededError))
+		updateSnapshotCondition(vmSnapshotCpy, newFailureCondition(corev1.ConditionTrue, failureReason))
 		updateSnapshotCondition(vmSnapshotCpy, newProgressingCondition(corev1.ConditionFalse, "Operation failed"))
 		// terminal phase 2 - succeeded
 	} else if vmSnapshotSucceeded(vmSnapshotCpy) || vmSnapshotCpy.Status.CreationTime != nil {
 		vmSnapshotCpy.Status.Phase = snapshotv1.Succeeded
 		updateSnapshotCondition(vmSnapshotCpy, newProgressingCondition(corev1.ConditionFalse, "Operation complete"))
+		updateSnapshotNotReadyAnymoreCondition(vmSnapshotCpy)
+		updateFailedHookIndication(vmSnapshotCpy)
+		vmSnapshotCpy.Status.ConsistencyLevel = snapshotConsistencyLevel(vmSnapshotCpy)
 		if err := ctrl.updateSnapshotSnapshotableVolumes(vmSnapshotCpy, content); err != nil {
 			return nil, err
 		}
@@ -814,6 +1565,15 @@ func (ctrl *VMSnapshotController) updateSnapshotStatus(vmSnapshot *snapshotv1.Vi
 			}
 
 			updateSnapshotSourceIndications(vmSnapshotCpy, source)
+			if err := ctrl.updateSharedVolumeIndication(vmSnapshotCpy, source); err != nil {
+				return nil, err
+			}
+			ctrl.updateResumedIndication(vmSnapshotCpy, content)
+			updateMemoryIndication(vmSnapshotCpy, content)
+			updateFailedHookIndication(vmSnapshotCpy)
+			if err := ctrl.updateExcludedVolumesCondition(vmSnapshotCpy, source); err != nil {
+				return nil, err
+			}
 		} else {
 			updateSnapshotCondition(vmSnapshotCpy, newProgressingCondition(corev1.ConditionFalse, "Source does not exist"))
 		}
@@ -834,6 +1594,12 @@ func (ctrl *VMSnapshotController) updateSnapshotStatus(vmSnapshot *snapshotv1.Vi
 		updateSnapshotCondition(vmSnapshotCpy, newProgressingCondition(corev1.ConditionFalse, "In error state"))
 	}
 
+	if vmSnapshotCpy.Status.Phase == snapshotv1.InProgress {
+		updateSnapshotRetry(vmSnapshot, vmSnapshotCpy)
+	} else {
+		vmSnapshotCpy.Status.NextRetryTime = nil
+	}
+
 	if !equality.Semantic.DeepEqual(vmSnapshot.Status, vmSnapshotCpy.Status) {
 		if _, err := ctrl.Client.VirtualMachineSnapshot(vmSnapshotCpy.Namespace).UpdateStatus(context.Background(), vmSnapshotCpy, metav1.UpdateOptions{}); err != nil {
 			return nil, err
@@ -844,6 +1610,35 @@ func (ctrl *VMSnapshotController) updateSnapshotStatus(vmSnapshot *snapshotv1.Vi
 	return vmSnapshot, nil
 }
 
+// updateSnapshotRetry counts each new error the content reports while the
+// snapshot is in progress as a retry of the failed step
+func updateSnapshotRetry(vmSnapshot, vmSnapshotCpy *snapshotv1.VirtualMachineSnapshot) {
+	snapErr := vmSnapshotCpy.Status.Error
+	if snapErr == nil || snapErr.Time == nil {
+		vmSnapshotCpy.Status.NextRetryTime = nil
+		return
+	}
+
+	if vmSnapshot.Status != nil && vmSnapshot.Status.Error != nil && vmSnapshot.Status.Error.Time != nil &&
+		vmSnapshot.Status.Error.Time.Equal(snapErr.Time) {
+		return
+	}
+
+	vmSnapshotCpy.Status.RetryCount++
+	vmSnapshotCpy.Status.NextRetryTime = &metav1.Time{Time: snapErr.Time.Add(snapshotRetryInterval)}
+}
+
+// updateSnapshotNotReadyAnymoreCondition reports a failure while the content of a
+// succeeded snapshot has VolumeSnapshots which are no longer ready to use
+func updateSnapshotNotReadyAnymoreCondition(vmSnapshot *snapshotv1.VirtualMachineSnapshot) {
+	snapErr := vmSnapshot.Status.Error
+	if snapErr != nil && snapErr.Reason != nil && *snapErr.Reason == snapshotv1.VolumeSnapshotNotReadyErrorReason && snapErr.Message != nil {
+		updateSnapshotCondition(vmSnapshot, newFailureCondition(corev1.ConditionTrue, *snapErr.Message))
+	} else if hasConditionType(vmSnapshot.Status.Conditions, snapshotv1.ConditionFailure) {
+		updateSnapshotCondition(vmSnapshot, newFailureCondition(corev1.ConditionFalse, "VolumeSnapshots ready"))
+	}
+}
+
 // IndicationMessage returns a human-readable message for each indication
 func IndicationMessage(indication snapshotv1.Indication) string {
 	if message, ok := snapshotIndicationMessages[indication]; ok {
@@ -859,7 +1654,7 @@ func updateSnapshotSourceIndications(snapshot *snapshotv1.VirtualMachineSnapshot
 		indications := sets.New(snapshot.Status.Indications...)
 		indications = sets.Insert(indications, snapshotv1.VMSnapshotOnlineSnapshotIndication)
 
-		if source.Paused() {
+		if source.Paused() || sourcePausedBySnapshot(snapshot) {
 			indications = sets.Insert(indications, snapshotv1.VMSnapshotPausedIndication)
 		} else if source.GuestAgent() {
 			indications = sets.Insert(indications, snapshotv1.VMSnapshotGuestAgentIndication)
@@ -872,20 +1667,7 @@ func updateSnapshotSourceIndications(snapshot *snapshotv1.VirtualMachineSnapshot
 			indications = sets.Insert(indications, snapshotv1.VMSnapshotNoGuestAgentIndication)
 		}
 
-		indicationsList := sets.List(indications)
-
-		// Update the old field for backward compatibility
-		snapshot.Status.Indications = indicationsList
-
-		// Update the new sourceIndications field
-		var sourceIndications []snapshotv1.SourceIndication
-		for _, indication := range indicationsList {
-			sourceIndications = append(sourceIndications, snapshotv1.SourceIndication{
-				Indication: indication,
-				Message:    IndicationMessage(indication),
-			})
-		}
-		snapshot.Status.SourceIndications = sourceIndications
+		setSnapshotIndications(snapshot, sets.List(indications))
 	} else {
 		// For offline snapshots, no indications are needed
 		snapshot.Status.Indications = nil
@@ -893,6 +1675,187 @@ func updateSnapshotSourceIndications(snapshot *snapshotv1.VirtualMachineSnapshot
 	}
 }
 
+// updateSharedVolumeIndication records that ReadWriteMany volumes, which other
+// VMs may be writing to, are captured without coordinating with those VMs
+func (ctrl *VMSnapshotController) updateSharedVolumeIndication(snapshot *snapshotv1.VirtualMachineSnapshot, source snapshotSource) error {
+	if definitionOnlySnapshot(snapshot) {
+		return nil
+	}
+
+	shared, err := ctrl.hasSharedVolumes(snapshot.Namespace, source)
+	if err != nil || !shared {
+		return err
+	}
+
+	indications := sets.New(snapshot.Status.Indications...)
+	indications = sets.Insert(indications, snapshotv1.VMSnapshotSharedVolumeIndication)
+	setSnapshotIndications(snapshot, sets.List(indications))
+
+	return nil
+}
+
+// hasSharedVolumes returns true if any of the captured PVCs of the source can
+// be mounted read-write by several nodes
+func (ctrl *VMSnapshotController) hasSharedVolumes(namespace string, source snapshotSource) (bool, error) {
+	pvcs, err := source.PersistentVolumeClaims()
+	if err != nil {
+		return false, err
+	}
+
+	for _, pvcName := range pvcs {
+		pvc, err := ctrl.getSnapshotPVC(namespace, pvcName)
+		if err != nil {
+			return false, err
+		}
+		if pvc != nil && slices.Contains(pvc.Spec.AccessModes, corev1.ReadWriteMany) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// updateResumedIndication indicates a capture whose content was created
+// before the controller started, which is picked back up after a restart
+func (ctrl *VMSnapshotController) updateResumedIndication(snapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) {
+	if content == nil || content.CreationTimestamp.IsZero() || !content.CreationTimestamp.Time.Before(ctrl.startTime) {
+		return
+	}
+
+	indications := sets.New(snapshot.Status.Indications...)
+	indications = sets.Insert(indications, snapshotv1.VMSnapshotResumedIndication)
+	setSnapshotIndications(snapshot, sets.List(indications))
+}
+
+// updateMemoryIndication indicates a capture which includes a dump of the
+// guest memory
+func updateMemoryIndication(snapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) {
+	if content == nil || content.Spec.MemoryDumpBackup == nil {
+		return
+	}
+
+	indications := sets.New(snapshot.Status.Indications...)
+	indications = sets.Insert(indications, snapshotv1.VMSnapshotMemoryDumpIndication)
+	setSnapshotIndications(snapshot, sets.List(indications))
+}
+
+// updateFailedHookIndication reports a failed snapshot hook as a quiesce
+// failure. Post-thaw hooks run once the volumes are captured, so this is
+// also checked when the snapshot succeeded.
+func updateFailedHookIndication(snapshot *snapshotv1.VirtualMachineSnapshot) {
+	if _, ok := snapshot.Annotations[failedHookAnnotation]; !ok {
+		return
+	}
+
+	indications := sets.New(snapshot.Status.Indications...)
+	if !indications.Has(snapshotv1.VMSnapshotOnlineSnapshotIndication) {
+		return
+	}
+	indications = sets.Insert(indications, snapshotv1.VMSnapshotQuiesceFailedIndication)
+	setSnapshotIndications(snapshot, sets.List(indications))
+}
+
+// updateExcludedVolumesCondition warns about excluded volumes the source VM
+// does not have, which usually means a typo in the snapshot spec
+func (ctrl *VMSnapshotController) updateExcludedVolumesCondition(snapshot *snapshotv1.VirtualMachineSnapshot, source snapshotSource) error {
+	if len(snapshot.Spec.ExcludedVolumes) == 0 {
+		return nil
+	}
+
+	vm, err := ctrl.getVM(snapshot)
+	if err != nil || vm == nil || vm.Spec.Template == nil {
+		return err
+	}
+	hotplugVolumes, err := source.HotplugVolumes()
+	if err != nil {
+		return err
+	}
+
+	volumeNames := sets.New[string]()
+	for _, volume := range append(vm.Spec.Template.Spec.Volumes, hotplugVolumes...) {
+		volumeNames.Insert(volume.Name)
+	}
+
+	var missing []string
+	for _, volumeName := range snapshot.Spec.ExcludedVolumes {
+		if !volumeNames.Has(volumeName) {
+			missing = append(missing, volumeName)
+		}
+	}
+
+	if len(missing) > 0 {
+		updateSnapshotCondition(snapshot, newExcludedVolumesMissingCondition(corev1.ConditionTrue,
+			fmt.Sprintf("Excluded volumes %s do not exist in the VM", strings.Join(missing, ", "))))
+	} else if hasConditionType(snapshot.Status.Conditions, snapshotv1.ConditionExcludedVolumesMissing) {
+		updateSnapshotCondition(snapshot, newExcludedVolumesMissingCondition(corev1.ConditionFalse, "All excluded volumes exist in the VM"))
+	}
+
+	return nil
+}
+
+// setSnapshotIndications updates both the old and new indication fields
+func setSnapshotIndications(snapshot *snapshotv1.VirtualMachineSnapshot, indications []snapshotv1.Indication) {
+	// Update the old field for backward compatibility
+	snapshot.Status.Indications = indications
+
+	// Update the new sourceIndications field
+	var sourceIndications []snapshotv1.SourceIndication
+	for _, indication := range indications {
+		sourceIndications = append(sourceIndications, snapshotv1.SourceIndication{
+			Indication: indication,
+			Message:    snapshotIndicationMessage(snapshot, indication),
+		})
+	}
+	snapshot.Status.SourceIndications = sourceIndications
+}
+
+// normalizeSnapshotIndications brings the deprecated Indications and the
+// SourceIndications of snapshots written by older versions in sync. When
+// both are set, the SourceIndications take precedence and the Indications
+// are rebuilt from them, otherwise the missing field is derived from the
+// one which is set.
+func normalizeSnapshotIndications(snapshot *snapshotv1.VirtualMachineSnapshot) {
+	if len(snapshot.Status.SourceIndications) == 0 {
+		if len(snapshot.Status.Indications) > 0 {
+			setSnapshotIndications(snapshot, sets.List(sets.New(snapshot.Status.Indications...)))
+		}
+		return
+	}
+
+	indications := sets.New[snapshotv1.Indication]()
+	for _, sourceIndication := range snapshot.Status.SourceIndications {
+		indications.Insert(sourceIndication.Indication)
+	}
+	snapshot.Status.Indications = sets.List(indications)
+}
+
+// snapshotIndicationMessage returns the message of an indication, naming the
+// hook which failed when the quiesce failure comes from a snapshot hook
+func snapshotIndicationMessage(snapshot *snapshotv1.VirtualMachineSnapshot, indication snapshotv1.Indication) string {
+	failedHook, ok := snapshot.Annotations[failedHookAnnotation]
+	if indication == snapshotv1.VMSnapshotQuiesceFailedIndication && ok {
+		return fmt.Sprintf("Snapshot %s. Snapshot may not be application-consistent.", failedHook)
+	}
+
+	return IndicationMessage(indication)
+}
+
+// snapshotConsistencyLevel classifies a snapshot by the indications recorded while
+// it was taken. Online snapshots are only more than crash consistent when the
+// guest agent froze the filesystems.
+func snapshotConsistencyLevel(snapshot *snapshotv1.VirtualMachineSnapshot) snapshotv1.ConsistencyLevel {
+	indications := sets.New(snapshot.Status.Indications...)
+	switch {
+	case !indications.Has(snapshotv1.VMSnapshotOnlineSnapshotIndication):
+		return snapshotv1.ApplicationConsistent
+	case indications.Has(snapshotv1.VMSnapshotGuestAgentIndication) &&
+		!indications.HasAny(snapshotv1.VMSnapshotPausedIndication, snapshotv1.VMSnapshotNoGuestAgentIndication, snapshotv1.VMSnapshotQuiesceFailedIndication):
+		return snapshotv1.FilesystemConsistent
+	default:
+		return snapshotv1.CrashConsistent
+	}
+}
+
 func (ctrl *VMSnapshotController) updateSnapshotSnapshotableVolumes(snapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) error {
 	if content == nil {
 		return nil
@@ -920,9 +1883,19 @@ func (ctrl *VMSnapshotController) updateSnapshotSnapshotableVolumes(snapshot *sn
 			excludedVolumes = append(excludedVolumes, volume.Name)
 		}
 	}
+
+	// hotplugged volumes are not part of the captured VM spec
+	var hotplugVolumes []string
+	for _, volumeBackup := range content.Spec.VolumeBackups {
+		if volumeBackup.Hotplug {
+			includedVolumes = append(includedVolumes, volumeBackup.VolumeName)
+			hotplugVolumes = append(hotplugVolumes, volumeBackup.VolumeName)
+		}
+	}
 	snapshot.Status.SnapshotVolumes = &snapshotv1.SnapshotVolumesLists{
 		IncludedVolumes: includedVolumes,
 		ExcludedVolumes: excludedVolumes,
+		HotplugVolumes:  hotplugVolumes,
 	}
 	return nil
 }
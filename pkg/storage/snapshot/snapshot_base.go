@@ -70,6 +70,7 @@ type VMSnapshotController struct {
 
 	VMSnapshotInformer        cache.SharedIndexInformer
 	VMSnapshotContentInformer cache.SharedIndexInformer
+	VMRestoreInformer         cache.SharedIndexInformer
 	VMInformer                cache.SharedIndexInformer
 	VMIInformer               cache.SharedIndexInformer
 	StorageClassInformer      cache.SharedIndexInformer
@@ -84,6 +85,14 @@ type VMSnapshotController struct {
 
 	ResyncPeriod time.Duration
 
+	// DedupWindow lets a new snapshot of an unchanged VM reuse the content of
+	// a ready snapshot created less than DedupWindow ago. Zero disables it.
+	DedupWindow time.Duration
+
+	// startTime tells captures in progress since before the controller
+	// started apart from new ones
+	startTime time.Time
+
 	vmSnapshotQueue        workqueue.TypedRateLimitingInterface[string]
 	vmSnapshotContentQueue workqueue.TypedRateLimitingInterface[string]
 	crdQueue               workqueue.TypedRateLimitingInterface[string]
@@ -92,12 +101,19 @@ type VMSnapshotController struct {
 
 	dynamicInformerMap map[string]*dynamicInformer
 	eventHandlerMap    map[string]cache.ResourceEventHandlerFuncs
+
+	// hookRequests tracks the freeze and unfreeze requests carrying
+	// snapshot hooks, they run in the background so the hooks do not hold
+	// a worker
+	hookRequests      map[string]*hookRequest
+	hookRequestsMutex sync.Mutex
 }
 
 var supportedCRDVersions = []string{"v1"}
 
 // Init initializes the snapshot controller
 func (ctrl *VMSnapshotController) Init() error {
+	ctrl.startTime = currentTime().Time
 	ctrl.vmSnapshotQueue = workqueue.NewTypedRateLimitingQueueWithConfig[string](
 		workqueue.DefaultTypedControllerRateLimiter[string](),
 		workqueue.TypedRateLimitingQueueConfig[string]{Name: "virt-controller-snapshot-vmsnapshot"},
@@ -119,6 +135,8 @@ func (ctrl *VMSnapshotController) Init() error {
 		workqueue.TypedRateLimitingQueueConfig[string]{Name: "virt-controller-snapshot-vm"},
 	)
 
+	ctrl.hookRequests = map[string]*hookRequest{}
+
 	ctrl.dynamicInformerMap = map[string]*dynamicInformer{
 		volumeSnapshotCRD:      {informerFunc: controller.VolumeSnapshotInformer},
 		volumeSnapshotClassCRD: {informerFunc: controller.VolumeSnapshotClassInformer},
@@ -141,6 +159,7 @@ func (ctrl *VMSnapshotController) Init() error {
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    ctrl.handleVMSnapshot,
 			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.handleVMSnapshot(newObj) },
+			DeleteFunc: ctrl.handleVMSnapshot,
 		},
 		ctrl.ResyncPeriod,
 	)
@@ -160,6 +179,18 @@ func (ctrl *VMSnapshotController) Init() error {
 		return err
 	}
 
+	_, err = ctrl.VMRestoreInformer.AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    ctrl.handleVMRestore,
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.handleVMRestore(newObj) },
+			DeleteFunc: ctrl.handleVMRestore,
+		},
+		ctrl.ResyncPeriod,
+	)
+	if err != nil {
+		return err
+	}
+
 	_, err = ctrl.VMInformer.AddEventHandlerWithResyncPeriod(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    ctrl.handleVM,
@@ -239,6 +270,7 @@ func (ctrl *VMSnapshotController) Run(threadiness int, stopCh <-chan struct{}) e
 		stopCh,
 		ctrl.VMSnapshotInformer.HasSynced,
 		ctrl.VMSnapshotContentInformer.HasSynced,
+		ctrl.VMRestoreInformer.HasSynced,
 		ctrl.VMInformer.HasSynced,
 		ctrl.VMIInformer.HasSynced,
 		ctrl.CRDInformer.HasSynced,
@@ -431,6 +463,12 @@ func (ctrl *VMSnapshotController) handleVMSnapshot(obj interface{}) {
 		}
 		log.Log.V(3).Infof(enqueuedForSyncFmt, objName)
 		ctrl.vmSnapshotQueue.Add(objName)
+
+		// the deletion of the snapshot an alias reuses the content of
+		// waits for the alias
+		if aliasOf, ok := vmSnapshot.Labels[snapshotv1.SnapshotAliasOfLabel]; ok {
+			ctrl.vmSnapshotQueue.Add(cacheKeyFunc(vmSnapshot.Namespace, aliasOf))
+		}
 	}
 }
 
@@ -457,6 +495,39 @@ func (ctrl *VMSnapshotController) handleVMSnapshotContent(obj interface{}) {
 	}
 }
 
+// handleVMRestore enqueues the snapshot a restore uses, its deletion waits
+// for the restore to finish
+func (ctrl *VMSnapshotController) handleVMRestore(obj interface{}) {
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+
+	vmRestore, ok := obj.(*snapshotv1.VirtualMachineRestore)
+	if !ok {
+		return
+	}
+
+	snapshotName := vmRestore.Spec.VirtualMachineSnapshotName
+	if vmRestore.Spec.VirtualMachineSnapshotContentName != nil {
+		k := cacheKeyFunc(vmRestore.Namespace, *vmRestore.Spec.VirtualMachineSnapshotContentName)
+		storeObj, exists, err := ctrl.VMSnapshotContentInformer.GetStore().GetByKey(k)
+		if err != nil || !exists {
+			return
+		}
+		content := storeObj.(*snapshotv1.VirtualMachineSnapshotContent)
+		if content.Spec.VirtualMachineSnapshotName == nil {
+			return
+		}
+		snapshotName = *content.Spec.VirtualMachineSnapshotName
+	}
+
+	if snapshotName != "" {
+		k := cacheKeyFunc(vmRestore.Namespace, snapshotName)
+		log.Log.V(5).Infof("enqueued vmsnapshot %q for sync", k)
+		ctrl.vmSnapshotQueue.Add(k)
+	}
+}
+
 func (ctrl *VMSnapshotController) handleVM(obj interface{}) {
 	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
 		obj = unknown.Obj
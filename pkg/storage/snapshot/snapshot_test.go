@@ -112,7 +112,8 @@ var _ = Describe("Snapshot controlleer", func() {
 				newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
 				newReadyCondition(corev1.ConditionTrue, "Ready"),
 			},
-			Phase: snapshotv1.Succeeded,
+			Phase:            snapshotv1.Succeeded,
+			ConsistencyLevel: snapshotv1.ApplicationConsistent,
 		}
 
 		return vms
@@ -292,6 +293,7 @@ var _ = Describe("Snapshot controlleer", func() {
 		var vmSnapshotInformer cache.SharedIndexInformer
 		var vmSnapshotContentSource *framework.FakeControllerSource
 		var vmSnapshotContentInformer cache.SharedIndexInformer
+		var vmRestoreInformer cache.SharedIndexInformer
 		var vmInformer cache.SharedIndexInformer
 		var vmSource *framework.FakeControllerSource
 		var vmiInformer cache.SharedIndexInformer
@@ -364,6 +366,7 @@ var _ = Describe("Snapshot controlleer", func() {
 
 			vmSnapshotInformer, vmSnapshotSource = testutils.NewFakeInformerWithIndexersFor(&snapshotv1.VirtualMachineSnapshot{}, virtcontroller.GetVirtualMachineSnapshotInformerIndexers())
 			vmSnapshotContentInformer, vmSnapshotContentSource = testutils.NewFakeInformerWithIndexersFor(&snapshotv1.VirtualMachineSnapshotContent{}, virtcontroller.GetVirtualMachineSnapshotContentInformerIndexers())
+			vmRestoreInformer, _ = testutils.NewFakeInformerWithIndexersFor(&snapshotv1.VirtualMachineRestore{}, virtcontroller.GetVirtualMachineRestoreInformerIndexers())
 			crInformer, crSource = testutils.NewFakeInformerWithIndexersFor(&appsv1.ControllerRevision{}, virtcontroller.GetControllerRevisionInformerIndexers())
 			vmInformer, vmSource = testutils.NewFakeInformerWithIndexersFor(&v1.VirtualMachine{}, virtcontroller.GetVirtualMachineInformerIndexers())
 			vmiInformer, vmiSource = testutils.NewFakeInformerWithIndexersFor(&v1.VirtualMachineInstance{}, virtcontroller.GetVMIInformerIndexers())
@@ -383,6 +386,7 @@ var _ = Describe("Snapshot controlleer", func() {
 				Client:                    virtClient,
 				VMSnapshotInformer:        vmSnapshotInformer,
 				VMSnapshotContentInformer: vmSnapshotContentInformer,
+				VMRestoreInformer:         vmRestoreInformer,
 				VMInformer:                vmInformer,
 				VMIInformer:               vmiInformer,
 				PodInformer:               podInformer,
@@ -617,6 +621,87 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*contentDeletes).To(Equal(1))
 			})
 
+			DescribeTable("should report stuck deletion and force remove finalizer if requested", func(forceDelete bool) {
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.ForceDelete = forceDelete
+				vmSnapshot.DeletionTimestamp = &metav1.Time{Time: timeFunc().Add(-snapshotDeletionTimeout)}
+				vm := createLockedVM()
+				vmSource.Add(vm)
+				content := createVMSnapshotContent()
+				vmSnapshotContentSource.Add(content)
+				volumeSnapshots := createVolumeSnapshots(content)
+				for i := range volumeSnapshots {
+					volumeSnapshotSource.Add(&volumeSnapshots[i])
+				}
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.Phase = snapshotv1.Deleting
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "VM snapshot is deleting"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+				}
+				addVirtualMachineSnapshot(vmSnapshot)
+				contentDeletes := expectVMSnapshotContentDelete(vmSnapshotClient, content.Name)
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				expectedPatchCalls := 0
+				if forceDelete {
+					expectedPatchCalls = 1
+					updatedVM := vm.DeepCopy()
+					updatedVM.Finalizers = []string{}
+					updatedVM.ResourceVersion = "1"
+					vmInterface.EXPECT().Patch(context.Background(), updatedVM.Name, types.JSONPatchType, gomock.Any(), metav1.PatchOptions{}).Return(updatedVM, nil).Times(1)
+					statusUpdate := updatedVM.DeepCopy()
+					statusUpdate.Status.SnapshotInProgress = nil
+					vmInterface.EXPECT().UpdateStatus(context.Background(), statusUpdate, metav1.UpdateOptions{}).Return(statusUpdate, nil).Times(1)
+				}
+				updatedSnapshot2 := updatedSnapshot.DeepCopy()
+				updatedSnapshot2.Finalizers = []string{}
+				patchCalls := expectVMSnapshotPatch(vmSnapshotClient, updatedSnapshot, updatedSnapshot2)
+
+				controller.processVMSnapshotWorkItem()
+				testutils.ExpectEvent(recorder, snapshotDeletionStuckEvent)
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*contentDeletes).To(Equal(1))
+				Expect(*patchCalls).To(Equal(expectedPatchCalls))
+			},
+				Entry("without ForceDelete", false),
+				Entry("with ForceDelete", true),
+			)
+
+			It("should report stuck deletion after custom DeletionTimeout and keep retrying", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.DeletionTimeout = &metav1.Duration{Duration: time.Minute}
+				vmSnapshot.DeletionTimestamp = &metav1.Time{Time: timeFunc().Add(-time.Minute)}
+				vm := createLockedVM()
+				vmSource.Add(vm)
+				content := createVMSnapshotContent()
+				vmSnapshotContentSource.Add(content)
+				volumeSnapshots := createVolumeSnapshots(content)
+				for i := range volumeSnapshots {
+					volumeSnapshotSource.Add(&volumeSnapshots[i])
+				}
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.Phase = snapshotv1.Deleting
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "VM snapshot is deleting"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+				}
+				addVirtualMachineSnapshot(vmSnapshot)
+				expectVMSnapshotContentDelete(vmSnapshotClient, content.Name)
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				Expect(recorder.Events).To(Receive(And(
+					ContainSubstring(snapshotDeletionStuckEvent),
+					ContainSubstring("1m0s"),
+					ContainSubstring(volumeSnapshots[0].Name),
+				)))
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(mockVMSnapshotQueue.GetAddAfterEnqueueCount()).To(Equal(1))
+			})
+
 			It("should finish unlock source VirtualMachine", func() {
 				vmSnapshot := createVMSnapshotSuccess()
 				vm := createLockedVM()
@@ -680,6 +765,150 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*updateStatusCalls).To(Equal(1))
 			})
 
+			Context("when a VirtualMachineSnapshot in use is deleted", func() {
+				var vmSnapshot *snapshotv1.VirtualMachineSnapshot
+				var content *snapshotv1.VirtualMachineSnapshotContent
+
+				BeforeEach(func() {
+					vmSnapshot = createVMSnapshotSuccess()
+					vmSnapshot.DeletionTimestamp = timeFunc()
+					content = createReadyVMSnapshotContent()
+					vmSnapshot.Status.VirtualMachineSnapshotContentName = &content.Name
+					Expect(vmSnapshotContentInformer.GetStore().Add(content)).To(Succeed())
+				})
+
+				newRestore := func(name string, status *snapshotv1.VirtualMachineRestoreStatus) *snapshotv1.VirtualMachineRestore {
+					return &snapshotv1.VirtualMachineRestore{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: testNamespace,
+						},
+						Spec: snapshotv1.VirtualMachineRestoreSpec{
+							VirtualMachineSnapshotName: vmSnapshotName,
+						},
+						Status: status,
+					}
+				}
+
+				newAlias := func(name, aliasOf string) *snapshotv1.VirtualMachineSnapshot {
+					alias := createVirtualMachineSnapshot(testNamespace, name, vmName)
+					alias.Labels = map[string]string{snapshotv1.SnapshotAliasOfLabel: aliasOf}
+					return alias
+				}
+
+				It("should keep the finalizer and the content while a restore is in progress", func() {
+					Expect(vmRestoreInformer.GetStore().Add(newRestore("restore", nil))).To(Succeed())
+					vmSnapshotClient.Fake.PrependReactor("*", "*", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						Fail(fmt.Sprintf("unexpected %s of %s", action.GetVerb(), action.GetResource().Resource))
+						return true, nil, nil
+					})
+
+					retry, err := controller.updateVMSnapshot(vmSnapshot)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(retry).To(BeZero())
+					testutils.ExpectEvent(recorder, "Deletion waits for VirtualMachineRestore restore")
+				})
+
+				DescribeTable("should report the restores in progress using the snapshot", func(restore *snapshotv1.VirtualMachineRestore, expected []string) {
+					Expect(vmRestoreInformer.GetStore().Add(restore)).To(Succeed())
+
+					users, err := controller.snapshotUsers(vmSnapshot, content)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(users).To(Equal(expected))
+				},
+					Entry("with a restore without status", newRestore("restore", nil), []string{"VirtualMachineRestore restore"}),
+					Entry("with an incomplete restore",
+						newRestore("restore", &snapshotv1.VirtualMachineRestoreStatus{Complete: pointer.P(false)}),
+						[]string{"VirtualMachineRestore restore"},
+					),
+					Entry("with a complete restore",
+						newRestore("restore", &snapshotv1.VirtualMachineRestoreStatus{Complete: pointer.P(true)}),
+						nil,
+					),
+					Entry("with a failed restore",
+						newRestore("restore", &snapshotv1.VirtualMachineRestoreStatus{
+							Complete:   pointer.P(false),
+							Conditions: []snapshotv1.Condition{{Type: snapshotv1.ConditionFailure, Status: corev1.ConditionTrue}},
+						}),
+						nil,
+					),
+					Entry("with a restore of another snapshot", &snapshotv1.VirtualMachineRestore{
+						ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: testNamespace},
+						Spec:       snapshotv1.VirtualMachineRestoreSpec{VirtualMachineSnapshotName: "other-snapshot"},
+					}, nil),
+				)
+
+				DescribeTable("should match restores referencing the content", func(sameContent bool, expected []string) {
+					restore := newRestore("restore", nil)
+					restore.Spec.VirtualMachineSnapshotName = ""
+					restore.Spec.VirtualMachineSnapshotContentName = pointer.P("other-content")
+					if sameContent {
+						restore.Spec.VirtualMachineSnapshotContentName = &content.Name
+					}
+					Expect(vmRestoreInformer.GetStore().Add(restore)).To(Succeed())
+
+					users, err := controller.snapshotUsers(vmSnapshot, content)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(users).To(Equal(expected))
+				},
+					Entry("of the snapshot", true, []string{"VirtualMachineRestore restore"}),
+					Entry("of another snapshot", false, nil),
+				)
+
+				It("should report the alias snapshots reusing the content", func() {
+					deleting := newAlias("deleting", vmSnapshotName)
+					deleting.DeletionTimestamp = timeFunc()
+					Expect(vmSnapshotInformer.GetStore().Add(newAlias("alias", vmSnapshotName))).To(Succeed())
+					Expect(vmSnapshotInformer.GetStore().Add(deleting)).To(Succeed())
+					Expect(vmSnapshotInformer.GetStore().Add(newAlias("other", "other-snapshot"))).To(Succeed())
+
+					users, err := controller.snapshotUsers(vmSnapshot, content)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(users).To(ConsistOf("VirtualMachineSnapshot alias"))
+				})
+			})
+
+			It("should delete a succeeded VirtualMachineSnapshot once its TTL elapsed", func() {
+				vmSnapshot := createVMSnapshotSuccess()
+				vmSnapshot.Spec.TTLAfterSuccess = &metav1.Duration{Duration: time.Hour}
+				vmSnapshot.Status.CreationTime = &metav1.Time{Time: timeFunc().Add(-time.Hour)}
+				vmSource.Add(createVM())
+
+				deletes := 0
+				vmSnapshotClient.Fake.PrependReactor("delete", "virtualmachinesnapshots", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					delete, ok := action.(testing.DeleteAction)
+					Expect(ok).To(BeTrue())
+					Expect(delete.GetName()).To(Equal(vmSnapshot.Name))
+					deletes++
+					return true, nil, nil
+				})
+
+				_, err := controller.updateVMSnapshot(vmSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+				testutils.ExpectEvent(recorder, snapshotExpiredEvent)
+				Expect(deletes).To(Equal(1))
+			})
+
+			DescribeTable("should requeue a succeeded VirtualMachineSnapshot when its TTL elapses", func(ttl *metav1.Duration, expectedRetry time.Duration) {
+				vmSnapshot := createVMSnapshotSuccess()
+				vmSnapshot.Spec.TTLAfterSuccess = ttl
+				vmSnapshot.Status.CreationTime = &metav1.Time{Time: timeFunc().Add(-time.Minute)}
+				vmSource.Add(createVM())
+
+				vmSnapshotClient.Fake.PrependReactor("delete", "virtualmachinesnapshots", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					Fail("snapshot should not be deleted")
+					return true, nil, nil
+				})
+
+				retry, err := controller.updateVMSnapshot(vmSnapshot)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(retry).To(Equal(expectedRetry))
+			},
+				Entry("with a TTL", &metav1.Duration{Duration: time.Hour}, 59*time.Minute),
+				Entry("without a TTL", nil, time.Duration(0)),
+				Entry("with a zero TTL", &metav1.Duration{}, time.Duration(0)),
+			)
+
 			It("should delete content when VirtualMachineSnapshot is deleted, content not ready even if retain policy", func() {
 				vmSnapshot := createVMSnapshotSuccess()
 				vmSnapshot.DeletionTimestamp = timeFunc()
@@ -931,6 +1160,31 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*updateStatusCalls).To(Equal(1))
 			})
 
+			It("should echo the description into the status", func() {
+				n := "otherSnapshot"
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.Description = "before upgrade"
+				vm := createVM()
+				vm.Status.SnapshotInProgress = &n
+				vmSource.Add(vm)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.Status.Phase = snapshotv1.InProgress
+				updatedSnapshot.Status.Description = "before upgrade"
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, fmt.Sprintf("Source not locked snapshot %q in progress", n)),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+				}
+				updatedSnapshot.Status.Indications = nil
+				updatedSnapshot.Status.SourceIndications = nil
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				addVirtualMachineSnapshot(vmSnapshot)
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
 			It("should create VirtualMachineSnapshotContent", func() {
 				vmSnapshot := createVMSnapshotInProgress()
 				vm := createLockedVM()
@@ -963,42 +1217,114 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*createCalls).To(Equal(1))
 			})
 
-			It("create VirtualMachineSnapshotContent online snapshot", func() {
-				vmSnapshot := createVMSnapshotInProgress()
-				vm := createLockedVM()
-				vm.Spec.RunStrategy = pointer.P(v1.RunStrategyAlways)
-				vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
-					corev1.ResourceMemory: resource.MustParse("32Mi"),
-				}
+			Context("with a dedup window", func() {
+				const existingSnapshotName = "existing-snapshot"
 
-				vmRevision := createVMRevision(vm)
-				crSource.Add(vmRevision)
-				vmi := createVMI(vm)
-				vmi.Status.VirtualMachineRevisionName = vmRevisionName
-				vmiSource.Add(vmi)
+				var existingSnapshot *snapshotv1.VirtualMachineSnapshot
+				var existingContent *snapshotv1.VirtualMachineSnapshotContent
 
-				vm.ObjectMeta.Annotations = map[string]string{}
-				vm.Spec.Template.Spec.Volumes = append(vm.Spec.Template.Spec.Volumes, v1.Volume{
-					Name: "disk2",
-					VolumeSource: v1.VolumeSource{
-						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{
-							ClaimName: "test-pvc",
-						}},
-					},
+				BeforeEach(func() {
+					controller.DedupWindow = time.Minute
+
+					existingSnapshot = createVMSnapshotSuccess()
+					existingSnapshot.Name = existingSnapshotName
+					existingSnapshot.UID = "existing-uid"
+					existingContent = createReadyVMSnapshotContent()
+					existingContent.Name = "vmsnapshot-content-existing-uid"
+					existingContent.Spec.VirtualMachineSnapshotName = &existingSnapshot.Name
+					existingSnapshot.Status.VirtualMachineSnapshotContentName = &existingContent.Name
+
+					vmSource.Add(createVM())
+					addVirtualMachineSnapshot(existingSnapshot)
+					addVirtualMachineSnapshotContent(existingContent)
 				})
-				// the content source will have the a combination of the vm revision, the vmi and the vm volumes
-				vm.ObjectMeta.Generation = 2
-				pvcs := createPersistentVolumeClaims()
 
-				expectedContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
-				vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
-					corev1.ResourceMemory: resource.MustParse("64Mi"),
+				It("should alias a ready snapshot of the unchanged VM instead of capturing again", func() {
+					vmSnapshot := createVMSnapshotInProgress()
+
+					aliased := vmSnapshot.DeepCopy()
+					aliased.Labels = map[string]string{snapshotv1.SnapshotAliasOfLabel: existingSnapshotName}
+					patchCalls := expectVMSnapshotPatch(vmSnapshotClient, vmSnapshot, aliased)
+
+					var updated *snapshotv1.VirtualMachineSnapshot
+					vmSnapshotClient.Fake.PrependReactor("update", "virtualmachinesnapshots", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						update, ok := action.(testing.UpdateAction)
+						Expect(ok).To(BeTrue())
+						Expect(update.GetSubresource()).To(Equal("status"))
+						updated = update.GetObject().(*snapshotv1.VirtualMachineSnapshot)
+						return true, updated, nil
+					})
+
+					_, err := controller.updateVMSnapshot(vmSnapshot)
+					Expect(err).ToNot(HaveOccurred())
+					testutils.ExpectEvent(recorder, vmSnapshotAliasEvent)
+					Expect(*patchCalls).To(Equal(1))
+					Expect(updated).ToNot(BeNil())
+					Expect(updated.Status.Phase).To(Equal(snapshotv1.Succeeded))
+					Expect(updated.Status.ReadyToUse).To(HaveValue(BeTrue()))
+					Expect(updated.Status.VirtualMachineSnapshotContentName).To(HaveValue(Equal(existingContent.Name)))
+				})
+
+				It("should not alias a snapshot taken before the VM changed", func() {
+					vm := createVM()
+					vm.Generation++
+					vmSource.Modify(vm)
+
+					Eventually(func() (*snapshotv1.VirtualMachineSnapshot, error) {
+						return controller.findDedupSnapshot(createVMSnapshotInProgress())
+					}).Should(BeNil())
+				})
+
+				It("should not alias a snapshot of a running VM", func() {
+					vmiSource.Add(createVMI(createVM()))
+
+					Eventually(func() (*snapshotv1.VirtualMachineSnapshot, error) {
+						return controller.findDedupSnapshot(createVMSnapshotInProgress())
+					}).Should(BeNil())
+				})
+
+				It("should not alias a snapshot of a VM which can be started without a new generation", func() {
+					vm := createVM()
+					vm.Spec.RunStrategy = pointer.P(v1.RunStrategyManual)
+					vmSource.Modify(vm)
+
+					Eventually(func() (*snapshotv1.VirtualMachineSnapshot, error) {
+						return controller.findDedupSnapshot(createVMSnapshotInProgress())
+					}).Should(BeNil())
+				})
+
+				It("should not alias a snapshot created outside of the window", func() {
+					currentTime = func() *metav1.Time {
+						return &metav1.Time{Time: timeStamp.Add(2 * time.Minute)}
+					}
+
+					existing, err := controller.findDedupSnapshot(createVMSnapshotInProgress())
+					Expect(err).ToNot(HaveOccurred())
+					Expect(existing).To(BeNil())
+				})
+
+				It("should never delete the content of an aliased snapshot", func() {
+					vmSnapshot := createVMSnapshotSuccess()
+					vmSnapshot.Labels = map[string]string{snapshotv1.SnapshotAliasOfLabel: existingSnapshotName}
+					Expect(shouldDeleteContent(vmSnapshot, existingContent)).To(BeFalse())
+				})
+			})
+
+			It("should indicate ReadWriteMany volumes captured without coordination", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				storageClass := createStorageClass()
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+				pvcs := createPersistentVolumeClaims()
+				for i := range pvcs {
+					pvcs[i].Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+					pvcSource.Modify(&pvcs[i])
 				}
+				vmSnapshotContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+
 				vmSource.Add(vm)
-				storageClass := createStorageClass()
 				storageClassSource.Add(storageClass)
-				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
-				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, expectedContent)
+				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, vmSnapshotContent)
 				vmSnapshotSource.Add(vmSnapshot)
 				addVolumeSnapshotClass(volumeSnapshotClass)
 
@@ -1012,19 +1338,12 @@ var _ = Describe("Snapshot controlleer", func() {
 						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
 						newReadyCondition(corev1.ConditionFalse, "Not ready"),
 					},
-				}
-				updatedSnapshot.Status.Indications = []snapshotv1.Indication{
-					snapshotv1.VMSnapshotNoGuestAgentIndication,
-					snapshotv1.VMSnapshotOnlineSnapshotIndication,
-				}
-				updatedSnapshot.Status.SourceIndications = []snapshotv1.SourceIndication{
-					{
-						Indication: snapshotv1.VMSnapshotNoGuestAgentIndication,
-						Message:    IndicationMessage(snapshotv1.VMSnapshotNoGuestAgentIndication),
-					},
-					{
-						Indication: snapshotv1.VMSnapshotOnlineSnapshotIndication,
-						Message:    IndicationMessage(snapshotv1.VMSnapshotOnlineSnapshotIndication),
+					Indications: []snapshotv1.Indication{snapshotv1.VMSnapshotSharedVolumeIndication},
+					SourceIndications: []snapshotv1.SourceIndication{
+						{
+							Indication: snapshotv1.VMSnapshotSharedVolumeIndication,
+							Message:    IndicationMessage(snapshotv1.VMSnapshotSharedVolumeIndication),
+						},
 					},
 				}
 				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
@@ -1035,24 +1354,16 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*createCalls).To(Equal(1))
 			})
 
-			It("should create VirtualMachineSnapshotContent with memory dump", func() {
-				storageClass := createStorageClass()
-				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
-
+			It("should create VirtualMachineSnapshotContent without volume backups for a definition only snapshot", func() {
 				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.SnapshotType = pointer.P(snapshotv1.SnapshotTypeDefinitionOnly)
+				vmSnapshot.Spec.ExcludeUnsnapshottableVolumes = pointer.P(false)
 				vm := createLockedVM()
-				vm = updateVMWithMemoryDump(vm)
-				pvcs := createPersistentVolumeClaims()
-				md := memoryDumpPVC()
-				pvcSource.Add(&md)
-				pvcs = append(pvcs, md)
-				vmSnapshotContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.Spec.VolumeBackups = nil
 
 				vmSource.Add(vm)
-				storageClassSource.Add(storageClass)
 				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, vmSnapshotContent)
-				vmSnapshotSource.Add(vmSnapshot)
-				addVolumeSnapshotClass(volumeSnapshotClass)
 
 				updatedSnapshot := vmSnapshot.DeepCopy()
 				updatedSnapshot.ResourceVersion = "1"
@@ -1067,45 +1378,22 @@ var _ = Describe("Snapshot controlleer", func() {
 				}
 				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
 
+				addVirtualMachineSnapshot(vmSnapshot)
 				controller.processVMSnapshotWorkItem()
 				testutils.ExpectEvent(recorder, "SuccessfulVirtualMachineSnapshotContentCreate")
 				Expect(*updateStatusCalls).To(Equal(1))
 				Expect(*createCalls).To(Equal(1))
 			})
 
-			It("should create online VirtualMachineSnapshotContent with volume migration", func() {
+			It("should create VirtualMachineSnapshotContent without the excluded volumes", func() {
 				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.ExcludedVolumes = []string{diskName, "scratch"}
 				vm := createLockedVM()
-				vm.Spec.RunStrategy = pointer.P(v1.RunStrategyAlways)
-				vmRevision := createVMRevision(vm)
-				crSource.Add(vmRevision)
-
-				vm.ObjectMeta.Annotations = map[string]string{}
-
-				vm.Spec.DataVolumeTemplates[0].Name = "alpine-dv-dest"
-				vm.Spec.Template.Spec.Volumes[0].VolumeSource.DataVolume.Name = "alpine-dv-dest"
-
-				vmi := createVMI(vm)
-				vmi.Status.VirtualMachineRevisionName = vmRevisionName
-				vmiSource.Add(vmi)
-
-				vm.ObjectMeta.Generation = 2
-				pvcs := createPVCsForVM(vm)
-				for i := range pvcs {
-					pvcSource.Add(&pvcs[i])
-				}
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.Spec.VolumeBackups = nil
 
-				expectedContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
-				vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
-					corev1.ResourceMemory: resource.MustParse("64Mi"),
-				}
 				vmSource.Add(vm)
-				storageClass := createStorageClass()
-				storageClassSource.Add(storageClass)
-				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
-				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, expectedContent)
-				vmSnapshotSource.Add(vmSnapshot)
-				addVolumeSnapshotClass(volumeSnapshotClass)
+				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, vmSnapshotContent)
 
 				updatedSnapshot := vmSnapshot.DeepCopy()
 				updatedSnapshot.ResourceVersion = "1"
@@ -1115,18 +1403,660 @@ var _ = Describe("Snapshot controlleer", func() {
 					Phase:      snapshotv1.InProgress,
 					Conditions: []snapshotv1.Condition{
 						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+						newExcludedVolumesMissingCondition(corev1.ConditionTrue, "Excluded volumes scratch do not exist in the VM"),
 						newReadyCondition(corev1.ConditionFalse, "Not ready"),
 					},
 				}
-				updatedSnapshot.Status.Indications = []snapshotv1.Indication{
-					snapshotv1.VMSnapshotNoGuestAgentIndication,
-					snapshotv1.VMSnapshotOnlineSnapshotIndication,
-				}
-				updatedSnapshot.Status.SourceIndications = []snapshotv1.SourceIndication{
-					{
-						Indication: snapshotv1.VMSnapshotNoGuestAgentIndication,
-						Message:    IndicationMessage(snapshotv1.VMSnapshotNoGuestAgentIndication),
-					},
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				addVirtualMachineSnapshot(vmSnapshot)
+				controller.processVMSnapshotWorkItem()
+				testutils.ExpectEvent(recorder, "SuccessfulVirtualMachineSnapshotContentCreate")
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*createCalls).To(Equal(1))
+			})
+
+			It("should fail when a volume has no VolumeSnapshotClass and unsnapshottable volumes are not excluded", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.ExcludeUnsnapshottableVolumes = pointer.P(false)
+				vm := createLockedVM()
+				storageClass := createStorageClass()
+
+				vmSource.Add(vm)
+				storageClassSource.Add(storageClass)
+
+				message := fmt.Sprintf("No VolumeSnapshotClass for volumes %s (StorageClass %s, provisioner %s)",
+					diskName, storageClassName, storageClass.Provisioner)
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.Phase = snapshotv1.Failed
+				updatedSnapshot.Status.Error = &snapshotv1.Error{
+					Time:    timeFunc(),
+					Message: &message,
+					Reason:  pointer.P(snapshotv1.NoVolumeSnapshotClassErrorReason),
+				}
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newFailureCondition(corev1.ConditionTrue, message),
+					newProgressingCondition(corev1.ConditionFalse, "Operation failed"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				addVirtualMachineSnapshot(vmSnapshot)
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should fail when the VolumeSnapshotClass of a volume belongs to another driver", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				storageClass := createStorageClass()
+				storageProfile := createStorageProfile()
+				volumeSnapshotClass := createVolumeSnapshotClasses()[1]
+				volumeSnapshotClass.Driver = "other.csi.driver"
+
+				vmSource.Add(vm)
+				storageClassSource.Add(storageClass)
+				storageProfileSource.Add(storageProfile)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+
+				message := fmt.Sprintf("Volumes not snapshottable %s (VolumeSnapshotClass %s, driver other.csi.driver, provisioner %s)",
+					diskName, volumeSnapshotClassName2, storageClass.Provisioner)
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.Phase = snapshotv1.Failed
+				updatedSnapshot.Status.Error = &snapshotv1.Error{
+					Time:    timeFunc(),
+					Message: &message,
+					Reason:  pointer.P(snapshotv1.VolumeNotSnapshottableErrorReason),
+				}
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newFailureCondition(corev1.ConditionTrue, message),
+					newProgressingCondition(corev1.ConditionFalse, "Operation failed"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				addVirtualMachineSnapshot(vmSnapshot)
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("create VirtualMachineSnapshotContent online snapshot", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				vm.Spec.RunStrategy = pointer.P(v1.RunStrategyAlways)
+				vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("32Mi"),
+				}
+
+				vmRevision := createVMRevision(vm)
+				crSource.Add(vmRevision)
+				vmi := createVMI(vm)
+				vmi.Status.VirtualMachineRevisionName = vmRevisionName
+				vmiSource.Add(vmi)
+
+				vm.ObjectMeta.Annotations = map[string]string{}
+				vm.Spec.Template.Spec.Volumes = append(vm.Spec.Template.Spec.Volumes, v1.Volume{
+					Name: "disk2",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: "test-pvc",
+						}},
+					},
+				})
+				// the content source will have the a combination of the vm revision, the vmi and the vm volumes
+				vm.ObjectMeta.Generation = 2
+				pvcs := createPersistentVolumeClaims()
+
+				expectedContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+				vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("64Mi"),
+				}
+				vmSource.Add(vm)
+				storageClass := createStorageClass()
+				storageClassSource.Add(storageClass)
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, expectedContent)
+				vmSnapshotSource.Add(vmSnapshot)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{
+					SourceUID:  &vmUID,
+					ReadyToUse: pointer.P(false),
+					Phase:      snapshotv1.InProgress,
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+						newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					},
+				}
+				updatedSnapshot.Status.Indications = []snapshotv1.Indication{
+					snapshotv1.VMSnapshotNoGuestAgentIndication,
+					snapshotv1.VMSnapshotOnlineSnapshotIndication,
+				}
+				updatedSnapshot.Status.SourceIndications = []snapshotv1.SourceIndication{
+					{
+						Indication: snapshotv1.VMSnapshotNoGuestAgentIndication,
+						Message:    IndicationMessage(snapshotv1.VMSnapshotNoGuestAgentIndication),
+					},
+					{
+						Indication: snapshotv1.VMSnapshotOnlineSnapshotIndication,
+						Message:    IndicationMessage(snapshotv1.VMSnapshotOnlineSnapshotIndication),
+					},
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				testutils.ExpectEvent(recorder, "SuccessfulVirtualMachineSnapshotContentCreate")
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*createCalls).To(Equal(1))
+			})
+
+			It("should create VirtualMachineSnapshotContent with memory dump", func() {
+				storageClass := createStorageClass()
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				vm = updateVMWithMemoryDump(vm)
+				pvcs := createPersistentVolumeClaims()
+				md := memoryDumpPVC()
+				pvcSource.Add(&md)
+				pvcs = append(pvcs, md)
+				vmSnapshotContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+
+				vmSource.Add(vm)
+				storageClassSource.Add(storageClass)
+				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, vmSnapshotContent)
+				vmSnapshotSource.Add(vmSnapshot)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{
+					SourceUID:  &vmUID,
+					ReadyToUse: pointer.P(false),
+					Phase:      snapshotv1.InProgress,
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+						newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					},
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				testutils.ExpectEvent(recorder, "SuccessfulVirtualMachineSnapshotContentCreate")
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*createCalls).To(Equal(1))
+			})
+
+			Context("including memory", func() {
+				var vmSnapshot *snapshotv1.VirtualMachineSnapshot
+				var vm *v1.VirtualMachine
+
+				BeforeEach(func() {
+					vmSnapshot = createVMSnapshotInProgress()
+					vmSnapshot.Spec.IncludeMemoryDump = pointer.P(true)
+					vm = createLockedVM()
+					vm.Spec.RunStrategy = pointer.P(v1.RunStrategyAlways)
+					vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("64Mi"),
+					}
+					virtClient.EXPECT().CoreV1().Return(k8sClient.CoreV1()).AnyTimes()
+				})
+
+				expectOnlineSnapshotStatus := func() *snapshotv1.VirtualMachineSnapshot {
+					updatedSnapshot := vmSnapshot.DeepCopy()
+					updatedSnapshot.ResourceVersion = "1"
+					updatedSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{
+						SourceUID:  &vmUID,
+						ReadyToUse: pointer.P(false),
+						Phase:      snapshotv1.InProgress,
+						Conditions: []snapshotv1.Condition{
+							newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+							newReadyCondition(corev1.ConditionFalse, "Not ready"),
+						},
+					}
+					setSnapshotIndications(updatedSnapshot, []snapshotv1.Indication{
+						snapshotv1.VMSnapshotNoGuestAgentIndication,
+						snapshotv1.VMSnapshotOnlineSnapshotIndication,
+					})
+					return updatedSnapshot
+				}
+
+				It("should dump the guest memory before creating the content", func() {
+					vmiSource.Add(createVMI(vm))
+					vmSource.Add(vm)
+					storageClassSource.Add(createStorageClass())
+					addVolumeSnapshotClass(createVolumeSnapshotClasses()[0])
+					for _, pvc := range createPersistentVolumeClaims() {
+						pvcSource.Add(&pvc)
+					}
+
+					var createdPVC *corev1.PersistentVolumeClaim
+					k8sClient.Fake.PrependReactor("create", "persistentvolumeclaims", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						createdPVC = action.(testing.CreateAction).GetObject().(*corev1.PersistentVolumeClaim)
+						return true, createdPVC, nil
+					})
+					patchBytes, err := patch.New(
+						patch.WithTest("/status/memoryDumpRequest", nil),
+						patch.WithAdd("/status/memoryDumpRequest", &v1.VirtualMachineMemoryDumpRequest{
+							ClaimName: memoryDumpClaimName(vmSnapshot),
+							Phase:     v1.MemoryDumpAssociating,
+						}),
+					).GeneratePayload()
+					Expect(err).ToNot(HaveOccurred())
+					vmInterface.EXPECT().PatchStatus(context.Background(), vm.Name, types.JSONPatchType, patchBytes, metav1.PatchOptions{}).Return(vm, nil).Times(1)
+					updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, expectOnlineSnapshotStatus())
+
+					addVirtualMachineSnapshot(vmSnapshot)
+					controller.processVMSnapshotWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+					Expect(createdPVC).ToNot(BeNil())
+					Expect(createdPVC.Name).To(Equal(memoryDumpClaimName(vmSnapshot)))
+					Expect(createdPVC.OwnerReferences).To(ConsistOf(HaveField("UID", vmSnapshot.UID)))
+					Expect(createdPVC.Spec.StorageClassName).To(HaveValue(Equal(storageClassName)))
+					Expect(createdPVC.Spec.Resources.Requests.Storage().Cmp(resource.MustParse("164Mi"))).To(BeNumerically(">", 0))
+				})
+
+				It("should wait for the memory dump to complete", func() {
+					vm.Status.MemoryDumpRequest = &v1.VirtualMachineMemoryDumpRequest{
+						ClaimName: memoryDumpClaimName(vmSnapshot),
+						Phase:     v1.MemoryDumpInProgress,
+					}
+					vmiSource.Add(createVMI(vm))
+					vmSource.Add(vm)
+					storageClassSource.Add(createStorageClass())
+					addVolumeSnapshotClass(createVolumeSnapshotClasses()[0])
+					updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, expectOnlineSnapshotStatus())
+
+					addVirtualMachineSnapshot(vmSnapshot)
+					controller.processVMSnapshotWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+				})
+
+				It("should fail the snapshot when the memory dump failed", func() {
+					vm.Status.MemoryDumpRequest = &v1.VirtualMachineMemoryDumpRequest{
+						ClaimName: memoryDumpClaimName(vmSnapshot),
+						Phase:     v1.MemoryDumpFailed,
+						Message:   "no space left on device",
+					}
+					vmiSource.Add(createVMI(vm))
+					vmSource.Add(vm)
+					storageClassSource.Add(createStorageClass())
+					addVolumeSnapshotClass(createVolumeSnapshotClasses()[0])
+
+					message := "memory dump failed for vm testvm: no space left on device"
+					updatedSnapshot := vmSnapshot.DeepCopy()
+					updatedSnapshot.ResourceVersion = "1"
+					updatedSnapshot.Status.Phase = snapshotv1.Failed
+					updatedSnapshot.Status.Error = &snapshotv1.Error{
+						Time:    timeFunc(),
+						Message: &message,
+						Reason:  pointer.P(snapshotv1.MemoryDumpFailedErrorReason),
+					}
+					updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+						newFailureCondition(corev1.ConditionTrue, message),
+						newProgressingCondition(corev1.ConditionFalse, "Operation failed"),
+						newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					}
+					updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+					addVirtualMachineSnapshot(vmSnapshot)
+					controller.processVMSnapshotWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+				})
+
+				It("should track the memory dump in the content", func() {
+					claimName := memoryDumpClaimName(vmSnapshot)
+					vm.Spec.RunStrategy = pointer.P(v1.RunStrategyHalted)
+					vm = updateVMWithMemoryDump(vm)
+					memoryVolume := &vm.Spec.Template.Spec.Volumes[len(vm.Spec.Template.Spec.Volumes)-1]
+					memoryVolume.Name = claimName
+					memoryVolume.MemoryDump.ClaimName = claimName
+					vm.Status.MemoryDumpRequest.ClaimName = claimName
+					vm.Status.MemoryDumpRequest.FileName = pointer.P("memory.dump")
+
+					pvcs := createPersistentVolumeClaims()
+					md := memoryDumpPVC()
+					md.Name = claimName
+					pvcSource.Add(&md)
+					vmSnapshotContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+					vmSnapshotContent.Spec.VolumeBackups = append(vmSnapshotContent.Spec.VolumeBackups, snapshotv1.VolumeBackup{
+						VolumeName: claimName,
+						PersistentVolumeClaim: snapshotv1.PersistentVolumeClaim{
+							ObjectMeta: md.ObjectMeta,
+							Spec:       md.Spec,
+						},
+						VolumeSnapshotName: pointer.P(fmt.Sprintf("vmsnapshot-%s-volume-%s", vmSnapshot.UID, claimName)),
+					})
+					vmSnapshotContent.Spec.MemoryDumpBackup = &snapshotv1.MemoryDumpBackup{
+						VolumeName: claimName,
+						FileName:   pointer.P("memory.dump"),
+					}
+
+					vmSource.Add(vm)
+					storageClassSource.Add(createStorageClass())
+					createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, vmSnapshotContent)
+					addVolumeSnapshotClass(createVolumeSnapshotClasses()[0])
+
+					updatedSnapshot := vmSnapshot.DeepCopy()
+					updatedSnapshot.ResourceVersion = "1"
+					updatedSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{
+						SourceUID:  &vmUID,
+						ReadyToUse: pointer.P(false),
+						Phase:      snapshotv1.InProgress,
+						Conditions: []snapshotv1.Condition{
+							newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+							newReadyCondition(corev1.ConditionFalse, "Not ready"),
+						},
+					}
+					updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+					addVirtualMachineSnapshot(vmSnapshot)
+					controller.processVMSnapshotWorkItem()
+					testutils.ExpectEvent(recorder, "SuccessfulVirtualMachineSnapshotContentCreate")
+					Expect(*updateStatusCalls).To(Equal(1))
+					Expect(*createCalls).To(Equal(1))
+				})
+
+				It("should dissociate the memory dump once the snapshot is done", func() {
+					vmSnapshot = createVMSnapshotSuccess()
+					vmSnapshot.Spec.IncludeMemoryDump = pointer.P(true)
+					vm.Finalizers = []string{}
+					vm.Status.MemoryDumpRequest = &v1.VirtualMachineMemoryDumpRequest{
+						ClaimName: memoryDumpClaimName(vmSnapshot),
+						Phase:     v1.MemoryDumpCompleted,
+					}
+					statusUpdate := vm.DeepCopy()
+					statusUpdate.ResourceVersion = "1"
+					statusUpdate.Status.SnapshotInProgress = nil
+					vmSource.Add(vm)
+
+					patchBytes, err := patch.New(
+						patch.WithTest("/status/memoryDumpRequest", vm.Status.MemoryDumpRequest),
+						patch.WithReplace("/status/memoryDumpRequest", &v1.VirtualMachineMemoryDumpRequest{
+							ClaimName: memoryDumpClaimName(vmSnapshot),
+							Phase:     v1.MemoryDumpDissociating,
+							Remove:    true,
+						}),
+					).GeneratePayload()
+					Expect(err).ToNot(HaveOccurred())
+					vmInterface.EXPECT().PatchStatus(context.Background(), vm.Name, types.JSONPatchType, patchBytes, metav1.PatchOptions{}).Return(vm, nil).Times(1)
+					vmInterface.EXPECT().UpdateStatus(context.Background(), statusUpdate, metav1.UpdateOptions{}).Return(statusUpdate, nil).Times(1)
+					addVirtualMachineSnapshot(vmSnapshot)
+					controller.processVMSnapshotWorkItem()
+				})
+
+				It("should not dissociate the memory dump of another snapshot", func() {
+					vmSnapshot = createVMSnapshotSuccess()
+					vmSnapshot.Spec.IncludeMemoryDump = pointer.P(true)
+					vm.Finalizers = []string{}
+					vm.Status.MemoryDumpRequest = &v1.VirtualMachineMemoryDumpRequest{
+						ClaimName: "user-dump",
+						Phase:     v1.MemoryDumpCompleted,
+					}
+					statusUpdate := vm.DeepCopy()
+					statusUpdate.ResourceVersion = "1"
+					statusUpdate.Status.SnapshotInProgress = nil
+					vmSource.Add(vm)
+
+					vmInterface.EXPECT().PatchStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+					vmInterface.EXPECT().UpdateStatus(context.Background(), statusUpdate, metav1.UpdateOptions{}).Return(statusUpdate, nil).Times(1)
+					addVirtualMachineSnapshot(vmSnapshot)
+					controller.processVMSnapshotWorkItem()
+				})
+
+				It("should hand the memory dump PVC over to the content", func() {
+					content := createVMSnapshotContent()
+					content.UID = contentUID
+					content.Spec.MemoryDumpBackup = &snapshotv1.MemoryDumpBackup{VolumeName: content.Spec.VolumeBackups[0].VolumeName}
+					pvc := createPersistentVolumeClaims()[0]
+					pvc.OwnerReferences = []metav1.OwnerReference{
+						*metav1.NewControllerRef(vmSnapshot, snapshotv1.SchemeGroupVersion.WithKind("VirtualMachineSnapshot")),
+					}
+					pvcSource.Add(&pvc)
+					syncCaches(stop)
+
+					var patched bool
+					k8sClient.Fake.PrependReactor("patch", "persistentvolumeclaims", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						patch := action.(testing.PatchAction)
+						Expect(patch.GetName()).To(Equal(pvc.Name))
+						Expect(string(patch.GetPatch())).To(ContainSubstring(`"op":"replace","path":"/metadata/ownerReferences"`))
+						Expect(string(patch.GetPatch())).To(ContainSubstring(string(contentUID)))
+						patched = true
+						return true, nil, nil
+					})
+
+					Eventually(func() bool {
+						Expect(controller.adoptMemoryDumpPVC(content)).To(Succeed())
+						return patched
+					}).Should(BeTrue())
+				})
+
+				It("should indicate the memory was captured", func() {
+					content := createVMSnapshotContent()
+					content.Spec.MemoryDumpBackup = &snapshotv1.MemoryDumpBackup{VolumeName: memoryDumpClaimName(vmSnapshot)}
+					vmSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{}
+
+					updateMemoryIndication(vmSnapshot, content)
+					Expect(vmSnapshot.Status.Indications).To(ConsistOf(snapshotv1.VMSnapshotMemoryDumpIndication))
+					Expect(vmSnapshot.Status.SourceIndications).To(ConsistOf(snapshotv1.SourceIndication{
+						Indication: snapshotv1.VMSnapshotMemoryDumpIndication,
+						Message:    IndicationMessage(snapshotv1.VMSnapshotMemoryDumpIndication),
+					}))
+				})
+			})
+
+			DescribeTable("should capture ephemeral volumes only when requested", func(includeEphemeral bool) {
+				storageClass := createStorageClass()
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.IncludeEphemeralVolumes = includeEphemeral
+				vm := createLockedVM()
+				vm.Spec.Template.Spec.Volumes = append(vm.Spec.Template.Spec.Volumes, v1.Volume{
+					Name: "ephemeral",
+					VolumeSource: v1.VolumeSource{
+						Ephemeral: &v1.EphemeralVolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: "ephemeral-pvc",
+							},
+						},
+					},
+				})
+				pvcs := createPersistentVolumeClaims()
+				vmSnapshotContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+				if includeEphemeral {
+					ephemeralPVC := pvcs[0].DeepCopy()
+					ephemeralPVC.Name = "ephemeral-pvc"
+					pvcSource.Add(ephemeralPVC)
+					vmSnapshotContent.Spec.VolumeBackups = append(vmSnapshotContent.Spec.VolumeBackups, snapshotv1.VolumeBackup{
+						VolumeName: "ephemeral",
+						PersistentVolumeClaim: snapshotv1.PersistentVolumeClaim{
+							ObjectMeta: ephemeralPVC.ObjectMeta,
+							Spec:       ephemeralPVC.Spec,
+						},
+						VolumeSnapshotName: pointer.P(fmt.Sprintf("vmsnapshot-%s-volume-ephemeral", vmSnapshot.UID)),
+					})
+				}
+
+				vmSource.Add(vm)
+				storageClassSource.Add(storageClass)
+				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, vmSnapshotContent)
+				vmSnapshotSource.Add(vmSnapshot)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{
+					SourceUID:  &vmUID,
+					ReadyToUse: pointer.P(false),
+					Phase:      snapshotv1.InProgress,
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+						newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					},
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				testutils.ExpectEvent(recorder, "SuccessfulVirtualMachineSnapshotContentCreate")
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*createCalls).To(Equal(1))
+			},
+				Entry("excluded by default", false),
+				Entry("included with IncludeEphemeralVolumes", true),
+			)
+
+			It("should create online VirtualMachineSnapshotContent with volume migration", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				vm.Spec.RunStrategy = pointer.P(v1.RunStrategyAlways)
+				vmRevision := createVMRevision(vm)
+				crSource.Add(vmRevision)
+
+				vm.ObjectMeta.Annotations = map[string]string{}
+
+				vm.Spec.DataVolumeTemplates[0].Name = "alpine-dv-dest"
+				vm.Spec.Template.Spec.Volumes[0].VolumeSource.DataVolume.Name = "alpine-dv-dest"
+
+				vmi := createVMI(vm)
+				vmi.Status.VirtualMachineRevisionName = vmRevisionName
+				vmiSource.Add(vmi)
+
+				vm.ObjectMeta.Generation = 2
+				pvcs := createPVCsForVM(vm)
+				for i := range pvcs {
+					pvcSource.Add(&pvcs[i])
+				}
+
+				expectedContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+				vm.Spec.Template.Spec.Domain.Resources.Requests = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("64Mi"),
+				}
+				vmSource.Add(vm)
+				storageClass := createStorageClass()
+				storageClassSource.Add(storageClass)
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, expectedContent)
+				vmSnapshotSource.Add(vmSnapshot)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{
+					SourceUID:  &vmUID,
+					ReadyToUse: pointer.P(false),
+					Phase:      snapshotv1.InProgress,
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+						newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					},
+				}
+				updatedSnapshot.Status.Indications = []snapshotv1.Indication{
+					snapshotv1.VMSnapshotNoGuestAgentIndication,
+					snapshotv1.VMSnapshotOnlineSnapshotIndication,
+				}
+				updatedSnapshot.Status.SourceIndications = []snapshotv1.SourceIndication{
+					{
+						Indication: snapshotv1.VMSnapshotNoGuestAgentIndication,
+						Message:    IndicationMessage(snapshotv1.VMSnapshotNoGuestAgentIndication),
+					},
+					{
+						Indication: snapshotv1.VMSnapshotOnlineSnapshotIndication,
+						Message:    IndicationMessage(snapshotv1.VMSnapshotOnlineSnapshotIndication),
+					},
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				testutils.ExpectEvent(recorder, "SuccessfulVirtualMachineSnapshotContentCreate")
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*createCalls).To(Equal(1))
+			})
+
+			It("should create online VirtualMachineSnapshotContent with hotplugged volume", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				vm.Spec.RunStrategy = pointer.P(v1.RunStrategyAlways)
+				vmRevision := createVMRevision(vm)
+				crSource.Add(vmRevision)
+
+				vm.ObjectMeta.Annotations = map[string]string{}
+
+				vmi := createVMI(vm)
+				vmi.Status.VirtualMachineRevisionName = vmRevisionName
+				vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+					Name: "hotplug-disk",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+							PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: "hotplug-pvc",
+							},
+							Hotpluggable: true,
+						},
+					},
+				})
+				hotplugDisk := v1.Disk{
+					Name: "hotplug-disk",
+					DiskDevice: v1.DiskDevice{
+						Disk: &v1.DiskTarget{Bus: v1.DiskBusVirtio},
+					},
+				}
+				vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, hotplugDisk)
+				vmiSource.Add(vmi)
+
+				vm.ObjectMeta.Generation = 2
+				pvcs := createPVCsForVM(vm)
+				hotplugPVC := pvcs[0].DeepCopy()
+				hotplugPVC.Name = "hotplug-pvc"
+				for i := range pvcs {
+					pvcSource.Add(&pvcs[i])
+				}
+				pvcSource.Add(hotplugPVC)
+
+				expectedContent := createVirtualMachineSnapshotContent(vmSnapshot, vm, pvcs)
+				expectedContent.Spec.VolumeBackups = append(expectedContent.Spec.VolumeBackups, snapshotv1.VolumeBackup{
+					VolumeName: "hotplug-disk",
+					PersistentVolumeClaim: snapshotv1.PersistentVolumeClaim{
+						ObjectMeta: hotplugPVC.ObjectMeta,
+						Spec:       hotplugPVC.Spec,
+					},
+					VolumeSnapshotName: pointer.P(fmt.Sprintf("vmsnapshot-%s-volume-hotplug-disk", vmSnapshot.UID)),
+					Hotplug:            true,
+					Disk:               &hotplugDisk,
+				})
+				vmSource.Add(vm)
+				storageClass := createStorageClass()
+				storageClassSource.Add(storageClass)
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+				createCalls := expectVMSnapshotContentCreate(vmSnapshotClient, expectedContent)
+				vmSnapshotSource.Add(vmSnapshot)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status = &snapshotv1.VirtualMachineSnapshotStatus{
+					SourceUID:  &vmUID,
+					ReadyToUse: pointer.P(false),
+					Phase:      snapshotv1.InProgress,
+					Conditions: []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+						newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					},
+				}
+				updatedSnapshot.Status.Indications = []snapshotv1.Indication{
+					snapshotv1.VMSnapshotNoGuestAgentIndication,
+					snapshotv1.VMSnapshotOnlineSnapshotIndication,
+				}
+				updatedSnapshot.Status.SourceIndications = []snapshotv1.SourceIndication{
+					{
+						Indication: snapshotv1.VMSnapshotNoGuestAgentIndication,
+						Message:    IndicationMessage(snapshotv1.VMSnapshotNoGuestAgentIndication),
+					},
 					{
 						Indication: snapshotv1.VMSnapshotOnlineSnapshotIndication,
 						Message:    IndicationMessage(snapshotv1.VMSnapshotOnlineSnapshotIndication),
@@ -1151,6 +2081,7 @@ var _ = Describe("Snapshot controlleer", func() {
 				updatedSnapshot.Status.CreationTime = timeFunc()
 				updatedSnapshot.Status.ReadyToUse = pointer.P(true)
 				updatedSnapshot.Status.Phase = snapshotv1.Succeeded
+				updatedSnapshot.Status.ConsistencyLevel = snapshotv1.ApplicationConsistent
 				updatedSnapshot.Status.Indications = nil
 				updatedSnapshot.Status.SourceIndications = nil
 				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
@@ -1182,9 +2113,51 @@ var _ = Describe("Snapshot controlleer", func() {
 						}},
 					},
 				})
-				// Add volume to vm in content that is not in the volumebackup list
-				//this is a simulation for a volume that is not included in the snapshot
-				vmSnapshotContent.Spec.Source.VirtualMachine.Spec = *vm.Spec.DeepCopy()
+				// Add volume to vm in content that is not in the volumebackup list
+				//this is a simulation for a volume that is not included in the snapshot
+				vmSnapshotContent.Spec.Source.VirtualMachine.Spec = *vm.Spec.DeepCopy()
+
+				vmSnapshot := createVMSnapshotInProgress()
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.SourceUID = &vmUID
+				updatedSnapshot.Status.VirtualMachineSnapshotContentName = &vmSnapshotContent.Name
+				updatedSnapshot.Status.CreationTime = timeFunc()
+				updatedSnapshot.Status.ReadyToUse = pointer.P(true)
+				updatedSnapshot.Status.Phase = snapshotv1.Succeeded
+				updatedSnapshot.Status.ConsistencyLevel = snapshotv1.ApplicationConsistent
+				updatedSnapshot.Status.Indications = nil
+				updatedSnapshot.Status.SourceIndications = nil
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
+					newReadyCondition(corev1.ConditionTrue, "Ready"),
+				}
+				updatedSnapshot.Status.SnapshotVolumes = &snapshotv1.SnapshotVolumesLists{
+					IncludedVolumes: []string{diskName},
+					ExcludedVolumes: []string{"disk2"},
+				}
+
+				vmSource.Add(vm)
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+				addVirtualMachineSnapshot(vmSnapshot)
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should report hotplug volumes in VirtualMachineSnapshotStatus", func() {
+				vmSnapshotContent := createReadyVMSnapshotContent()
+				vmSnapshotContent.Spec.VolumeBackups = append(vmSnapshotContent.Spec.VolumeBackups, snapshotv1.VolumeBackup{
+					VolumeName: "hotplug-disk",
+					PersistentVolumeClaim: snapshotv1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testNamespace,
+							Name:      "hotplug-pvc",
+						},
+					},
+					VolumeSnapshotName: pointer.P("vmsnapshot-volume-hotplug-disk"),
+					Hotplug:            true,
+				})
 
 				vmSnapshot := createVMSnapshotInProgress()
 				updatedSnapshot := vmSnapshot.DeepCopy()
@@ -1194,6 +2167,7 @@ var _ = Describe("Snapshot controlleer", func() {
 				updatedSnapshot.Status.CreationTime = timeFunc()
 				updatedSnapshot.Status.ReadyToUse = pointer.P(true)
 				updatedSnapshot.Status.Phase = snapshotv1.Succeeded
+				updatedSnapshot.Status.ConsistencyLevel = snapshotv1.ApplicationConsistent
 				updatedSnapshot.Status.Indications = nil
 				updatedSnapshot.Status.SourceIndications = nil
 				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
@@ -1201,10 +2175,12 @@ var _ = Describe("Snapshot controlleer", func() {
 					newReadyCondition(corev1.ConditionTrue, "Ready"),
 				}
 				updatedSnapshot.Status.SnapshotVolumes = &snapshotv1.SnapshotVolumesLists{
-					IncludedVolumes: []string{diskName},
-					ExcludedVolumes: []string{"disk2"},
+					IncludedVolumes: []string{diskName, "hotplug-disk"},
+					HotplugVolumes:  []string{"hotplug-disk"},
 				}
 
+				vm := createLockedVM()
+
 				vmSource.Add(vm)
 				vmSnapshotContentSource.Add(vmSnapshotContent)
 				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
@@ -1232,6 +2208,8 @@ var _ = Describe("Snapshot controlleer", func() {
 					newReadyCondition(corev1.ConditionFalse, "Not ready"),
 				}
 				updatedSnapshot.Status.Error = vmSnapshotContent.Status.Error
+				updatedSnapshot.Status.RetryCount = 1
+				updatedSnapshot.Status.NextRetryTime = &metav1.Time{Time: timeFunc().Add(snapshotRetryInterval)}
 
 				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
 
@@ -1289,6 +2267,72 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*updateStatusCalls).To(Equal(1))
 			})
 
+			It("should report failure when VolumeSnapshots of a succeeded VirtualMachineSnapshot are no longer ready", func() {
+				vmSnapshot := createVMSnapshotSuccess()
+				vm := createVM()
+				errorMessage := "VolumeSnapshots (vol1) no longer ready to use"
+				vmSnapshotContent := createErrorVMSnapshotContent(errorMessage)
+				vmSnapshotContent.Status.CreationTime = timeFunc()
+				vmSnapshotContent.Status.Error.Reason = pointer.P(snapshotv1.VolumeSnapshotNotReadyErrorReason)
+
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+				vmSource.Add(vm)
+				addVirtualMachineSnapshot(vmSnapshot)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.VirtualMachineSnapshotContentName = &vmSnapshotContent.Name
+				updatedSnapshot.Status.ReadyToUse = pointer.P(false)
+				updatedSnapshot.Status.Error = vmSnapshotContent.Status.Error
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "In error state"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					newFailureCondition(corev1.ConditionTrue, errorMessage),
+				}
+				updatedSnapshot.Status.SnapshotVolumes = &snapshotv1.SnapshotVolumesLists{
+					IncludedVolumes: []string{diskName},
+				}
+
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should clear failure when VolumeSnapshots of a succeeded VirtualMachineSnapshot are ready again", func() {
+				vmSnapshot := createVMSnapshotSuccess()
+				vmSnapshot.Status.ReadyToUse = pointer.P(false)
+				vmSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "In error state"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					newFailureCondition(corev1.ConditionTrue, "VolumeSnapshots (vol1) no longer ready to use"),
+				}
+				vm := createVM()
+				vmSnapshotContent := createReadyVMSnapshotContent()
+
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+				vmSource.Add(vm)
+				addVirtualMachineSnapshot(vmSnapshot)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.VirtualMachineSnapshotContentName = &vmSnapshotContent.Name
+				updatedSnapshot.Status.ReadyToUse = pointer.P(true)
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionFalse, "Operation complete"),
+					newReadyCondition(corev1.ConditionTrue, "Ready"),
+					newFailureCondition(corev1.ConditionFalse, "VolumeSnapshots ready"),
+				}
+				updatedSnapshot.Status.SnapshotVolumes = &snapshotv1.SnapshotVolumesLists{
+					IncludedVolumes: []string{diskName},
+				}
+
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
 			It("shouldn't timeout if VirtualMachineSnapshot succeeded", func() {
 				vmSnapshot := createVMSnapshotSuccess()
 				negativeDeadline, _ := time.ParseDuration("-1m")
@@ -1328,6 +2372,102 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*contentDeletes).To(Equal(1))
 			})
 
+			Context("with a DeadlineGracePeriod", func() {
+				const (
+					failureDeadline = time.Minute
+					gracePeriod     = 5 * time.Minute
+				)
+
+				// createPastDeadlineSnapshot returns a snapshot created age ago
+				// whose FailureDeadline passed, but not its DeadlineGracePeriod
+				createPastDeadlineSnapshot := func(age time.Duration) *snapshotv1.VirtualMachineSnapshot {
+					vmSnapshot := createVMSnapshotInProgress()
+					vmSnapshot.CreationTimestamp = metav1.NewTime(timeStamp.Add(-age))
+					vmSnapshot.Spec.FailureDeadline = &metav1.Duration{Duration: failureDeadline}
+					vmSnapshot.Spec.DeadlineGracePeriod = &metav1.Duration{Duration: gracePeriod}
+					return vmSnapshot
+				}
+
+				createContentWithVolumeSnapshotError := func(volumeSnapshotError *snapshotv1.Error) *snapshotv1.VirtualMachineSnapshotContent {
+					vmSnapshotContent := createVMSnapshotContent()
+					vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+						ReadyToUse: pointer.P(false),
+					}
+					for _, vb := range vmSnapshotContent.Spec.VolumeBackups {
+						vmSnapshotContent.Status.VolumeSnapshotStatus = append(vmSnapshotContent.Status.VolumeSnapshotStatus, snapshotv1.VolumeSnapshotStatus{
+							VolumeSnapshotName: *vb.VolumeSnapshotName,
+							ReadyToUse:         pointer.P(false),
+							Error:              volumeSnapshotError,
+						})
+					}
+					return vmSnapshotContent
+				}
+
+				expectSnapshotUpdates := func() *[]*snapshotv1.VirtualMachineSnapshot {
+					var updates []*snapshotv1.VirtualMachineSnapshot
+					vmSnapshotClient.Fake.PrependReactor("update", "virtualmachinesnapshots", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						update, ok := action.(testing.UpdateAction)
+						Expect(ok).To(BeTrue())
+						updates = append(updates, update.GetObject().(*snapshotv1.VirtualMachineSnapshot))
+						return true, update.GetObject(), nil
+					})
+					vmSnapshotClient.Fake.PrependReactor("delete", "virtualmachinesnapshotcontents", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						return true, nil, nil
+					})
+					return &updates
+				}
+
+				DescribeTable("should extend the deadline", func(volumeSnapshotError *snapshotv1.Error, expectExtended bool) {
+					vmSnapshot := createPastDeadlineSnapshot(2 * time.Minute)
+					vmSnapshotContent := createContentWithVolumeSnapshotError(volumeSnapshotError)
+					vmSource.Add(createLockedVM())
+					vmSnapshotContentSource.Add(vmSnapshotContent)
+					addVirtualMachineSnapshot(vmSnapshot)
+
+					updates := expectSnapshotUpdates()
+					controller.processVMSnapshotWorkItem()
+					Expect(*updates).To(HaveLen(1))
+					update := (*updates)[0]
+					Expect(deadlineExtended(update)).To(Equal(expectExtended))
+					if expectExtended {
+						Expect(update.Status.Phase).To(Equal(snapshotv1.InProgress))
+						Expect(update.Status.Conditions).To(ContainElement(
+							newDeadlineExtendedCondition(corev1.ConditionTrue, "Deadline extended by 5m0s while volume snapshots are progressing"),
+						))
+					} else {
+						Expect(update.Status.Phase).To(Equal(snapshotv1.Failed))
+					}
+				},
+					Entry("when volume snapshots are progressing", nil, true),
+					Entry("not when a volume snapshot reported an error", &snapshotv1.Error{Message: pointer.P("error")}, false),
+				)
+
+				It("should fail once the extended deadline passed", func() {
+					vmSnapshot := createPastDeadlineSnapshot(failureDeadline + gracePeriod + time.Second)
+					vmSnapshot.Status.Conditions = append(vmSnapshot.Status.Conditions,
+						newDeadlineExtendedCondition(corev1.ConditionTrue, "Deadline extended by 5m0s while volume snapshots are progressing"))
+					vmSource.Add(createLockedVM())
+					vmSnapshotContentSource.Add(createContentWithVolumeSnapshotError(nil))
+					addVirtualMachineSnapshot(vmSnapshot)
+
+					updates := expectSnapshotUpdates()
+					controller.processVMSnapshotWorkItem()
+					Expect(*updates).To(HaveLen(1))
+					Expect((*updates)[0].Status.Phase).To(Equal(snapshotv1.Failed))
+				})
+
+				DescribeTable("should only terminate the snapshot when the volume snapshots of the content do not progress", func(volumeSnapshotError *snapshotv1.Error, expectTerminating bool) {
+					vmSnapshot := createPastDeadlineSnapshot(2 * time.Minute)
+					vmSnapshotContent := createContentWithVolumeSnapshotError(volumeSnapshotError)
+
+					Expect(vmSnapshotTerminating(vmSnapshot, vmSnapshotContent)).To(Equal(expectTerminating))
+					Expect(timeUntilDeadline(vmSnapshot, vmSnapshotContent) < 0).To(Equal(expectTerminating))
+				},
+					Entry("progressing", nil, false),
+					Entry("with a volume snapshot error", &snapshotv1.Error{Message: pointer.P("error")}, true),
+				)
+			})
+
 			It("should create VolumeSnapshot", func() {
 				vm := createLockedVM()
 				storageClass := createStorageClass()
@@ -1568,6 +2708,8 @@ var _ = Describe("Snapshot controlleer", func() {
 					newReadyCondition(corev1.ConditionFalse, "Not ready"),
 				}
 				updatedSnapshot.Status.Error = vmSnapshotContent.Status.Error
+				updatedSnapshot.Status.RetryCount = 1
+				updatedSnapshot.Status.NextRetryTime = &metav1.Time{Time: timeFunc().Add(snapshotRetryInterval)}
 
 				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
 
@@ -1613,15 +2755,306 @@ var _ = Describe("Snapshot controlleer", func() {
 				}
 				addVirtualMachineSnapshot(vmSnapshot)
 
-				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, vmSnapshot)
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, vmSnapshot)
+
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(0))
+			})
+
+			It("should freeze vm with online snapshot and guest agent", func() {
+				storageClass := createStorageClass()
+				vmSnapshot := createVMSnapshotInProgress()
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.UID = contentUID
+				vm := createLockedVM()
+				vmSource.Add(vm)
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+
+				vmi := createVMI(vm)
+				agentCondition := v1.VirtualMachineInstanceCondition{
+					Type:          v1.VirtualMachineInstanceAgentConnected,
+					LastProbeTime: metav1.Now(),
+					Status:        corev1.ConditionTrue,
+				}
+				vmi.Status.Conditions = append(vmi.Status.Conditions, agentCondition)
+				vmiSource.Add(vmi)
+
+				updatedContent := vmSnapshotContent.DeepCopy()
+				updatedContent.ResourceVersion = "1"
+				updatedContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse: pointer.P(false),
+				}
+
+				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
+				for i := range volumeSnapshots {
+					vss := snapshotv1.VolumeSnapshotStatus{
+						VolumeSnapshotName: volumeSnapshots[i].Name,
+					}
+					updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, vss)
+				}
+
+				storageClassSource.Add(storageClass)
+
+				vmiInterface.EXPECT().Freeze(context.Background(), vm.Name, 0*time.Second).Return(nil).Times(1)
+				snapshotCreates := expectVolumeSnapshotCreates(k8sSnapshotClient, volumeSnapshotClass.Name, vmSnapshotContent)
+				updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+				vmSnapshotSource.Add(vmSnapshot)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+				controller.processVMSnapshotContentWorkItem()
+				testutils.ExpectEvent(recorder, "SuccessfulVolumeSnapshotCreate")
+				Expect(*updateStatusCalls).To(Equal(1))
+				Expect(*snapshotCreates).To(Equal(1))
+			})
+
+			Context("with hooks", func() {
+				var patchedSnapshot *snapshotv1.VirtualMachineSnapshot
+
+				createVMSnapshotWithHooks := func() *snapshotv1.VirtualMachineSnapshot {
+					vmSnapshot := createVMSnapshotInProgress()
+					vmSnapshot.Spec.Hooks = &snapshotv1.SnapshotHooks{
+						PreFreeze: []snapshotv1.SnapshotHook{
+							{Name: "flush", Command: "/usr/bin/flush", Args: []string{"--all"}},
+						},
+						PostThaw: []snapshotv1.SnapshotHook{
+							{Name: "unlock", Command: "/usr/bin/unlock", Timeout: &metav1.Duration{Duration: 5 * time.Second}},
+						},
+					}
+					return vmSnapshot
+				}
+
+				freezeHook := func(hook snapshotv1.SnapshotHook) v1.FreezeHook {
+					return v1.FreezeHook{
+						Name:           hook.Name,
+						Command:        hook.Command,
+						Args:           hook.Args,
+						TimeoutSeconds: int32(hookTimeout(hook).Seconds()),
+					}
+				}
+
+				// the requests carrying hooks run in the background and
+				// enqueue the content once they completed
+				processHookRequest := func() {
+					controller.processVMSnapshotContentWorkItem()
+					Eventually(controller.vmSnapshotContentQueue.Len).Should(Equal(1))
+					controller.processVMSnapshotContentWorkItem()
+				}
+
+				trackVMSnapshotPatches := func(vmSnapshot *snapshotv1.VirtualMachineSnapshot) {
+					patchedSnapshot = vmSnapshot.DeepCopy()
+					vmSnapshotClient.Fake.PrependReactor("patch", "virtualmachinesnapshots", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+						patch, ok := action.(testing.PatchAction)
+						Expect(ok).To(BeTrue())
+
+						updated := &snapshotv1.VirtualMachineSnapshot{}
+						Expect(applyPatch(patch.GetPatch(), patchedSnapshot, updated)).To(Succeed())
+						patchedSnapshot = updated
+
+						return true, updated, nil
+					})
+				}
+
+				addVMIWithGuestAgent := func(vm *v1.VirtualMachine) {
+					vmi := createVMI(vm)
+					vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+						Type:          v1.VirtualMachineInstanceAgentConnected,
+						LastProbeTime: metav1.Now(),
+						Status:        corev1.ConditionTrue,
+					})
+					vmiSource.Add(vmi)
+				}
+
+				It("should run the pre-freeze hooks with the freeze of the vm", func() {
+					storageClass := createStorageClass()
+					storageClassSource.Add(storageClass)
+					vmSnapshot := createVMSnapshotWithHooks()
+					// the failure of an earlier attempt is cleared once the freeze succeeds
+					vmSnapshot.Annotations = map[string]string{
+						failedHookAnnotation: "pre-freeze hook flush failed: command /usr/bin/flush exited with code 1",
+					}
+					volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+					vmSnapshotContent := createVMSnapshotContent()
+					vmSnapshotContent.UID = contentUID
+					vm := createLockedVM()
+					vmSource.Add(vm)
+					vmSnapshotContentSource.Add(vmSnapshotContent)
+					addVMIWithGuestAgent(vm)
+
+					updatedContent := vmSnapshotContent.DeepCopy()
+					updatedContent.ResourceVersion = "1"
+					updatedContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+						ReadyToUse: pointer.P(false),
+					}
+					for _, volumeSnapshot := range createVolumeSnapshots(vmSnapshotContent) {
+						updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, snapshotv1.VolumeSnapshotStatus{
+							VolumeSnapshotName: volumeSnapshot.Name,
+						})
+					}
+
+					vmiInterface.EXPECT().Freeze(gomock.Any(), vm.Name, 0*time.Second, freezeHook(vmSnapshot.Spec.Hooks.PreFreeze[0])).Return(nil).Times(1)
+					trackVMSnapshotPatches(vmSnapshot)
+					snapshotCreates := expectVolumeSnapshotCreates(k8sSnapshotClient, volumeSnapshotClass.Name, vmSnapshotContent)
+					updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+					vmSnapshotSource.Add(vmSnapshot)
+					addVolumeSnapshotClass(volumeSnapshotClass)
+					processHookRequest()
+					testutils.ExpectEvent(recorder, "SuccessfulVolumeSnapshotCreate")
+					Expect(*updateStatusCalls).To(Equal(1))
+					Expect(*snapshotCreates).To(Equal(1))
+					Expect(patchedSnapshot.Annotations).To(HaveKey(postThawHooksAnnotation))
+					Expect(patchedSnapshot.Annotations).ToNot(HaveKey(failedHookAnnotation))
+				})
+
+				It("should cap the hook timeout", func() {
+					Expect(hookTimeout(snapshotv1.SnapshotHook{Timeout: &metav1.Duration{Duration: time.Hour}})).To(Equal(maxHookTimeout))
+					Expect(hookTimeout(snapshotv1.SnapshotHook{})).To(Equal(defaultHookTimeout))
+				})
+
+				It("should not freeze the vm when a pre-freeze hook fails", func() {
+					storageClass := createStorageClass()
+					storageClassSource.Add(storageClass)
+					vmSnapshot := createVMSnapshotWithHooks()
+					vmSnapshotContent := createVMSnapshotContent()
+					vmSnapshotContent.UID = contentUID
+					vmSnapshotContentSource.Add(vmSnapshotContent)
+					vm := createLockedVM()
+					vmSource.Add(vm)
+					addVMIWithGuestAgent(vm)
+
+					hookErr := fmt.Errorf("pre-freeze hook flush failed: command /usr/bin/flush exited with code 1")
+					formattedErr := fmt.Sprintf("%s %s: %v", failedFreezeMsg, vm.Name, hookErr)
+					updatedContent := vmSnapshotContent.DeepCopy()
+					updatedContent.ResourceVersion = "1"
+					updatedContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+						ReadyToUse: pointer.P(false),
+						Error: &snapshotv1.Error{
+							Time:    timeFunc(),
+							Message: &formattedErr,
+						},
+					}
+
+					vmiInterface.EXPECT().Freeze(gomock.Any(), vm.Name, 0*time.Second, freezeHook(vmSnapshot.Spec.Hooks.PreFreeze[0])).Return(hookErr).Times(1)
+					trackVMSnapshotPatches(vmSnapshot)
+					updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+					vmSnapshotSource.Add(vmSnapshot)
+					addVolumeSnapshotClass(createVolumeSnapshotClasses()[0])
+					processHookRequest()
+					Expect(*updateStatusCalls).To(Equal(1))
+					// the post-thaw hooks run once the snapshot is done with the guest
+					Expect(patchedSnapshot.Annotations).To(HaveKey(postThawHooksAnnotation))
+					Expect(patchedSnapshot.Annotations).To(HaveKeyWithValue(failedHookAnnotation, hookErr.Error()))
+				})
+
+				It("should run the post-thaw hooks once the vm is unfrozen and record a failure", func() {
+					storageClass := createStorageClass()
+					storageClassSource.Add(storageClass)
+					vm := createLockedVM()
+					vmSource.Add(vm)
+					addVMIWithGuestAgent(vm)
+
+					vmSnapshot := createVMSnapshotWithHooks()
+					vmSnapshot.Annotations = map[string]string{postThawHooksAnnotation: "true"}
+					vmSnapshotContent := createVMSnapshotContent()
+					vmSnapshotContent.UID = contentUID
+					vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+						ReadyToUse: pointer.P(false),
+					}
+					volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
+					for i := range volumeSnapshots {
+						volumeSnapshots[i].Status.ReadyToUse = pointer.P(true)
+						volumeSnapshots[i].Status.CreationTime = timeFunc()
+						volumeSnapshots[i].CreationTimestamp = metav1.NewTime(timeStamp.Add(-time.Minute))
+						volumeSnapshotSource.Add(&volumeSnapshots[i])
+						vmSnapshotContent.Status.VolumeSnapshotStatus = append(vmSnapshotContent.Status.VolumeSnapshotStatus, snapshotv1.VolumeSnapshotStatus{
+							VolumeSnapshotName: volumeSnapshots[i].Name,
+						})
+					}
+					vmSnapshotContentSource.Add(vmSnapshotContent)
+
+					updatedContent := vmSnapshotContent.DeepCopy()
+					updatedContent.ResourceVersion = "1"
+					updatedContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+						CreationTime: timeFunc(),
+						ReadyToUse:   pointer.P(true),
+					}
+					for i := range volumeSnapshots {
+						updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, snapshotv1.VolumeSnapshotStatus{
+							VolumeSnapshotName: volumeSnapshots[i].Name,
+							ReadyToUse:         volumeSnapshots[i].Status.ReadyToUse,
+							CreationTime:       volumeSnapshots[i].Status.CreationTime,
+							Duration:           &metav1.Duration{Duration: time.Minute},
+						})
+					}
+
+					// the guest is thawed again without the hooks after a failure
+					hookErr := fmt.Errorf("post-thaw hook unlock failed: command /usr/bin/unlock timed out")
+					gomock.InOrder(
+						vmiInterface.EXPECT().Unfreeze(gomock.Any(), vm.Name, freezeHook(vmSnapshot.Spec.Hooks.PostThaw[0])).Return(hookErr).Times(1),
+						vmiInterface.EXPECT().Unfreeze(context.Background(), vm.Name).Return(nil).Times(1),
+					)
+					trackVMSnapshotPatches(vmSnapshot)
+					updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+					vmSnapshotSource.Add(vmSnapshot)
+					addVolumeSnapshotClass(createVolumeSnapshotClasses()[0])
+					processHookRequest()
+					Expect(*updateStatusCalls).To(Equal(1))
+					Expect(patchedSnapshot.Annotations).ToNot(HaveKey(postThawHooksAnnotation))
+					Expect(patchedSnapshot.Annotations).To(HaveKeyWithValue(failedHookAnnotation, hookErr.Error()))
+				})
+
+				It("should set QuiesceFailed indication naming the failed hook", func() {
+					vm := createLockedVM()
+					vmSource.Add(vm)
+					addVMIWithGuestAgent(vm)
+
+					failedHook := "post-thaw hook unlock failed: command /usr/bin/unlock timed out"
+					vmSnapshot := createVMSnapshotWithHooks()
+					vmSnapshot.Annotations = map[string]string{failedHookAnnotation: failedHook}
+					vmSnapshotContent := createVMSnapshotContent()
+					vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+						ReadyToUse: pointer.P(false),
+					}
+					vmSnapshotContentSource.Add(vmSnapshotContent)
+					addVirtualMachineSnapshot(vmSnapshot)
+
+					updatedSnapshot := vmSnapshot.DeepCopy()
+					updatedSnapshot.Status.VirtualMachineSnapshotContentName = &vmSnapshotContent.Name
+					updatedSnapshot.Status.ReadyToUse = pointer.P(false)
+					updatedSnapshot.Status.Indications = []snapshotv1.Indication{
+						snapshotv1.VMSnapshotGuestAgentIndication,
+						snapshotv1.VMSnapshotOnlineSnapshotIndication,
+						snapshotv1.VMSnapshotQuiesceFailedIndication,
+					}
+					updatedSnapshot.Status.SourceIndications = []snapshotv1.SourceIndication{
+						{
+							Indication: snapshotv1.VMSnapshotGuestAgentIndication,
+							Message:    IndicationMessage(snapshotv1.VMSnapshotGuestAgentIndication),
+						},
+						{
+							Indication: snapshotv1.VMSnapshotOnlineSnapshotIndication,
+							Message:    IndicationMessage(snapshotv1.VMSnapshotOnlineSnapshotIndication),
+						},
+						{
+							Indication: snapshotv1.VMSnapshotQuiesceFailedIndication,
+							Message:    "Snapshot " + failedHook + ". Snapshot may not be application-consistent.",
+						},
+					}
+					updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+						newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+						newReadyCondition(corev1.ConditionFalse, "Not ready"),
+					}
+
+					updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
 
-				controller.processVMSnapshotWorkItem()
-				Expect(*updateStatusCalls).To(Equal(0))
+					controller.processVMSnapshotWorkItem()
+					Expect(*updateStatusCalls).To(Equal(1))
+				})
 			})
 
-			It("should freeze vm with online snapshot and guest agent", func() {
+			It("should pause vm instead of freezing when PauseDuringSnapshot is set", func() {
 				storageClass := createStorageClass()
 				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.PauseDuringSnapshot = true
 				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
 				vmSnapshotContent := createVMSnapshotContent()
 				vmSnapshotContent.UID = contentUID
@@ -1654,13 +3087,19 @@ var _ = Describe("Snapshot controlleer", func() {
 
 				storageClassSource.Add(storageClass)
 
-				vmiInterface.EXPECT().Freeze(context.Background(), vm.Name, 0*time.Second).Return(nil).Times(1)
+				pausedSnapshot := vmSnapshot.DeepCopy()
+				pausedSnapshot.ResourceVersion = "1"
+				pausedSnapshot.Annotations = map[string]string{pausedSourceAnnotation: "true"}
+				patchCalls := expectVMSnapshotPatch(vmSnapshotClient, vmSnapshot, pausedSnapshot)
+				vmiInterface.EXPECT().Pause(context.Background(), vm.Name, &v1.PauseOptions{}).Return(nil).Times(1)
+				vmiInterface.EXPECT().Freeze(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 				snapshotCreates := expectVolumeSnapshotCreates(k8sSnapshotClient, volumeSnapshotClass.Name, vmSnapshotContent)
 				updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
 				vmSnapshotSource.Add(vmSnapshot)
 				addVolumeSnapshotClass(volumeSnapshotClass)
 				controller.processVMSnapshotContentWorkItem()
 				testutils.ExpectEvent(recorder, "SuccessfulVolumeSnapshotCreate")
+				Expect(*patchCalls).To(Equal(1))
 				Expect(*updateStatusCalls).To(Equal(1))
 				Expect(*snapshotCreates).To(Equal(1))
 			})
@@ -1807,6 +3246,7 @@ var _ = Describe("Snapshot controlleer", func() {
 
 				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
 				for i := range volumeSnapshots {
+					volumeSnapshots[i].CreationTimestamp = metav1.NewTime(timeStamp.Add(-time.Minute))
 					volumeSnapshots[i].Status.ReadyToUse = &readyToUse
 					volumeSnapshots[i].Status.CreationTime = timeFunc()
 					addVolumeSnapshot(&volumeSnapshots[i])
@@ -1817,6 +3257,9 @@ var _ = Describe("Snapshot controlleer", func() {
 						CreationTime:       volumeSnapshots[i].Status.CreationTime,
 						Error:              translateError(volumeSnapshots[i].Status.Error),
 					}
+					if *vss.ReadyToUse {
+						vss.Duration = &metav1.Duration{Duration: time.Minute}
+					}
 					updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, vss)
 				}
 
@@ -1827,6 +3270,47 @@ var _ = Describe("Snapshot controlleer", func() {
 				Entry("ready", true),
 			)
 
+			It("should report VolumeSnapshots which are no longer ready in VirtualMachineSnapshotContent", func() {
+				vmSnapshot := createVMSnapshotSuccess()
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse:   pointer.P(true),
+					CreationTime: timeFunc(),
+				}
+
+				updatedContent := vmSnapshotContent.DeepCopy()
+				updatedContent.ResourceVersion = "1"
+				updatedContent.Status.ReadyToUse = pointer.P(false)
+
+				vmSnapshotSource.Add(vmSnapshot)
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+
+				var names []string
+				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
+				for i := range volumeSnapshots {
+					volumeSnapshots[i].Status.ReadyToUse = pointer.P(false)
+					volumeSnapshots[i].Status.CreationTime = timeFunc()
+					addVolumeSnapshot(&volumeSnapshots[i])
+
+					names = append(names, volumeSnapshots[i].Name)
+					updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, snapshotv1.VolumeSnapshotStatus{
+						VolumeSnapshotName: volumeSnapshots[i].Name,
+						ReadyToUse:         pointer.P(false),
+						CreationTime:       timeFunc(),
+					})
+				}
+				updatedContent.Status.Error = &snapshotv1.Error{
+					Time:    timeFunc(),
+					Message: pointer.P(fmt.Sprintf("VolumeSnapshots (%s) no longer ready to use", strings.Join(names, ","))),
+					Reason:  pointer.P(snapshotv1.VolumeSnapshotNotReadyErrorReason),
+				}
+
+				updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+
+				controller.processVMSnapshotContentWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
 			It("should update VirtualMachineSnapshotContent no snapshots", func() {
 				vmSnapshot := createVMSnapshotInProgress()
 				vmSnapshotContent := createVMSnapshotContent()
@@ -1863,6 +3347,7 @@ var _ = Describe("Snapshot controlleer", func() {
 
 				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
 				for i := range volumeSnapshots {
+					volumeSnapshots[i].CreationTimestamp = metav1.NewTime(timeStamp.Add(-time.Minute))
 					message := "bad error"
 					volumeSnapshots[i].Status.ReadyToUse = &rtu
 					volumeSnapshots[i].Status.CreationTime = ct
@@ -1878,6 +3363,9 @@ var _ = Describe("Snapshot controlleer", func() {
 						CreationTime:       volumeSnapshots[i].Status.CreationTime,
 						Error:              translateError(volumeSnapshots[i].Status.Error),
 					}
+					if *vss.ReadyToUse {
+						vss.Duration = &metav1.Duration{Duration: time.Minute}
+					}
 					updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, vss)
 
 					if i == 0 && !rtu {
@@ -1945,6 +3433,53 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*updateStatusCalls).To(Equal(1))
 			})
 
+			It("should report a quota exceeded error reason when the CSI driver refuses the VolumeSnapshot", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse: pointer.P(false),
+				}
+
+				updatedContent := vmSnapshotContent.DeepCopy()
+				updatedContent.ResourceVersion = "1"
+
+				vmSnapshotSource.Add(vmSnapshot)
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+
+				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
+				message := "failed to take snapshot of the volume: rpc error: code = ResourceExhausted desc = too many snapshots"
+				volumeSnapshots[0].Status.ReadyToUse = pointer.P(false)
+				volumeSnapshots[0].Status.Error = &vsv1.VolumeSnapshotError{
+					Message: &message,
+					Time:    timeFunc(),
+				}
+				addVolumeSnapshot(&volumeSnapshots[0])
+
+				expectedErrorMessage := fmt.Sprintf("VolumeSnapshot %s error: %s", volumeSnapshots[0].Name, message)
+				quotaExceededReason := pointer.P(snapshotv1.SnapshotQuotaExceededErrorReason)
+				updatedContent.Status.Error = &snapshotv1.Error{
+					Time:    timeFunc(),
+					Message: &expectedErrorMessage,
+					Reason:  quotaExceededReason,
+				}
+				updatedContent.Status.VolumeSnapshotStatus = []snapshotv1.VolumeSnapshotStatus{
+					{
+						VolumeSnapshotName: volumeSnapshots[0].Name,
+						ReadyToUse:         volumeSnapshots[0].Status.ReadyToUse,
+						Error: &snapshotv1.Error{
+							Time:    timeFunc(),
+							Message: &message,
+							Reason:  quotaExceededReason,
+						},
+					},
+				}
+
+				updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+
+				controller.processVMSnapshotContentWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
 			It("should clear VirtualMachineSnapshotContent error when transitioning from error to success", func() {
 				vmSnapshot := createVMSnapshotInProgress()
 				vmSnapshotContent := createVMSnapshotContent()
@@ -1969,6 +3504,7 @@ var _ = Describe("Snapshot controlleer", func() {
 				// Create volume snapshots that are now ready (no errors)
 				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
 				for i := range volumeSnapshots {
+					volumeSnapshots[i].CreationTimestamp = metav1.NewTime(timeStamp.Add(-time.Minute))
 					volumeSnapshots[i].Status.ReadyToUse = pointer.P(true)
 					volumeSnapshots[i].Status.CreationTime = timeFunc()
 					// No error status - this simulates the error being resolved
@@ -1980,6 +3516,9 @@ var _ = Describe("Snapshot controlleer", func() {
 						CreationTime:       volumeSnapshots[i].Status.CreationTime,
 						Error:              translateError(volumeSnapshots[i].Status.Error), // nil
 					}
+					if *vss.ReadyToUse {
+						vss.Duration = &metav1.Duration{Duration: time.Minute}
+					}
 					updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, vss)
 				}
 
@@ -2025,6 +3564,111 @@ var _ = Describe("Snapshot controlleer", func() {
 				Expect(*updateStatusCalls).To(Equal(1))
 			})
 
+			It("should not recreate VolumeSnapshots of a capture in progress that were deleted", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshotContent := createVMSnapshotContent()
+				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
+				var volumeSnapshotNames []string
+				var volumeSnapshotStatus []snapshotv1.VolumeSnapshotStatus
+				for i := range volumeSnapshots {
+					volumeSnapshotNames = append(volumeSnapshotNames, volumeSnapshots[i].Name)
+					volumeSnapshotStatus = append(volumeSnapshotStatus, snapshotv1.VolumeSnapshotStatus{
+						VolumeSnapshotName: volumeSnapshots[i].Name,
+					})
+				}
+				vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse:           pointer.P(false),
+					VolumeSnapshotStatus: volumeSnapshotStatus,
+				}
+
+				errorMessage := fmt.Sprintf("VolumeSnapshots (%s) missing", strings.Join(volumeSnapshotNames, ","))
+				updatedContent := vmSnapshotContent.DeepCopy()
+				updatedContent.ResourceVersion = "1"
+				updatedContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse: pointer.P(false),
+					Error: &snapshotv1.Error{
+						Message: &errorMessage,
+						Time:    timeFunc(),
+						Reason:  pointer.P(snapshotv1.VolumeSnapshotMissingErrorReason),
+					},
+				}
+
+				vmSnapshotSource.Add(vmSnapshot)
+				updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+				addVirtualMachineSnapshotContent(vmSnapshotContent)
+				controller.processVMSnapshotContentWorkItem()
+				testutils.ExpectEvent(recorder, "VolumeSnapshotMissing")
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should fail a capture in progress whose VolumeSnapshots are missing", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				errorMessage := "VolumeSnapshots (vmsnapshot-snapshot-uid-volume-disk1) missing"
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse: pointer.P(false),
+					Error: &snapshotv1.Error{
+						Message: &errorMessage,
+						Time:    timeFunc(),
+						Reason:  pointer.P(snapshotv1.VolumeSnapshotMissingErrorReason),
+					},
+				}
+
+				vmSource.Add(vm)
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.VirtualMachineSnapshotContentName = &vmSnapshotContent.Name
+				updatedSnapshot.Status.Error = vmSnapshotContent.Status.Error
+				updatedSnapshot.Status.Phase = snapshotv1.Failed
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newFailureCondition(corev1.ConditionTrue, errorMessage),
+					newProgressingCondition(corev1.ConditionFalse, "In error state"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				addVirtualMachineSnapshot(vmSnapshot)
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
+			It("should indicate a capture resumed after a controller restart", func() {
+				vmSnapshot := createVMSnapshotInProgress()
+				vm := createLockedVM()
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.CreationTimestamp = metav1.NewTime(timeStamp.Add(-time.Minute))
+				vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse: pointer.P(false),
+				}
+				controller.startTime = timeStamp.Time
+
+				vmSource.Add(vm)
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+
+				updatedSnapshot := vmSnapshot.DeepCopy()
+				updatedSnapshot.ResourceVersion = "1"
+				updatedSnapshot.Status.VirtualMachineSnapshotContentName = &vmSnapshotContent.Name
+				updatedSnapshot.Status.Conditions = []snapshotv1.Condition{
+					newProgressingCondition(corev1.ConditionTrue, "Source locked and operation in progress"),
+					newReadyCondition(corev1.ConditionFalse, "Not ready"),
+				}
+				updatedSnapshot.Status.Indications = []snapshotv1.Indication{snapshotv1.VMSnapshotResumedIndication}
+				updatedSnapshot.Status.SourceIndications = []snapshotv1.SourceIndication{
+					{
+						Indication: snapshotv1.VMSnapshotResumedIndication,
+						Message:    IndicationMessage(snapshotv1.VMSnapshotResumedIndication),
+					},
+				}
+				updateStatusCalls := expectVMSnapshotUpdateStatus(vmSnapshotClient, updatedSnapshot)
+
+				addVirtualMachineSnapshot(vmSnapshot)
+				controller.processVMSnapshotWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
 			It("should not update snapshotContent the same error is already updated", func() {
 				vmSnapshot := createVMSnapshotInProgress()
 				vmSnapshotContent := createVMSnapshotContent()
@@ -2097,12 +3741,16 @@ var _ = Describe("Snapshot controlleer", func() {
 					ReadyToUse:   &r,
 				}
 				for i := range volumeSnapshots {
+					volumeSnapshots[i].CreationTimestamp = metav1.NewTime(timeStamp.Add(-time.Minute))
 					vss := snapshotv1.VolumeSnapshotStatus{
 						VolumeSnapshotName: volumeSnapshots[i].Name,
 						ReadyToUse:         volumeSnapshots[i].Status.ReadyToUse,
 						CreationTime:       volumeSnapshots[i].Status.CreationTime,
 						Error:              translateError(volumeSnapshots[i].Status.Error),
 					}
+					if *vss.ReadyToUse {
+						vss.Duration = &metav1.Duration{Duration: time.Minute}
+					}
 					updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, vss)
 				}
 
@@ -2120,6 +3768,64 @@ var _ = Describe("Snapshot controlleer", func() {
 				Entry("created and ready", timeFunc(), true),
 			)
 
+			It("should unpause vm paused by the snapshot once volume snapshots are created", func() {
+				storageClass := createStorageClass()
+				storageClassSource.Add(storageClass)
+				volumeSnapshotClass := createVolumeSnapshotClasses()[0]
+
+				vm := createLockedVM()
+				vmSource.Add(vm)
+				vmi := createVMI(vm)
+				vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+					Type:   v1.VirtualMachineInstancePaused,
+					Status: corev1.ConditionTrue,
+				})
+				vmiSource.Add(vmi)
+
+				vmSnapshot := createVMSnapshotInProgress()
+				vmSnapshot.Spec.PauseDuringSnapshot = true
+				vmSnapshot.Annotations = map[string]string{pausedSourceAnnotation: "true"}
+				vmSnapshotContent := createVMSnapshotContent()
+				vmSnapshotContent.UID = contentUID
+				vmSnapshotContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					ReadyToUse: pointer.P(false),
+				}
+				volumeSnapshots := createVolumeSnapshots(vmSnapshotContent)
+				for i := range volumeSnapshots {
+					volumeSnapshots[i].Status.ReadyToUse = pointer.P(false)
+					volumeSnapshots[i].Status.CreationTime = timeFunc()
+					volumeSnapshotSource.Add(&volumeSnapshots[i])
+					vss := snapshotv1.VolumeSnapshotStatus{
+						VolumeSnapshotName: volumeSnapshots[i].Name,
+					}
+					vmSnapshotContent.Status.VolumeSnapshotStatus = append(vmSnapshotContent.Status.VolumeSnapshotStatus, vss)
+				}
+				vmSnapshotContentSource.Add(vmSnapshotContent)
+
+				updatedContent := createVMSnapshotContent()
+				updatedContent.UID = contentUID
+				updatedContent.ResourceVersion = "1"
+				updatedContent.Status = &snapshotv1.VirtualMachineSnapshotContentStatus{
+					CreationTime: timeFunc(),
+					ReadyToUse:   pointer.P(false),
+				}
+				for i := range volumeSnapshots {
+					vss := snapshotv1.VolumeSnapshotStatus{
+						VolumeSnapshotName: volumeSnapshots[i].Name,
+						ReadyToUse:         volumeSnapshots[i].Status.ReadyToUse,
+						CreationTime:       volumeSnapshots[i].Status.CreationTime,
+					}
+					updatedContent.Status.VolumeSnapshotStatus = append(updatedContent.Status.VolumeSnapshotStatus, vss)
+				}
+
+				vmiInterface.EXPECT().Unpause(context.Background(), vm.Name, &v1.UnpauseOptions{}).Return(nil).Times(1)
+				updateStatusCalls := expectVMSnapshotContentUpdateStatus(vmSnapshotClient, updatedContent)
+				vmSnapshotSource.Add(vmSnapshot)
+				addVolumeSnapshotClass(volumeSnapshotClass)
+				controller.processVMSnapshotContentWorkItem()
+				Expect(*updateStatusCalls).To(Equal(1))
+			})
+
 			DescribeTable("should attempt to unfreeze vm and remove content finalizer if vmsnapshot deleting", func(unfreezeError error) {
 				vm := createLockedVM()
 				vmSource.Add(vm)
@@ -2791,6 +4497,114 @@ var _ = Describe("Snapshot controlleer", func() {
 		})
 	})
 
+	DescribeTable("should classify the consistency level", func(indications []snapshotv1.Indication, expected snapshotv1.ConsistencyLevel) {
+		vmSnapshot := createVMSnapshotSuccess()
+		vmSnapshot.Status.Indications = indications
+		Expect(snapshotConsistencyLevel(vmSnapshot)).To(Equal(expected))
+	},
+		Entry("offline snapshot", nil, snapshotv1.ApplicationConsistent),
+		Entry("online with frozen guest agent",
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotOnlineSnapshotIndication, snapshotv1.VMSnapshotGuestAgentIndication},
+			snapshotv1.FilesystemConsistent),
+		Entry("online with failed quiesce",
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotOnlineSnapshotIndication, snapshotv1.VMSnapshotGuestAgentIndication, snapshotv1.VMSnapshotQuiesceFailedIndication},
+			snapshotv1.CrashConsistent),
+		Entry("online without guest agent",
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotOnlineSnapshotIndication, snapshotv1.VMSnapshotNoGuestAgentIndication},
+			snapshotv1.CrashConsistent),
+		Entry("online while paused",
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotOnlineSnapshotIndication, snapshotv1.VMSnapshotPausedIndication},
+			snapshotv1.CrashConsistent),
+	)
+
+	DescribeTable("should normalize the indications", func(indications []snapshotv1.Indication, sourceIndications []snapshotv1.SourceIndication, expected []snapshotv1.Indication) {
+		vmSnapshot := createVMSnapshotSuccess()
+		vmSnapshot.Status.Indications = indications
+		vmSnapshot.Status.SourceIndications = sourceIndications
+		normalizeSnapshotIndications(vmSnapshot)
+		Expect(vmSnapshot.Status.Indications).To(Equal(expected))
+		Expect(vmSnapshot.Status.SourceIndications).To(HaveLen(len(expected)))
+		for i, indication := range expected {
+			Expect(vmSnapshot.Status.SourceIndications[i].Indication).To(Equal(indication))
+			Expect(vmSnapshot.Status.SourceIndications[i].Message).To(Equal(IndicationMessage(indication)))
+		}
+	},
+		Entry("without indications", nil, nil, nil),
+		Entry("with only the deprecated indications",
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotOnlineSnapshotIndication, snapshotv1.VMSnapshotGuestAgentIndication, snapshotv1.VMSnapshotOnlineSnapshotIndication},
+			nil,
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotGuestAgentIndication, snapshotv1.VMSnapshotOnlineSnapshotIndication}),
+		Entry("with only the source indications",
+			nil,
+			[]snapshotv1.SourceIndication{
+				{Indication: snapshotv1.VMSnapshotGuestAgentIndication, Message: IndicationMessage(snapshotv1.VMSnapshotGuestAgentIndication)},
+				{Indication: snapshotv1.VMSnapshotOnlineSnapshotIndication, Message: IndicationMessage(snapshotv1.VMSnapshotOnlineSnapshotIndication)},
+			},
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotGuestAgentIndication, snapshotv1.VMSnapshotOnlineSnapshotIndication}),
+		Entry("with both, preferring the source indications",
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotOnlineSnapshotIndication, snapshotv1.VMSnapshotNoGuestAgentIndication},
+			[]snapshotv1.SourceIndication{
+				{Indication: snapshotv1.VMSnapshotOnlineSnapshotIndication, Message: IndicationMessage(snapshotv1.VMSnapshotOnlineSnapshotIndication)},
+				{Indication: snapshotv1.VMSnapshotPausedIndication, Message: IndicationMessage(snapshotv1.VMSnapshotPausedIndication)},
+			},
+			[]snapshotv1.Indication{snapshotv1.VMSnapshotOnlineSnapshotIndication, snapshotv1.VMSnapshotPausedIndication}),
+	)
+
+	DescribeTable("should detect CSI snapshot quota errors", func(message string, expected bool) {
+		Expect(isQuotaExceededError(message)).To(Equal(expected))
+	},
+		Entry("ResourceExhausted gRPC code", "rpc error: code = ResourceExhausted desc = no space", true),
+		Entry("quota message", "Snapshot Quota exceeded for volume", true),
+		Entry("limit message", "snapshot limit exceeded", true),
+		Entry("maximum snapshots message", "volume reached the maximum number of snapshots", true),
+		Entry("other error", "rpc error: code = Internal desc = failed", false),
+	)
+
+	It("should list the snapshots of a VM from the informer index", func() {
+		informer, _ := testutils.NewFakeInformerWithIndexersFor(&snapshotv1.VirtualMachineSnapshot{}, virtcontroller.GetVirtualMachineSnapshotInformerIndexers())
+		Expect(informer.GetIndexer().Add(createVirtualMachineSnapshot(testNamespace, "snapshot-1", vmName))).To(Succeed())
+		Expect(informer.GetIndexer().Add(createVirtualMachineSnapshot(testNamespace, "snapshot-2", vmName))).To(Succeed())
+		Expect(informer.GetIndexer().Add(createVirtualMachineSnapshot(testNamespace, "other-snapshot", "other-vm"))).To(Succeed())
+		Expect(informer.GetIndexer().Add(createVirtualMachineSnapshot("other-namespace", "snapshot-3", vmName))).To(Succeed())
+
+		vmSnapshots, err := SnapshotsForVM(informer, testNamespace, vmName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vmSnapshots).To(ConsistOf(
+			HaveField("Name", "snapshot-1"),
+			HaveField("Name", "snapshot-2"),
+		))
+	})
+
+	It("should list the restores targeting a VM from the informer index", func() {
+		createRestore := func(namespace, name, targetName string, targetNamespace *string) *snapshotv1.VirtualMachineRestore {
+			return &snapshotv1.VirtualMachineRestore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: snapshotv1.VirtualMachineRestoreSpec{
+					Target: corev1.TypedLocalObjectReference{
+						APIGroup: &vmAPIGroup,
+						Kind:     "VirtualMachine",
+						Name:     targetName,
+					},
+					TargetNamespace: targetNamespace,
+				},
+			}
+		}
+
+		informer, _ := testutils.NewFakeInformerWithIndexersFor(&snapshotv1.VirtualMachineRestore{}, virtcontroller.GetVirtualMachineRestoreInformerIndexers())
+		Expect(informer.GetIndexer().Add(createRestore(testNamespace, "restore-1", vmName, nil))).To(Succeed())
+		Expect(informer.GetIndexer().Add(createRestore("other-namespace", "restore-2", vmName, pointer.P(testNamespace)))).To(Succeed())
+		Expect(informer.GetIndexer().Add(createRestore(testNamespace, "other-restore", "other-vm", nil))).To(Succeed())
+
+		vmRestores, err := RestoresForVM(informer, testNamespace, vmName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vmRestores).To(ConsistOf(
+			HaveField("Name", "restore-1"),
+			HaveField("Name", "restore-2"),
+		))
+	})
 })
 
 func applyPatch(patch []byte, orig, patched interface{}) error {
@@ -3085,6 +4899,9 @@ func createVirtualMachineSnapshotContent(vmSnapshot *snapshotv1.VirtualMachineSn
 			Name:       "vmsnapshot-content-" + vmSnapshotUID,
 			Namespace:  testNamespace,
 			Finalizers: []string{"snapshot.kubevirt.io/vmsnapshotcontent-protection"},
+			Labels: map[string]string{
+				"snapshot.kubevirt.io/source-vm-uid": string(vm.UID),
+			},
 		},
 		Spec: snapshotv1.VirtualMachineSnapshotContentSpec{
 			VirtualMachineSnapshotName: &vmSnapshot.Name,
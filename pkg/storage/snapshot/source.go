@@ -42,7 +42,9 @@ import (
 	"kubevirt.io/client-go/log"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
+	"kubevirt.io/kubevirt/pkg/apimachinery/patch"
 	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/pointer"
 	storagetypes "kubevirt.io/kubevirt/pkg/storage/types"
 	storageutils "kubevirt.io/kubevirt/pkg/storage/utils"
 	utils "kubevirt.io/kubevirt/pkg/util"
@@ -53,12 +55,40 @@ import (
 const (
 	sourceFinalizer = "snapshot.kubevirt.io/snapshot-source-protection"
 	failedFreezeMsg = "Failed freezing vm"
+	failedPauseMsg  = "Failed pausing vm"
+
+	// pausedSourceAnnotation marks a snapshot which paused its source VM
+	// and is responsible for unpausing it once the volumes are captured
+	pausedSourceAnnotation = "snapshot.kubevirt.io/paused-source"
+
+	// postThawHooksAnnotation marks a snapshot which froze its source and
+	// still has to run the post-thaw hooks
+	postThawHooksAnnotation = "snapshot.kubevirt.io/post-thaw-hooks"
+
+	// failedHookAnnotation records the last snapshot hook which failed
+	failedHookAnnotation = "snapshot.kubevirt.io/failed-hook"
+
+	preFreezeHookStage = "pre-freeze"
+	postThawHookStage  = "post-thaw"
+
+	defaultHookTimeout = 30 * time.Second
+	// maxHookTimeout caps the hook timeout, the freeze and unfreeze
+	// requests wait for the hooks
+	maxHookTimeout = 2 * time.Minute
+	// hookRequestGracePeriod is added to the hook timeouts so the request
+	// outlives the commands executed in the guest
+	hookRequestGracePeriod = 10 * time.Second
 )
 
 var (
 	ErrVolumeDoesntExist  = errors.New("volume doesnt exist")
 	ErrVolumeNotBound     = errors.New("volume not bound")
 	ErrVolumeNotPopulated = errors.New("volume not populated")
+	ErrMemoryDumpFailed   = errors.New("memory dump failed")
+
+	// errHooksRunning is returned while a freeze or unfreeze request
+	// carrying snapshot hooks runs in the background
+	errHooksRunning = errors.New("snapshot hooks running")
 )
 
 type snapshotSource interface {
@@ -76,6 +106,10 @@ type snapshotSource interface {
 	Unfreeze() error
 	Spec() (snapshotv1.SourceSpec, error)
 	PersistentVolumeClaims() (map[string]string, error)
+	HotplugVolumes() ([]kubevirtv1.Volume, error)
+	HotplugDisks() (map[string]kubevirtv1.Disk, error)
+	CaptureMemory() (bool, error)
+	ReleaseMemoryDump() error
 }
 
 type sourceState struct {
@@ -436,6 +470,10 @@ func (s *vmSnapshotSource) Freeze() error {
 		return nil
 	}
 
+	if s.snapshot.Spec.PauseDuringSnapshot && s.Online() {
+		return s.pause()
+	}
+
 	if s.Paused() {
 		log.Log.Warningf("VM %s is paused - taking snapshot without filesystem freeze. Paused VMs cannot flush memory buffers to disk, which may result in inconsistent snapshots.", s.vm.Name)
 		return nil
@@ -448,6 +486,19 @@ func (s *vmSnapshotSource) Freeze() error {
 		return nil
 	}
 
+	hooks := snapshotHooks(s.snapshot)
+	// mark the snapshot before freezing the guest so the post-thaw
+	// hooks run even if the controller restarts in between
+	if _, ok := s.snapshot.Annotations[postThawHooksAnnotation]; !ok && len(hooks.PostThaw) > 0 {
+		if err := s.annotateSnapshot(postThawHooksAnnotation, "true"); err != nil {
+			return err
+		}
+	}
+
+	if len(hooks.PreFreeze) > 0 {
+		return s.freezeWithHooks(hooks.PreFreeze)
+	}
+
 	log.Log.V(3).Infof("Freezing vm %s file system before taking the snapshot", s.vm.Name)
 
 	startTime := time.Now()
@@ -463,11 +514,67 @@ func (s *vmSnapshotSource) Freeze() error {
 	return nil
 }
 
+// freezeWithHooks freezes the guest once the pre-freeze hooks ran. The
+// freeze request waits for the hooks, so it is sent in the background and
+// errHooksRunning is returned until it completes.
+func (s *vmSnapshotSource) freezeWithHooks(hooks []snapshotv1.SnapshotHook) error {
+	namespace, name := s.vm.Namespace, s.vm.Name
+	unfreezeTimeout := getFailureDeadline(s.snapshot)
+	done, err := s.controller.pollHookRequest(s.snapshot, preFreezeHookStage, func() error {
+		log.Log.V(3).Infof("Running pre-freeze hooks and freezing vm %s file system before taking the snapshot", name)
+
+		ctx, cancel := context.WithTimeout(context.Background(), hooksRequestTimeout(hooks))
+		defer cancel()
+		defer timeTrack(time.Now(), fmt.Sprintf("Freezing vmi %s with pre-freeze hooks", name))
+		return s.controller.Client.VirtualMachineInstance(namespace).Freeze(ctx, name, unfreezeTimeout, freezeHooks(hooks)...)
+	})
+	if !done {
+		return errHooksRunning
+	}
+	if err != nil {
+		if err := s.annotateSnapshot(failedHookAnnotation, err.Error()); err != nil {
+			log.Log.Reason(err).Errorf("Failed to record failed hook of snapshot %s/%s", s.snapshot.Namespace, s.snapshot.Name)
+		}
+		formattedErr := fmt.Errorf("%s %s: %v", failedFreezeMsg, s.vm.Name, err)
+		log.Log.Errorf("%s", formattedErr.Error())
+		return formattedErr
+	}
+
+	// the failure of an earlier attempt was recovered from
+	if err := s.removeSnapshotAnnotation(failedHookAnnotation); err != nil {
+		return err
+	}
+	s.state.frozen = true
+
+	return nil
+}
+
 func (s *vmSnapshotSource) Unfreeze() error {
-	if !s.Locked() || !s.GuestAgent() || s.Paused() {
+	if !s.Locked() {
 		return nil
 	}
 
+	if sourcePausedBySnapshot(s.snapshot) {
+		return s.unpause()
+	}
+
+	if !s.GuestAgent() || s.Paused() {
+		return nil
+	}
+
+	// a freeze still running its hooks would freeze the guest again
+	// once it is thawed
+	if s.controller.hookRequestRunning(s.snapshot, preFreezeHookStage) {
+		return errHooksRunning
+	}
+
+	if _, ok := s.snapshot.Annotations[postThawHooksAnnotation]; ok {
+		thawed, err := s.unfreezeWithHooks(snapshotHooks(s.snapshot).PostThaw)
+		if err != nil || thawed {
+			return err
+		}
+	}
+
 	log.Log.V(3).Infof("Unfreezing vm %s file system after taking the snapshot", s.vm.Name)
 
 	defer timeTrack(time.Now(), fmt.Sprintf("Unfreezing vmi %s", s.vm.Name))
@@ -480,12 +587,452 @@ func (s *vmSnapshotSource) Unfreeze() error {
 	return nil
 }
 
+// unfreezeWithHooks thaws the guest and runs the post-thaw hooks once, in
+// the background like the pre-freeze hooks. A failure is recorded on the
+// snapshot, which already captured the volumes, and the guest is thawed
+// again without hooks in case the thaw itself failed.
+func (s *vmSnapshotSource) unfreezeWithHooks(hooks []snapshotv1.SnapshotHook) (bool, error) {
+	namespace, name := s.vm.Namespace, s.vm.Name
+	done, requestErr := s.controller.pollHookRequest(s.snapshot, postThawHookStage, func() error {
+		log.Log.V(3).Infof("Unfreezing vm %s file system and running post-thaw hooks after taking the snapshot", name)
+
+		ctx, cancel := context.WithTimeout(context.Background(), hooksRequestTimeout(hooks))
+		defer cancel()
+		defer timeTrack(time.Now(), fmt.Sprintf("Unfreezing vmi %s with post-thaw hooks", name))
+		return s.controller.Client.VirtualMachineInstance(namespace).Unfreeze(ctx, name, freezeHooks(hooks)...)
+	})
+	if !done {
+		return false, errHooksRunning
+	}
+	if requestErr != nil {
+		log.Log.Warningf("Failed to unfreeze vm %s with post-thaw hooks: %v", name, requestErr)
+		if err := s.annotateSnapshot(failedHookAnnotation, requestErr.Error()); err != nil {
+			log.Log.Reason(err).Errorf("Failed to record failed hook of snapshot %s/%s", s.snapshot.Namespace, s.snapshot.Name)
+		}
+	}
+	if err := s.removeSnapshotAnnotation(postThawHooksAnnotation); err != nil {
+		return false, err
+	}
+	if requestErr != nil {
+		return false, nil
+	}
+	s.state.frozen = false
+
+	return true, nil
+}
+
+func snapshotHooks(snapshot *snapshotv1.VirtualMachineSnapshot) *snapshotv1.SnapshotHooks {
+	if snapshot.Spec.Hooks == nil {
+		return &snapshotv1.SnapshotHooks{}
+	}
+	return snapshot.Spec.Hooks
+}
+
+func hookTimeout(hook snapshotv1.SnapshotHook) time.Duration {
+	if hook.Timeout == nil {
+		return defaultHookTimeout
+	}
+	return min(hook.Timeout.Duration, maxHookTimeout)
+}
+
+// hooksRequestTimeout is the time a freeze or unfreeze request carrying the
+// hooks is given, the hooks run one after the other
+func hooksRequestTimeout(hooks []snapshotv1.SnapshotHook) time.Duration {
+	timeout := hookRequestGracePeriod
+	for _, hook := range hooks {
+		timeout += hookTimeout(hook)
+	}
+	return timeout
+}
+
+func freezeHooks(hooks []snapshotv1.SnapshotHook) []kubevirtv1.FreezeHook {
+	var freezeHooks []kubevirtv1.FreezeHook
+	for _, hook := range hooks {
+		freezeHooks = append(freezeHooks, kubevirtv1.FreezeHook{
+			Name:           hook.Name,
+			Command:        hook.Command,
+			Args:           hook.Args,
+			TimeoutSeconds: int32(hookTimeout(hook).Seconds()),
+		})
+	}
+	return freezeHooks
+}
+
+// hookRequest is a freeze or unfreeze request carrying snapshot hooks
+type hookRequest struct {
+	done bool
+	err  error
+}
+
+func hookRequestKey(snapshot *snapshotv1.VirtualMachineSnapshot, stage string) string {
+	return fmt.Sprintf("%s/%s", snapshot.UID, stage)
+}
+
+// pollHookRequest starts the request of the snapshot stage in the background
+// unless it was already started, and returns whether it is done along with
+// its result. The snapshot content is enqueued once the request completes,
+// a completed request is forgotten once its result was returned.
+func (ctrl *VMSnapshotController) pollHookRequest(snapshot *snapshotv1.VirtualMachineSnapshot, stage string, run func() error) (bool, error) {
+	key := hookRequestKey(snapshot, stage)
+	contentKey := cacheKeyFunc(snapshot.Namespace, GetVMSnapshotContentName(snapshot))
+
+	ctrl.hookRequestsMutex.Lock()
+	defer ctrl.hookRequestsMutex.Unlock()
+
+	request, ok := ctrl.hookRequests[key]
+	if !ok {
+		request = &hookRequest{}
+		ctrl.hookRequests[key] = request
+		go func() {
+			err := run()
+
+			ctrl.hookRequestsMutex.Lock()
+			request.done, request.err = true, err
+			ctrl.hookRequestsMutex.Unlock()
+
+			ctrl.vmSnapshotContentQueue.Add(contentKey)
+		}()
+		return false, nil
+	}
+
+	if !request.done {
+		return false, nil
+	}
+
+	delete(ctrl.hookRequests, key)
+	return true, request.err
+}
+
+// hookRequestRunning returns whether the request of the snapshot stage is
+// still running, a completed request is forgotten
+func (ctrl *VMSnapshotController) hookRequestRunning(snapshot *snapshotv1.VirtualMachineSnapshot, stage string) bool {
+	key := hookRequestKey(snapshot, stage)
+
+	ctrl.hookRequestsMutex.Lock()
+	defer ctrl.hookRequestsMutex.Unlock()
+
+	request, ok := ctrl.hookRequests[key]
+	if !ok {
+		return false
+	}
+	if request.done {
+		delete(ctrl.hookRequests, key)
+		return false
+	}
+	return true
+}
+
+func sourcePausedBySnapshot(snapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	_, ok := snapshot.Annotations[pausedSourceAnnotation]
+	return ok
+}
+
+func (s *vmSnapshotSource) pause() error {
+	// a VM paused before the snapshot started is left paused
+	if s.Paused() {
+		return nil
+	}
+
+	// mark the snapshot before pausing so the VM is unpaused
+	// even if the controller restarts in between
+	if err := s.markSourcePaused(); err != nil {
+		return err
+	}
+
+	log.Log.V(3).Infof("Pausing vm %s before taking the snapshot", s.vm.Name)
+
+	startTime := time.Now()
+	err := s.controller.Client.VirtualMachineInstance(s.vm.Namespace).Pause(context.Background(), s.vm.Name, &kubevirtv1.PauseOptions{})
+	timeTrack(startTime, fmt.Sprintf("Pausing vmi %s", s.vm.Name))
+	if err != nil {
+		formattedErr := fmt.Errorf("%s %s: %v", failedPauseMsg, s.vm.Name, err)
+		log.Log.Errorf("%s", formattedErr.Error())
+		return formattedErr
+	}
+	s.state.paused = true
+
+	return nil
+}
+
+func (s *vmSnapshotSource) unpause() error {
+	if !s.Paused() {
+		return nil
+	}
+
+	log.Log.V(3).Infof("Unpausing vm %s after taking the snapshot", s.vm.Name)
+
+	defer timeTrack(time.Now(), fmt.Sprintf("Unpausing vmi %s", s.vm.Name))
+	err := s.controller.Client.VirtualMachineInstance(s.vm.Namespace).Unpause(context.Background(), s.vm.Name, &kubevirtv1.UnpauseOptions{})
+	if err != nil {
+		return err
+	}
+	s.state.paused = false
+
+	return nil
+}
+
+func (s *vmSnapshotSource) markSourcePaused() error {
+	if sourcePausedBySnapshot(s.snapshot) {
+		return nil
+	}
+
+	return s.annotateSnapshot(pausedSourceAnnotation, "true")
+}
+
+func (s *vmSnapshotSource) annotateSnapshot(key, value string) error {
+	var patchSet *patch.PatchSet
+	if s.snapshot.Annotations == nil {
+		patchSet = patch.New(patch.WithAdd("/metadata/annotations", map[string]string{key: value}))
+	} else {
+		patchSet = patch.New(patch.WithAdd(fmt.Sprintf("/metadata/annotations/%s", patch.EscapeJSONPointer(key)), value))
+	}
+
+	return s.patchSnapshot(patchSet)
+}
+
+func (s *vmSnapshotSource) removeSnapshotAnnotation(key string) error {
+	if _, ok := s.snapshot.Annotations[key]; !ok {
+		return nil
+	}
+
+	return s.patchSnapshot(patch.New(patch.WithRemove(fmt.Sprintf("/metadata/annotations/%s", patch.EscapeJSONPointer(key)))))
+}
+
+func (s *vmSnapshotSource) patchSnapshot(patchSet *patch.PatchSet) error {
+	payload, err := patchSet.GeneratePayload()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := s.controller.Client.VirtualMachineSnapshot(s.snapshot.Namespace).Patch(context.Background(), s.snapshot.Name, types.JSONPatchType, payload, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	s.snapshot = snapshot
+
+	return nil
+}
+
 func (s *vmSnapshotSource) PersistentVolumeClaims() (map[string]string, error) {
 	volumes, err := storageutils.GetVolumes(s.vm, s.controller.Client, storageutils.WithAllVolumes)
 	if err != nil {
 		return map[string]string{}, err
 	}
-	return storagetypes.GetPVCsFromVolumes(volumes), nil
+	hotplugVolumes, err := s.HotplugVolumes()
+	if err != nil {
+		return map[string]string{}, err
+	}
+	pvcs := storagetypes.GetPVCsFromVolumes(append(volumes, hotplugVolumes...))
+	if s.snapshot.Spec.IncludeEphemeralVolumes {
+		for _, volume := range volumes {
+			if volume.Ephemeral != nil && volume.Ephemeral.PersistentVolumeClaim != nil {
+				pvcs[volume.Name] = volume.Ephemeral.PersistentVolumeClaim.ClaimName
+			}
+		}
+	}
+	for _, volumeName := range s.snapshot.Spec.ExcludedVolumes {
+		delete(pvcs, volumeName)
+	}
+	return pvcs, nil
+}
+
+// HotplugVolumes returns the storage volumes hotplugged to the running VMI
+// which are not part of the VM spec
+func (s *vmSnapshotSource) HotplugVolumes() ([]kubevirtv1.Volume, error) {
+	if !s.Online() {
+		return nil, nil
+	}
+
+	vmi, exists, err := s.controller.getVMI(s.vm)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var volumes []kubevirtv1.Volume
+	vmVolumesByName := storagetypes.GetVolumesByName(&s.vm.Spec.Template.Spec)
+	for _, volume := range vmi.Spec.Volumes {
+		if _, exists := vmVolumesByName[volume.Name]; exists {
+			continue
+		}
+		if storagetypes.IsStorageVolume(&volume) && storagetypes.IsDeclarativeHotplugVolume(&volume) {
+			volumes = append(volumes, *volume.DeepCopy())
+		}
+	}
+
+	return volumes, nil
+}
+
+// HotplugDisks returns the disks of the storage volumes hotplugged to the
+// running VMI, keyed by volume name
+func (s *vmSnapshotSource) HotplugDisks() (map[string]kubevirtv1.Disk, error) {
+	volumes, err := s.HotplugVolumes()
+	if err != nil || len(volumes) == 0 {
+		return nil, err
+	}
+
+	vmi, exists, err := s.controller.getVMI(s.vm)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	hotplugVolumeNames := sets.New[string]()
+	for _, volume := range volumes {
+		hotplugVolumeNames.Insert(volume.Name)
+	}
+	disks := map[string]kubevirtv1.Disk{}
+	for _, disk := range vmi.Spec.Domain.Devices.Disks {
+		if hotplugVolumeNames.Has(disk.Name) {
+			disks[disk.Name] = *disk.DeepCopy()
+		}
+	}
+
+	return disks, nil
+}
+
+// CaptureMemory dumps the guest memory of a running source to a PVC, which
+// is then captured with the other volumes. It returns true once the dump
+// completed, or when no dump was requested.
+func (s *vmSnapshotSource) CaptureMemory() (bool, error) {
+	if !includeMemoryDump(s.snapshot) || !s.Online() {
+		return true, nil
+	}
+
+	claimName := memoryDumpClaimName(s.snapshot)
+	request := s.vm.Status.MemoryDumpRequest
+	if request != nil && request.ClaimName == claimName {
+		switch request.Phase {
+		case kubevirtv1.MemoryDumpCompleted:
+			return true, nil
+		case kubevirtv1.MemoryDumpFailed:
+			return false, fmt.Errorf("%w for vm %s: %s", ErrMemoryDumpFailed, s.vm.Name, request.Message)
+		}
+		return false, nil
+	}
+
+	if request != nil {
+		return false, fmt.Errorf("%w for vm %s: memory dump %s is already associated with the vm", ErrMemoryDumpFailed, s.vm.Name, request.ClaimName)
+	}
+
+	if err := s.createMemoryDumpPVC(claimName); err != nil {
+		return false, err
+	}
+
+	log.Log.V(3).Infof("Dumping memory of vm %s before taking the snapshot", s.vm.Name)
+
+	return false, s.patchMemoryDumpRequest(&kubevirtv1.VirtualMachineMemoryDumpRequest{
+		ClaimName: claimName,
+		Phase:     kubevirtv1.MemoryDumpAssociating,
+	})
+}
+
+// ReleaseMemoryDump dissociates the memory dump of the snapshot from the
+// source VM once the snapshot is done with it, so the VM can take further
+// memory dumps. The PVC is kept, it is owned by the snapshot content.
+func (s *vmSnapshotSource) ReleaseMemoryDump() error {
+	request := s.vm.Status.MemoryDumpRequest
+	if request == nil || request.ClaimName != memoryDumpClaimName(s.snapshot) ||
+		request.Phase == kubevirtv1.MemoryDumpDissociating || request.Remove {
+		return nil
+	}
+
+	log.Log.V(3).Infof("Dissociating memory dump %s from vm %s", request.ClaimName, s.vm.Name)
+
+	return s.patchMemoryDumpRequest(&kubevirtv1.VirtualMachineMemoryDumpRequest{
+		ClaimName: request.ClaimName,
+		Phase:     kubevirtv1.MemoryDumpDissociating,
+		Remove:    true,
+	})
+}
+
+// patchMemoryDumpRequest sets the memory dump request in the status of the
+// source VM, the VM controller then associates or dissociates the dump. The
+// patch fails if the request changed since the VM was read.
+func (s *vmSnapshotSource) patchMemoryDumpRequest(request *kubevirtv1.VirtualMachineMemoryDumpRequest) error {
+	patchSet := patch.New(patch.WithTest("/status/memoryDumpRequest", s.vm.Status.MemoryDumpRequest))
+	if s.vm.Status.MemoryDumpRequest != nil {
+		patchSet.AddOption(patch.WithReplace("/status/memoryDumpRequest", request))
+	} else {
+		patchSet.AddOption(patch.WithAdd("/status/memoryDumpRequest", request))
+	}
+
+	payload, err := patchSet.GeneratePayload()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.controller.Client.VirtualMachine(s.vm.Namespace).PatchStatus(context.Background(), s.vm.Name, types.JSONPatchType, payload, metav1.PatchOptions{})
+	return err
+}
+
+// createMemoryDumpPVC creates the PVC the guest memory is dumped to, sized
+// for the guest memory and using the storage class of a snapshotted volume.
+// The snapshot owns the PVC until its content takes it over.
+func (s *vmSnapshotSource) createMemoryDumpPVC(claimName string) error {
+	vmi, exists, err := s.controller.getVMI(s.vm)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("no vmi for vm %s to dump the memory of", s.vm.Name)
+	}
+
+	size, err := storagetypes.GetSizeIncludingDefaultFSOverhead(utils.CalcExpectedMemoryDumpSize(vmi))
+	if err != nil {
+		return err
+	}
+
+	storageClassName, err := s.memoryDumpStorageClass()
+	if err != nil {
+		return err
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName,
+			Namespace: s.vm.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s.snapshot, snapshotv1.SchemeGroupVersion.WithKind("VirtualMachineSnapshot")),
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: storageClassName,
+			VolumeMode:       pointer.P(corev1.PersistentVolumeFilesystem),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: *size,
+				},
+			},
+		},
+	}
+
+	_, err = s.controller.Client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(context.Background(), pvc, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// memoryDumpStorageClass returns the storage class of the first snapshotted
+// PVC, so the memory dump can be captured the same way as the volumes
+func (s *vmSnapshotSource) memoryDumpStorageClass() (*string, error) {
+	pvcs, err := s.PersistentVolumeClaims()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, volumeName := range slices.Sorted(maps.Keys(pvcs)) {
+		pvc, err := s.controller.getSnapshotPVC(s.vm.Namespace, pvcs[volumeName])
+		if err != nil {
+			return nil, err
+		}
+		if pvc != nil {
+			return pvc.Spec.StorageClassName, nil
+		}
+	}
+
+	return nil, nil
 }
 
 func (s *vmSnapshotSource) pvcNames() (sets.String, error) {
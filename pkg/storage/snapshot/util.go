@@ -28,6 +28,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
 
 	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
 	"kubevirt.io/client-go/log"
@@ -75,6 +76,42 @@ func newFailureCondition(status corev1.ConditionStatus, reason string) snapshotv
 	}
 }
 
+func newDeadlineExtendedCondition(status corev1.ConditionStatus, reason string) snapshotv1.Condition {
+	return snapshotv1.Condition{
+		Type:               snapshotv1.ConditionDeadlineExtended,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: *currentTime(),
+	}
+}
+
+func newAccessCredentialsRequiredCondition(status corev1.ConditionStatus, reason string) snapshotv1.Condition {
+	return snapshotv1.Condition{
+		Type:               snapshotv1.ConditionAccessCredentialsRequired,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: *currentTime(),
+	}
+}
+
+func newPlacementUnschedulableCondition(status corev1.ConditionStatus, reason string) snapshotv1.Condition {
+	return snapshotv1.Condition{
+		Type:               snapshotv1.ConditionPlacementUnschedulable,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: *currentTime(),
+	}
+}
+
+func newExcludedVolumesMissingCondition(status corev1.ConditionStatus, reason string) snapshotv1.Condition {
+	return snapshotv1.Condition{
+		Type:               snapshotv1.ConditionExcludedVolumesMissing,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: *currentTime(),
+	}
+}
+
 func hasConditionType(conditions []snapshotv1.Condition, condType snapshotv1.ConditionType) bool {
 	for _, cond := range conditions {
 		if cond.Type == condType {
@@ -112,14 +149,82 @@ func getFailureDeadline(vmSnapshot *snapshotv1.VirtualMachineSnapshot) time.Dura
 	return failureDeadline
 }
 
-func timeUntilDeadline(vmSnapshot *snapshotv1.VirtualMachineSnapshot) time.Duration {
+func getDeadlineGracePeriod(vmSnapshot *snapshotv1.VirtualMachineSnapshot) time.Duration {
+	if vmSnapshot.Spec.DeadlineGracePeriod == nil {
+		return 0
+	}
+
+	return vmSnapshot.Spec.DeadlineGracePeriod.Duration
+}
+
+func deadlineExtended(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	return vmSnapshot.Status != nil && hasConditionType(vmSnapshot.Status.Conditions, snapshotv1.ConditionDeadlineExtended)
+}
+
+// deadlineGracePeriodGranted reports whether the DeadlineGracePeriod is added
+// to the failure deadline, which it is once the extension was recorded or
+// while the volume snapshots of the content are progressing
+func deadlineGracePeriodGranted(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) bool {
+	if getDeadlineGracePeriod(vmSnapshot) == 0 {
+		return false
+	}
+	return deadlineExtended(vmSnapshot) || volumeSnapshotsProgressing(content)
+}
+
+// failureDeadlinePassed reports whether the FailureDeadline passed, not
+// taking the DeadlineGracePeriod into account
+func failureDeadlinePassed(vmSnapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	failureDeadline := getFailureDeadline(vmSnapshot)
+	return failureDeadline != 0 && vmSnapshot.CreationTimestamp.Add(failureDeadline).Before(currentTime().Time)
+}
+
+func timeUntilDeadline(vmSnapshot *snapshotv1.VirtualMachineSnapshot, content *snapshotv1.VirtualMachineSnapshotContent) time.Duration {
 	failureDeadline := getFailureDeadline(vmSnapshot)
 	// No Deadline set by user
 	if failureDeadline == 0 {
 		return failureDeadline
 	}
 	deadline := vmSnapshot.CreationTimestamp.Add(failureDeadline)
-	return time.Until(deadline)
+	if deadlineGracePeriodGranted(vmSnapshot, content) {
+		deadline = deadline.Add(getDeadlineGracePeriod(vmSnapshot))
+	}
+	return deadline.Sub(currentTime().Time)
+}
+
+// timeUntilExpiration returns how long a succeeded snapshot with a TTL is
+// kept, false is returned if the snapshot does not expire (yet)
+func timeUntilExpiration(vmSnapshot *snapshotv1.VirtualMachineSnapshot) (time.Duration, bool) {
+	ttl := vmSnapshot.Spec.TTLAfterSuccess
+	if ttl == nil || ttl.Duration <= 0 || !vmSnapshotSucceeded(vmSnapshot) || vmSnapshot.Status.CreationTime == nil {
+		return 0, false
+	}
+
+	return vmSnapshot.Status.CreationTime.Add(ttl.Duration).Sub(currentTime().Time), true
+}
+
+func getDeletionTimeout(vmSnapshot *snapshotv1.VirtualMachineSnapshot) time.Duration {
+	if vmSnapshot.Spec.DeletionTimeout != nil {
+		return vmSnapshot.Spec.DeletionTimeout.Duration
+	}
+
+	return snapshotDeletionTimeout
+}
+
+func timeUntilDeletionTimeout(vmSnapshot *snapshotv1.VirtualMachineSnapshot) time.Duration {
+	if vmSnapshot.DeletionTimestamp == nil {
+		return 0
+	}
+	return time.Until(vmSnapshot.DeletionTimestamp.Add(getDeletionTimeout(vmSnapshot)))
+}
+
+// timeUntilDeletionRetry returns when a pending deletion should be checked
+// again. Once the deletion timeout passed, the deletion is still retried
+// and reported again every timeout period.
+func timeUntilDeletionRetry(vmSnapshot *snapshotv1.VirtualMachineSnapshot) time.Duration {
+	if remaining := timeUntilDeletionTimeout(vmSnapshot); remaining > 0 {
+		return remaining
+	}
+	return max(getDeletionTimeout(vmSnapshot), snapshotRetryInterval)
 }
 
 func getSimplifiedMetaObject(meta metav1.ObjectMeta) *metav1.ObjectMeta {
@@ -142,3 +247,44 @@ func GetSnapshotContents(vmSnapshot *snapshotv1.VirtualMachineSnapshot, client k
 
 	return client.VirtualMachineSnapshotContent(vmSnapshot.Namespace).Get(context.Background(), vmSnapshotContentName, metav1.GetOptions{})
 }
+
+// SnapshotsForVM returns the VirtualMachineSnapshots of a VM, looked up in the
+// "vm" index of the VirtualMachineSnapshot informer
+func SnapshotsForVM(informer cache.SharedIndexInformer, namespace, vmName string) ([]*snapshotv1.VirtualMachineSnapshot, error) {
+	objs, err := informer.GetIndexer().ByIndex("vm", cacheKeyFunc(namespace, vmName))
+	if err != nil {
+		return nil, err
+	}
+
+	vmSnapshots := make([]*snapshotv1.VirtualMachineSnapshot, 0, len(objs))
+	for _, obj := range objs {
+		vmSnapshot, ok := obj.(*snapshotv1.VirtualMachineSnapshot)
+		if !ok {
+			return nil, fmt.Errorf("unexpected resource %+v", obj)
+		}
+		vmSnapshots = append(vmSnapshots, vmSnapshot)
+	}
+
+	return vmSnapshots, nil
+}
+
+// RestoresForVM returns the VirtualMachineRestores targeting a VM, looked up
+// in the "vm" index of the VirtualMachineRestore informer. The namespace is
+// the one of the VM, which differs from the one of cross-namespace restores.
+func RestoresForVM(informer cache.SharedIndexInformer, namespace, vmName string) ([]*snapshotv1.VirtualMachineRestore, error) {
+	objs, err := informer.GetIndexer().ByIndex("vm", cacheKeyFunc(namespace, vmName))
+	if err != nil {
+		return nil, err
+	}
+
+	vmRestores := make([]*snapshotv1.VirtualMachineRestore, 0, len(objs))
+	for _, obj := range objs {
+		vmRestore, ok := obj.(*snapshotv1.VirtualMachineRestore)
+		if !ok {
+			return nil, fmt.Errorf("unexpected resource %+v", obj)
+		}
+		vmRestores = append(vmRestores, vmRestore)
+	}
+
+	return vmRestores, nil
+}
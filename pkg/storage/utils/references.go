@@ -0,0 +1,70 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package utils
+
+import (
+	k8sv1 "k8s.io/api/core/v1"
+
+	v1 "kubevirt.io/api/core/v1"
+	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
+)
+
+// VisitResourceReferences calls visit with the name of every Secret and ConfigMap
+// referenced by the VM spec. The name is passed by pointer so it can be rewritten.
+func VisitResourceReferences(spec *v1.VirtualMachineSpec, visit func(kind snapshotv1.ResourceRemappingKind, name *string)) {
+	if spec.Template == nil {
+		return
+	}
+
+	visitLocalRef := func(kind snapshotv1.ResourceRemappingKind, ref *k8sv1.LocalObjectReference) {
+		if ref != nil {
+			visit(kind, &ref.Name)
+		}
+	}
+
+	for i := range spec.Template.Spec.Volumes {
+		volume := &spec.Template.Spec.Volumes[i]
+		switch {
+		case volume.Secret != nil:
+			visit(snapshotv1.ResourceRemappingSecret, &volume.Secret.SecretName)
+		case volume.ConfigMap != nil:
+			visit(snapshotv1.ResourceRemappingConfigMap, &volume.ConfigMap.Name)
+		case volume.CloudInitNoCloud != nil:
+			visitLocalRef(snapshotv1.ResourceRemappingSecret, volume.CloudInitNoCloud.UserDataSecretRef)
+			visitLocalRef(snapshotv1.ResourceRemappingSecret, volume.CloudInitNoCloud.NetworkDataSecretRef)
+		case volume.CloudInitConfigDrive != nil:
+			visitLocalRef(snapshotv1.ResourceRemappingSecret, volume.CloudInitConfigDrive.UserDataSecretRef)
+			visitLocalRef(snapshotv1.ResourceRemappingSecret, volume.CloudInitConfigDrive.NetworkDataSecretRef)
+		case volume.Sysprep != nil:
+			visitLocalRef(snapshotv1.ResourceRemappingSecret, volume.Sysprep.Secret)
+			visitLocalRef(snapshotv1.ResourceRemappingConfigMap, volume.Sysprep.ConfigMap)
+		}
+	}
+
+	for i := range spec.Template.Spec.AccessCredentials {
+		accessCredential := &spec.Template.Spec.AccessCredentials[i]
+		if accessCredential.SSHPublicKey != nil && accessCredential.SSHPublicKey.Source.Secret != nil {
+			visit(snapshotv1.ResourceRemappingSecret, &accessCredential.SSHPublicKey.Source.Secret.SecretName)
+		}
+		if accessCredential.UserPassword != nil && accessCredential.UserPassword.Source.Secret != nil {
+			visit(snapshotv1.ResourceRemappingSecret, &accessCredential.UserPassword.Source.Secret.SecretName)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	v1 "kubevirt.io/api/core/v1"
+	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
+)
+
+var _ = Describe("VisitResourceReferences", func() {
+	newVMSpec := func() *v1.VirtualMachineSpec {
+		return &v1.VirtualMachineSpec{
+			Template: &v1.VirtualMachineInstanceTemplateSpec{
+				Spec: v1.VirtualMachineInstanceSpec{
+					Volumes: []v1.Volume{
+						{
+							Name: "secret",
+							VolumeSource: v1.VolumeSource{
+								Secret: &v1.SecretVolumeSource{SecretName: "secret-volume"},
+							},
+						},
+						{
+							Name: "configmap",
+							VolumeSource: v1.VolumeSource{
+								ConfigMap: &v1.ConfigMapVolumeSource{
+									LocalObjectReference: k8sv1.LocalObjectReference{Name: "configmap-volume"},
+								},
+							},
+						},
+						{
+							Name: "cloudinit",
+							VolumeSource: v1.VolumeSource{
+								CloudInitNoCloud: &v1.CloudInitNoCloudSource{
+									UserDataSecretRef:    &k8sv1.LocalObjectReference{Name: "userdata"},
+									NetworkDataSecretRef: &k8sv1.LocalObjectReference{Name: "networkdata"},
+								},
+							},
+						},
+						{
+							Name: "sysprep",
+							VolumeSource: v1.VolumeSource{
+								Sysprep: &v1.SysprepSource{
+									ConfigMap: &k8sv1.LocalObjectReference{Name: "sysprep"},
+								},
+							},
+						},
+					},
+					AccessCredentials: []v1.AccessCredential{
+						{
+							SSHPublicKey: &v1.SSHPublicKeyAccessCredential{
+								Source: v1.SSHPublicKeyAccessCredentialSource{
+									Secret: &v1.AccessCredentialSecretSource{SecretName: "ssh-key"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("should visit every Secret and ConfigMap reference", func() {
+		visited := map[string]snapshotv1.ResourceRemappingKind{}
+		VisitResourceReferences(newVMSpec(), func(kind snapshotv1.ResourceRemappingKind, name *string) {
+			visited[*name] = kind
+		})
+
+		Expect(visited).To(Equal(map[string]snapshotv1.ResourceRemappingKind{
+			"secret-volume":    snapshotv1.ResourceRemappingSecret,
+			"configmap-volume": snapshotv1.ResourceRemappingConfigMap,
+			"userdata":         snapshotv1.ResourceRemappingSecret,
+			"networkdata":      snapshotv1.ResourceRemappingSecret,
+			"sysprep":          snapshotv1.ResourceRemappingConfigMap,
+			"ssh-key":          snapshotv1.ResourceRemappingSecret,
+		}))
+	})
+
+	It("should allow rewriting the referenced names", func() {
+		spec := newVMSpec()
+		VisitResourceReferences(spec, func(kind snapshotv1.ResourceRemappingKind, name *string) {
+			*name = "new-" + *name
+		})
+
+		volumes := spec.Template.Spec.Volumes
+		Expect(volumes[0].Secret.SecretName).To(Equal("new-secret-volume"))
+		Expect(volumes[1].ConfigMap.Name).To(Equal("new-configmap-volume"))
+		Expect(volumes[2].CloudInitNoCloud.UserDataSecretRef.Name).To(Equal("new-userdata"))
+		Expect(volumes[2].CloudInitNoCloud.NetworkDataSecretRef.Name).To(Equal("new-networkdata"))
+		Expect(volumes[3].Sysprep.ConfigMap.Name).To(Equal("new-sysprep"))
+		Expect(spec.Template.Spec.AccessCredentials[0].SSHPublicKey.Source.Secret.SecretName).To(Equal("new-ssh-key"))
+	})
+
+	It("should not fail without a template", func() {
+		VisitResourceReferences(&v1.VirtualMachineSpec{}, func(snapshotv1.ResourceRemappingKind, *string) {
+			Fail("unexpected reference")
+		})
+	})
+})
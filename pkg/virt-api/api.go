@@ -312,6 +312,8 @@ func (app *virtAPIApp) composeSubresources() {
 
 		subws.Route(subws.PUT(definitions.NamespacedResourcePath(subresourcesvmiGVR)+definitions.SubResourcePath("unfreeze")).
 			To(subresourceApp.UnfreezeVMIRequestHandler).
+			Consumes(mime.MIME_ANY).
+			Reads(v1.UnfreezeOptions{}).
 			Param(definitions.NamespaceParam(subws)).Param(definitions.NameParam(subws)).
 			Operation(version.Version+"Unfreeze").
 			Doc("Unfreeze a VirtualMachineInstance object.").
@@ -269,6 +269,7 @@ type VirtControllerApp struct {
 	snapshotControllerThreads         int
 	restoreControllerThreads          int
 	snapshotControllerResyncPeriod    time.Duration
+	snapshotDedupWindow               time.Duration
 	cloneControllerThreads            int
 	additionalLauncherAnnotationsSync []string
 	additionalLauncherLabelsSync      []string
@@ -896,6 +897,7 @@ func (vca *VirtControllerApp) initSnapshotController() {
 		Client:                    vca.clientSet,
 		VMSnapshotInformer:        vca.vmSnapshotInformer,
 		VMSnapshotContentInformer: vca.vmSnapshotContentInformer,
+		VMRestoreInformer:         vca.vmRestoreInformer,
 		VMInformer:                vca.vmInformer,
 		VMIInformer:               vca.vmiInformer,
 		StorageClassInformer:      vca.storageClassInformer,
@@ -907,6 +909,7 @@ func (vca *VirtControllerApp) initSnapshotController() {
 		CRInformer:                vca.controllerRevisionInformer,
 		Recorder:                  recorder,
 		ResyncPeriod:              vca.snapshotControllerResyncPeriod,
+		DedupWindow:               vca.snapshotDedupWindow,
 	}
 	if err := vca.snapshotController.Init(); err != nil {
 		panic(err)
@@ -928,6 +931,7 @@ func (vca *VirtControllerApp) initRestoreController() {
 		VolumeSnapshotProvider:    vca.snapshotController,
 		Recorder:                  recorder,
 		CRInformer:                vca.controllerRevisionInformer,
+		NodeInformer:              vca.nodeInformer,
 	}
 	if err := vca.restoreController.Init(); err != nil {
 		panic(err)
@@ -1088,6 +1092,9 @@ func (vca *VirtControllerApp) AddFlags() {
 	flag.DurationVar(&vca.snapshotControllerResyncPeriod, "snapshot-controller-resync-period", defaultSnapshotControllerResyncPeriod,
 		"Number of goroutines to run for snapshot controller")
 
+	flag.DurationVar(&vca.snapshotDedupWindow, "snapshot-dedup-window", 0,
+		"Window in which a new snapshot of an unchanged, stopped VM with the Halted run strategy reuses the content of a ready snapshot instead of capturing again, 0 disables it")
+
 	flag.DurationVar(&vca.nodeTopologyUpdatePeriod, "node-topology-update-period", defaultNodeTopologyUpdatePeriod,
 		"Update period for the node topology updater")
 
@@ -201,6 +201,7 @@ var _ = Describe("Application", func() {
 			Client:                    virtClient,
 			VMSnapshotInformer:        vmSnapshotInformer,
 			VMSnapshotContentInformer: vmSnapshotContentInformer,
+			VMRestoreInformer:         vmRestoreInformer,
 			VMInformer:                vmInformer,
 			VMIInformer:               vmiInformer,
 			PodInformer:               podInformer,
@@ -223,6 +224,7 @@ var _ = Describe("Application", func() {
 			PVCInformer:               pvcInformer,
 			StorageClassInformer:      storageClassInformer,
 			DataVolumeInformer:        dataVolumeInformer,
+			NodeInformer:              nodeInformer,
 			Recorder:                  recorder,
 		}
 		_ = app.restoreController.Init()
@@ -36,6 +36,7 @@ import (
 	"kubevirt.io/client-go/log"
 
 	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
 )
 
 const (
@@ -43,6 +44,8 @@ const (
 	failedFreezeVMI        = "Failed to freeze VMI"
 	failedDetectCmdClient  = "Failed to detect cmd client"
 	failedConnectCmdClient = "Failed to connect cmd client"
+
+	defaultFreezeHookTimeoutSeconds = 30
 )
 
 type LifecycleHandler struct {
@@ -126,8 +129,11 @@ func (lh *LifecycleHandler) FreezeHandler(request *restful.Request, response *re
 		return
 	}
 
-	unfreezeTimeoutSeconds := int32(unfreezeTimeout.UnfreezeTimeout.Seconds())
-	err = client.FreezeVirtualMachine(vmi, unfreezeTimeoutSeconds)
+	err = runFreezeHooks(vmi, client, "pre-freeze", unfreezeTimeout.Hooks)
+	if err == nil {
+		unfreezeTimeoutSeconds := int32(unfreezeTimeout.UnfreezeTimeout.Seconds())
+		err = client.FreezeVirtualMachine(vmi, unfreezeTimeoutSeconds)
+	}
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error(failedFreezeVMI)
 		response.WriteError(http.StatusBadRequest, err)
@@ -145,6 +151,20 @@ func (lh *LifecycleHandler) UnfreezeHandler(request *restful.Request, response *
 	}
 	defer client.Close()
 
+	unfreezeOptions := &v1.UnfreezeOptions{}
+	if request.Request.Body != nil {
+		defer request.Request.Body.Close()
+		err = yaml.NewYAMLOrJSONDecoder(request.Request.Body, 1024).Decode(unfreezeOptions)
+		switch err {
+		case io.EOF, nil:
+			break
+		default:
+			log.Log.Object(vmi).Reason(err).Error("Failed to unmarshal unfreeze request")
+			response.WriteError(http.StatusBadRequest, fmt.Errorf("failed to unmarshal unfreeze request"))
+			return
+		}
+	}
+
 	err = client.UnfreezeVirtualMachine(vmi)
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to unfreeze VMI")
@@ -152,9 +172,39 @@ func (lh *LifecycleHandler) UnfreezeHandler(request *restful.Request, response *
 		return
 	}
 
+	// the hooks only run once the guest is thawed
+	err = runFreezeHooks(vmi, client, "post-thaw", unfreezeOptions.Hooks)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to run post-thaw hooks of VMI")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
 	response.WriteHeader(http.StatusAccepted)
 }
 
+// runFreezeHooks executes the hooks in order inside the guest and stops at the
+// first one which fails. The output of the commands is not returned, it may
+// hold guest data which does not belong in errors and events.
+func runFreezeHooks(vmi *v1.VirtualMachineInstance, client cmdclient.LauncherClient, stage string, hooks []v1.FreezeHook) error {
+	for _, hook := range hooks {
+		timeoutSeconds := hook.TimeoutSeconds
+		if timeoutSeconds <= 0 {
+			timeoutSeconds = defaultFreezeHookTimeoutSeconds
+		}
+
+		exitCode, _, err := client.Exec(api.VMINamespaceKeyFunc(vmi), hook.Command, hook.Args, timeoutSeconds)
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("command %s exited with code %d", hook.Command, exitCode)
+		}
+		if err != nil {
+			return fmt.Errorf("%s hook %s failed: %v", stage, hook.Name, err)
+		}
+	}
+
+	return nil
+}
+
 func (lh *LifecycleHandler) ResetHandler(request *restful.Request, response *restful.Response) {
 	vmi, client, err := lh.getVMILauncherClient(request, response)
 	if err != nil {
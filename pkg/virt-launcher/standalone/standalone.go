@@ -20,33 +20,280 @@
 package standalone
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"time"
 
 	v1 "kubevirt.io/api/core/v1"
 	"kubevirt.io/client-go/log"
+	"libvirt.org/go/libvirt"
 	"sigs.k8s.io/yaml"
 
+	virtwait "kubevirt.io/kubevirt/pkg/apimachinery/wait"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+)
+
+const (
+	envStandaloneWaitRunning        = "STANDALONE_WAIT_RUNNING"
+	envStandaloneWaitRunningTimeout = "STANDALONE_WAIT_RUNNING_TIMEOUT"
+	envStandaloneWatchLifecycle     = "STANDALONE_WATCH_LIFECYCLE"
+
+	defaultWaitRunningTimeout = 2 * time.Minute
+	waitRunningInterval       = 1 * time.Second
 )
 
 // HandleStandaloneMode checks for STANDALONE_VMI env var and syncs if present.
-func HandleStandaloneMode(domainManager virtwrap.DomainManager) {
-	if vmiObjStr, ok := os.LookupEnv("STANDALONE_VMI"); ok {
-		var vmi v1.VirtualMachineInstance
-		// Try YAML unmarshal
-		if err := yaml.Unmarshal([]byte(vmiObjStr), &vmi); err != nil {
-			// Fallback to JSON if YAML fails
-			if jsonErr := json.Unmarshal([]byte(vmiObjStr), &vmi); jsonErr != nil {
-				log.Log.Reason(err).Error("Failed to unmarshal VMI from STANDALONE_VMI as YAML/JSON")
-				panic(err)
+// STANDALONE_VMI holds either a single VMI or a YAML/JSON list of VMIs, all
+// of which are synced before failing on the errors of any of them.
+// When STANDALONE_WAIT_RUNNING=1 is set, it additionally waits for the domain
+// of every synced VMI to reach the Running state, bounded by
+// STANDALONE_WAIT_RUNNING_TIMEOUT.
+// Failures are returned, leaving the decision to exit to the caller.
+func HandleStandaloneMode(domainManager virtwrap.DomainManager) error {
+	vmiObjStr, ok := os.LookupEnv("STANDALONE_VMI")
+	if !ok {
+		return nil
+	}
+
+	if isList([]byte(vmiObjStr)) {
+		vmis, err := syncVMIList(domainManager, []byte(vmiObjStr))
+		if err != nil {
+			return fmt.Errorf("failed to sync VMIs from STANDALONE_VMI: %w", err)
+		}
+		return waitForStandaloneDomains(domainManager, vmis)
+	}
+
+	var vmi v1.VirtualMachineInstance
+	// Try YAML unmarshal
+	if err := yaml.Unmarshal([]byte(vmiObjStr), &vmi); err != nil {
+		// Fallback to JSON if YAML fails
+		if jsonErr := json.Unmarshal([]byte(vmiObjStr), &vmi); jsonErr != nil {
+			return fmt.Errorf("failed to unmarshal VMI from STANDALONE_VMI as YAML/JSON: %w", err)
+		}
+	}
+
+	log.Log.Object(&vmi).Infof("Standalone mode: syncing VMI")
+	if _, err := domainManager.SyncVMI(&vmi, true, nil); err != nil {
+		return fmt.Errorf("failed to sync VMI %s: %w", vmi.Name, err)
+	}
+
+	return waitForStandaloneDomains(domainManager, []*v1.VirtualMachineInstance{&vmi})
+}
+
+// isList reports whether the YAML or JSON document is a list rather than
+// a single object.
+func isList(data []byte) bool {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return false
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(jsonData), []byte("["))
+}
+
+// syncVMIList syncs every VMI of the list, carrying on past entries which
+// cannot be parsed or synced, and returns the synced VMIs along with the
+// errors of all the failed entries.
+func syncVMIList(domainManager virtwrap.DomainManager, data []byte) ([]*v1.VirtualMachineInstance, error) {
+	var items []json.RawMessage
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("STANDALONE_VMI contains an empty list")
+	}
+
+	var (
+		synced []*v1.VirtualMachineInstance
+		errs   []error
+	)
+	for i, item := range items {
+		vmi := &v1.VirtualMachineInstance{}
+		if err := json.Unmarshal(item, vmi); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unmarshal VMI at index %d: %w", i, err))
+			continue
+		}
+
+		log.Log.Object(vmi).Infof("Standalone mode: syncing VMI")
+		if _, err := domainManager.SyncVMI(vmi, true, nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to sync VMI %s at index %d: %w", vmi.Name, i, err))
+			continue
+		}
+		synced = append(synced, vmi)
+	}
+
+	return synced, errors.Join(errs...)
+}
+
+func waitForStandaloneDomains(domainManager virtwrap.DomainManager, vmis []*v1.VirtualMachineInstance) error {
+	if os.Getenv(envStandaloneWaitRunning) != "1" {
+		return nil
+	}
+
+	timeout, err := waitRunningTimeout()
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", envStandaloneWaitRunningTimeout, err)
+	}
+
+	domainNames := make([]string, 0, len(vmis))
+	for _, vmi := range vmis {
+		domainNames = append(domainNames, api.VMINamespaceKeyFunc(vmi))
+	}
+
+	log.Log.Infof("Standalone mode: waiting up to %s for domains %v to be running", timeout, domainNames)
+	return waitForDomainsRunning(domainManager, domainNames, waitRunningInterval, timeout)
+}
+
+func waitRunningTimeout() (time.Duration, error) {
+	timeoutStr, ok := os.LookupEnv(envStandaloneWaitRunningTimeout)
+	if !ok || timeoutStr == "" {
+		return defaultWaitRunningTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %s", timeoutStr)
+	}
+	return timeout, nil
+}
+
+// waitForDomainsRunning polls the domain manager until every domain of
+// domainNames reports the Running state, returning an error naming the first
+// domain which does not get there within timeout.
+func waitForDomainsRunning(domainManager virtwrap.DomainManager, domainNames []string, interval, timeout time.Duration) error {
+	var (
+		pending    string
+		lastStatus api.LifeCycle
+	)
+	err := virtwait.PollImmediately(interval, timeout, func(_ context.Context) (bool, error) {
+		domains, err := domainManager.ListAllDomains()
+		if err != nil {
+			return false, err
+		}
+
+		statuses := make(map[string]api.LifeCycle, len(domains))
+		for _, domain := range domains {
+			statuses[domain.Spec.Name] = domain.Status.Status
+		}
+		for _, name := range domainNames {
+			if statuses[name] != api.Running {
+				pending, lastStatus = name, statuses[name]
+				return false, nil
 			}
 		}
+		return true, nil
+	})
+	if err != nil {
+		if pending == "" {
+			return fmt.Errorf("failed to wait for the domains to be running: %w", err)
+		}
+		if lastStatus == "" {
+			return fmt.Errorf("domain %s not found while waiting for it to be running: %w", pending, err)
+		}
+		return fmt.Errorf("domain %s in state %s while waiting for it to be running: %w", pending, lastStatus, err)
+	}
+	return nil
+}
+
+// WatchStandaloneLifecycle follows the domains synced in standalone mode when
+// STANDALONE_WATCH_LIFECYCLE=1 is set along with STANDALONE_VMI, logging their
+// lifecycle transitions on every libvirt lifecycle event until stop is closed
+// or all of the domains are down.
+// onDomainStop is called once every domain is shut off, crashed or gone with
+// at least one of them shut off or crashed, so that the launcher can be torn
+// down instead of idling next to dead guests.
+func WatchStandaloneLifecycle(domainManager virtwrap.DomainManager, domainConn cli.Connection, stop <-chan struct{}, onDomainStop func()) {
+	if _, ok := os.LookupEnv("STANDALONE_VMI"); !ok || os.Getenv(envStandaloneWatchLifecycle) != "1" {
+		return
+	}
+
+	// Events only trigger a refresh of all the domains, so coalescing them
+	// while one is pending loses nothing.
+	lifecycleEvents := make(chan struct{}, 1)
+	err := domainConn.DomainEventLifecycleRegister(func(_ *libvirt.Connect, _ *libvirt.Domain, event *libvirt.DomainEventLifecycle) {
+		log.Log.V(4).Infof("Standalone mode: domain lifecycle event %s received", event.String())
+		select {
+		case lifecycleEvents <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		log.Log.Reason(err).Error("Standalone mode: failed to register the domain lifecycle event callback")
+		return
+	}
+
+	log.Log.Info("Standalone mode: watching the domain lifecycle")
+	watchDomainLifecycle(domainManager, lifecycleEvents, stop, onDomainStop)
+}
+
+func watchDomainLifecycle(domainManager virtwrap.DomainManager, lifecycleEvents <-chan struct{}, stop <-chan struct{}, onDomainStop func()) {
+	lastStatuses := map[string]api.LifeCycle{}
+	for {
+		if done := checkDomainLifecycle(domainManager, lastStatuses, onDomainStop); done {
+			return
+		}
+
+		select {
+		case <-stop:
+			log.Log.Info("Standalone mode: stopped watching the domain lifecycle")
+			return
+		case <-lifecycleEvents:
+		}
+	}
+}
+
+// checkDomainLifecycle logs the transitions of every domain from
+// lastStatuses, and reports whether all of the domains reached the end of
+// their lifecycle.
+func checkDomainLifecycle(domainManager virtwrap.DomainManager, lastStatuses map[string]api.LifeCycle, onDomainStop func()) bool {
+	domains, err := domainManager.ListAllDomains()
+	if err != nil {
+		log.Log.Reason(err).Warning("Standalone mode: failed to list domains")
+		return false
+	}
+
+	present := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		name := domain.Spec.Name
+		present[name] = true
+
+		status := domain.Status.Status
+		if status == lastStatuses[name] {
+			continue
+		}
+		log.Log.Infof("Standalone mode: domain %s transitioned from state %q to %s (reason %s)", name, lastStatuses[name], status, domain.Status.Reason)
+		lastStatuses[name] = status
+	}
 
-		log.Log.Object(&vmi).Infof("Standalone mode: syncing VMI")
-		if _, err := domainManager.SyncVMI(&vmi, true, nil); err != nil {
-			log.Log.Object(&vmi).Reason(err).Error("Failed to sync VMI, quitting")
-			panic(err)
+	if len(lastStatuses) == 0 {
+		return false
+	}
+
+	stopped := false
+	for name, status := range lastStatuses {
+		switch {
+		case !present[name]:
+			if status != "" {
+				log.Log.Infof("Standalone mode: domain %s is gone, last seen in state %s", name, status)
+				lastStatuses[name] = ""
+			}
+		case status == api.Shutoff || status == api.Crashed:
+			stopped = true
+		default:
+			return false
 		}
 	}
+
+	if stopped {
+		onDomainStop()
+	}
+	return true
 }
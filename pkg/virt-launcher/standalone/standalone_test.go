@@ -26,9 +26,15 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"libvirt.org/go/libvirt"
 
+	v1 "kubevirt.io/api/core/v1"
+
+	cmdv1 "kubevirt.io/kubevirt/pkg/handler-launcher-com/cmd/v1"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/standalone"
 	virtwrap "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
 )
 
 var _ = Describe("HandleStandaloneMode", func() {
@@ -48,28 +54,24 @@ var _ = Describe("HandleStandaloneMode", func() {
 
 	It("should do nothing if STANDALONE_VMI env var is not set", func() {
 		os.Unsetenv("STANDALONE_VMI")
-		standalone.HandleStandaloneMode(mockDM)
+		Expect(standalone.HandleStandaloneMode(mockDM)).To(Succeed())
 	})
 
-	It("should panic on invalid JSON in STANDALONE_VMI", func() {
+	It("should fail on invalid JSON in STANDALONE_VMI", func() {
 		os.Setenv("STANDALONE_VMI", "invalid json")
 		defer os.Unsetenv("STANDALONE_VMI")
 
-		Expect(func() {
-			standalone.HandleStandaloneMode(mockDM)
-		}).To(Panic())
+		Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("failed to unmarshal VMI from STANDALONE_VMI")))
 	})
 
-	It("should panic if SyncVMI fails", func() {
+	It("should fail if SyncVMI fails", func() {
 		vmiJSON := `{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi"}}`
 		os.Setenv("STANDALONE_VMI", vmiJSON)
 		defer os.Unsetenv("STANDALONE_VMI")
 
 		mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).Return(nil, fmt.Errorf("sync error"))
 
-		Expect(func() {
-			standalone.HandleStandaloneMode(mockDM)
-		}).To(PanicWith(MatchError(ContainSubstring("sync error"))))
+		Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("sync error")))
 	})
 
 	It("should succeed with valid JSON and successful SyncVMI", func() {
@@ -79,9 +81,7 @@ var _ = Describe("HandleStandaloneMode", func() {
 
 		mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).Return(nil, nil)
 
-		Expect(func() {
-			standalone.HandleStandaloneMode(mockDM)
-		}).NotTo(Panic())
+		Expect(standalone.HandleStandaloneMode(mockDM)).To(Succeed())
 	})
 
 	It("should succeed with valid YAML and successful SyncVMI", func() {
@@ -94,17 +94,269 @@ metadata:
 
 		mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).Return(nil, nil)
 
-		Expect(func() {
-			standalone.HandleStandaloneMode(mockDM)
-		}).NotTo(Panic())
+		Expect(standalone.HandleStandaloneMode(mockDM)).To(Succeed())
 	})
 
-	It("should panic on invalid YAML in STANDALONE_VMI", func() {
+	It("should fail on invalid YAML in STANDALONE_VMI", func() {
 		os.Setenv("STANDALONE_VMI", "invalid: yaml: here")
 		defer os.Unsetenv("STANDALONE_VMI")
 
-		Expect(func() {
-			standalone.HandleStandaloneMode(mockDM)
-		}).To(Panic())
+		Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("failed to unmarshal VMI from STANDALONE_VMI")))
+	})
+
+	Context("with a list of VMIs", func() {
+		vmiNames := func(calls *[]string) func(*v1.VirtualMachineInstance, bool, *cmdv1.VirtualMachineOptions) (*api.DomainSpec, error) {
+			return func(vmi *v1.VirtualMachineInstance, _ bool, _ *cmdv1.VirtualMachineOptions) (*api.DomainSpec, error) {
+				*calls = append(*calls, vmi.Name)
+				return nil, nil
+			}
+		}
+
+		AfterEach(func() {
+			os.Unsetenv("STANDALONE_VMI")
+		})
+
+		It("should sync every VMI of a JSON list", func() {
+			os.Setenv("STANDALONE_VMI", `[{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi1"}},`+
+				`{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi2"}}]`)
+
+			var synced []string
+			mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).DoAndReturn(vmiNames(&synced)).Times(2)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(Succeed())
+			Expect(synced).To(Equal([]string{"testvmi1", "testvmi2"}))
+		})
+
+		It("should sync every VMI of a YAML list", func() {
+			os.Setenv("STANDALONE_VMI", `- apiVersion: kubevirt.io/v1
+  kind: VirtualMachineInstance
+  metadata:
+    name: testvmi1
+- apiVersion: kubevirt.io/v1
+  kind: VirtualMachineInstance
+  metadata:
+    name: testvmi2`)
+
+			var synced []string
+			mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).DoAndReturn(vmiNames(&synced)).Times(2)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(Succeed())
+			Expect(synced).To(Equal([]string{"testvmi1", "testvmi2"}))
+		})
+
+		It("should sync the valid VMIs and fail on an invalid entry", func() {
+			os.Setenv("STANDALONE_VMI", `["invalid",{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi"}}]`)
+
+			var synced []string
+			mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).DoAndReturn(vmiNames(&synced)).Times(1)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("failed to unmarshal VMI at index 0")))
+			Expect(synced).To(Equal([]string{"testvmi"}))
+		})
+
+		It("should sync all VMIs and report every failed sync", func() {
+			os.Setenv("STANDALONE_VMI", `[{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi1"}},`+
+				`{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi2"}}]`)
+
+			mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).Return(nil, fmt.Errorf("sync error")).Times(2)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(And(
+				MatchError(ContainSubstring("failed to sync VMI testvmi1 at index 0: sync error")),
+				MatchError(ContainSubstring("failed to sync VMI testvmi2 at index 1: sync error")),
+			))
+		})
+
+		It("should fail on an empty list", func() {
+			os.Setenv("STANDALONE_VMI", "[]")
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(HaveOccurred())
+		})
+	})
+
+	Context("with STANDALONE_WAIT_RUNNING", func() {
+		const vmiJSON = `{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi","namespace":"default"}}`
+
+		newNamedDomain := func(name string, status api.LifeCycle) *api.Domain {
+			domain := api.NewMinimalDomain(name)
+			domain.Status.Status = status
+			return domain
+		}
+
+		newDomain := func(status api.LifeCycle) *api.Domain {
+			return newNamedDomain("testvmi", status)
+		}
+
+		BeforeEach(func() {
+			os.Setenv("STANDALONE_VMI", vmiJSON)
+			os.Setenv("STANDALONE_WAIT_RUNNING", "1")
+			os.Setenv("STANDALONE_WAIT_RUNNING_TIMEOUT", "1s")
+			mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).Return(nil, nil)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("STANDALONE_VMI")
+			os.Unsetenv("STANDALONE_WAIT_RUNNING")
+			os.Unsetenv("STANDALONE_WAIT_RUNNING_TIMEOUT")
+		})
+
+		It("should succeed once the domain is running", func() {
+			gomock.InOrder(
+				mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newDomain(api.Paused)}, nil),
+				mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newDomain(api.Running)}, nil),
+			)
+			os.Setenv("STANDALONE_WAIT_RUNNING_TIMEOUT", "5s")
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(Succeed())
+		})
+
+		It("should fail if the domain does not become running in time", func() {
+			mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newDomain(api.Paused)}, nil).MinTimes(1)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("domain default_testvmi in state Paused")))
+		})
+
+		It("should fail if the domain does not exist", func() {
+			mockDM.EXPECT().ListAllDomains().Return(nil, nil).MinTimes(1)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("domain default_testvmi not found")))
+		})
+
+		It("should not mistake another running domain for the expected one", func() {
+			mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newNamedDomain("othervmi", api.Running)}, nil).MinTimes(1)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("domain default_testvmi not found")))
+		})
+
+		It("should wait for the domain of every VMI of a list", func() {
+			os.Setenv("STANDALONE_VMI", `[{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi1","namespace":"default"}},`+
+				`{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi2","namespace":"default"}}]`)
+			mockDM.EXPECT().SyncVMI(gomock.Any(), true, nil).Return(nil, nil)
+			mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{
+				newNamedDomain("testvmi1", api.Running),
+				newNamedDomain("testvmi2", api.Paused),
+			}, nil).MinTimes(1)
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(MatchError(ContainSubstring("domain default_testvmi2 in state Paused")))
+		})
+
+		It("should fail on an invalid timeout", func() {
+			os.Setenv("STANDALONE_WAIT_RUNNING_TIMEOUT", "soon")
+
+			Expect(standalone.HandleStandaloneMode(mockDM)).To(HaveOccurred())
+		})
+	})
+
+	Context("with STANDALONE_WATCH_LIFECYCLE", func() {
+		const vmiJSON = `{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachineInstance","metadata":{"name":"testvmi"}}`
+
+		var (
+			mockConn          *cli.MockConnection
+			lifecycleCallback libvirt.DomainEventLifecycleCallback
+			stop              chan struct{}
+			domainStops       int
+			onDomainStop      = func() {
+				domainStops++
+			}
+		)
+
+		newDomain := func(name string, status api.LifeCycle) *api.Domain {
+			domain := api.NewMinimalDomain(name)
+			domain.Status.Status = status
+			return domain
+		}
+
+		// listThenEvent returns the domains and has libvirt report the next
+		// lifecycle event, as if the domains kept changing state.
+		listThenEvent := func(domains ...*api.Domain) func() ([]*api.Domain, error) {
+			return func() ([]*api.Domain, error) {
+				lifecycleCallback(nil, nil, &libvirt.DomainEventLifecycle{})
+				return domains, nil
+			}
+		}
+
+		BeforeEach(func() {
+			lifecycleCallback = nil
+			mockConn = cli.NewMockConnection(mockCtrl)
+			mockConn.EXPECT().DomainEventLifecycleRegister(gomock.Any()).DoAndReturn(func(callback libvirt.DomainEventLifecycleCallback) error {
+				lifecycleCallback = callback
+				return nil
+			}).AnyTimes()
+			stop = make(chan struct{})
+			domainStops = 0
+			os.Setenv("STANDALONE_VMI", vmiJSON)
+			os.Setenv("STANDALONE_WATCH_LIFECYCLE", "1")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("STANDALONE_VMI")
+			os.Unsetenv("STANDALONE_WATCH_LIFECYCLE")
+		})
+
+		It("should not watch the domain if not enabled", func() {
+			os.Unsetenv("STANDALONE_WATCH_LIFECYCLE")
+
+			standalone.WatchStandaloneLifecycle(mockDM, mockConn, stop, onDomainStop)
+			Expect(lifecycleCallback).To(BeNil())
+			Expect(domainStops).To(BeZero())
+		})
+
+		It("should not watch the domain if the event callback cannot be registered", func() {
+			mockConn = cli.NewMockConnection(mockCtrl)
+			mockConn.EXPECT().DomainEventLifecycleRegister(gomock.Any()).Return(fmt.Errorf("register error"))
+
+			standalone.WatchStandaloneLifecycle(mockDM, mockConn, stop, onDomainStop)
+			Expect(domainStops).To(BeZero())
+		})
+
+		It("should tear down once the domain shuts off", func() {
+			gomock.InOrder(
+				mockDM.EXPECT().ListAllDomains().DoAndReturn(listThenEvent(newDomain("testvmi", api.Running))),
+				mockDM.EXPECT().ListAllDomains().DoAndReturn(listThenEvent(newDomain("testvmi", api.Paused))),
+				mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newDomain("testvmi", api.Shutoff)}, nil),
+			)
+
+			standalone.WatchStandaloneLifecycle(mockDM, mockConn, stop, onDomainStop)
+			Expect(domainStops).To(Equal(1))
+		})
+
+		It("should tear down once the domain crashed", func() {
+			gomock.InOrder(
+				mockDM.EXPECT().ListAllDomains().DoAndReturn(listThenEvent(newDomain("testvmi", api.Running))),
+				mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newDomain("testvmi", api.Crashed)}, nil),
+			)
+
+			standalone.WatchStandaloneLifecycle(mockDM, mockConn, stop, onDomainStop)
+			Expect(domainStops).To(Equal(1))
+		})
+
+		It("should only tear down once every domain is down", func() {
+			gomock.InOrder(
+				mockDM.EXPECT().ListAllDomains().DoAndReturn(listThenEvent(newDomain("testvmi1", api.Running), newDomain("testvmi2", api.Running))),
+				mockDM.EXPECT().ListAllDomains().DoAndReturn(listThenEvent(newDomain("testvmi1", api.Shutoff), newDomain("testvmi2", api.Running))),
+				mockDM.EXPECT().ListAllDomains().DoAndReturn(listThenEvent(newDomain("testvmi2", api.Running))),
+				mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newDomain("testvmi2", api.Crashed)}, nil),
+			)
+
+			standalone.WatchStandaloneLifecycle(mockDM, mockConn, stop, onDomainStop)
+			Expect(domainStops).To(Equal(1))
+		})
+
+		It("should stop watching once the domain is gone", func() {
+			gomock.InOrder(
+				mockDM.EXPECT().ListAllDomains().DoAndReturn(listThenEvent(newDomain("testvmi", api.Running))),
+				mockDM.EXPECT().ListAllDomains().Return(nil, nil),
+			)
+
+			standalone.WatchStandaloneLifecycle(mockDM, mockConn, stop, onDomainStop)
+			Expect(domainStops).To(BeZero())
+		})
+
+		It("should stop watching on the stop signal", func() {
+			mockDM.EXPECT().ListAllDomains().Return([]*api.Domain{newDomain("testvmi", api.Running)}, nil)
+			close(stop)
+
+			standalone.WatchStandaloneLifecycle(mockDM, mockConn, stop, onDomainStop)
+			Expect(domainStops).To(BeZero())
+		})
 	})
 })
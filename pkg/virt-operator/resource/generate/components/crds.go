@@ -495,6 +495,7 @@ func NewVirtualMachineSnapshotCrd() (*extv1.CustomResourceDefinition, error) {
 		{Name: "ReadyToUse", Type: "boolean", JSONPath: ".status.readyToUse"},
 		{Name: "CreationTime", Type: "date", JSONPath: ".status.creationTime"},
 		{Name: "Error", Type: "string", JSONPath: errorMessageJSONPath},
+		{Name: "Description", Type: "string", JSONPath: ".status.description", Priority: 1},
 	})
 	if err != nil {
 		return nil, err
@@ -133,7 +133,7 @@ var _ = Describe("CRDs", func() {
 		Entry("for VirtualMachineInstanceMigration", NewVirtualMachineInstanceMigrationCrd, "Phase", "VMI"),
 		Entry("for KubeVirt", NewKubeVirtCrd, "Age", "Phase"),
 		Entry("for VirtualMachinePool", NewVirtualMachinePoolCrd, "Desired", "Current", "Ready", "Age"),
-		Entry("for VirtualMachineSnapshot", NewVirtualMachineSnapshotCrd, "SourceKind", "SourceName", "Phase", "ReadyToUse", "CreationTime", "Error"),
+		Entry("for VirtualMachineSnapshot", NewVirtualMachineSnapshotCrd, "SourceKind", "SourceName", "Phase", "ReadyToUse", "CreationTime", "Error", "Description"),
 		Entry("for VirtualMachineSnapshotContent", NewVirtualMachineSnapshotContentCrd, "ReadyToUse", "CreationTime", "Error"),
 		Entry("for VirtualMachineRestore", NewVirtualMachineRestoreCrd, "TargetKind", "TargetName", "Complete", "RestoreTime"),
 		Entry("for VirtualMachineExport", NewVirtualMachineExportCrd, "SourceKind", "SourceName", "Phase"),
@@ -271,9 +271,10 @@ var _ = Describe("CRDs", func() {
 					Error: &snapshotv1beta1.Error{
 						Message: pointer.P("test-error"),
 					},
+					Description: "before upgrade",
 				},
 			},
-			"VirtualMachine", "test-vm", "InProgress", "false", timestamp, "test-error",
+			"VirtualMachine", "test-vm", "InProgress", "false", timestamp, "test-error", "before upgrade",
 		),
 		Entry("for VirtualMachineSnapshotContent", NewVirtualMachineSnapshotContentCrd,
 			snapshotv1beta1.VirtualMachineSnapshotContent{
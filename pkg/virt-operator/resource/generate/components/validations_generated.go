@@ -26015,6 +26015,58 @@ var CRDsValidation map[string]string = map[string]string{
       description: VirtualMachineRestoreSpec is the spec for a VirtualMachineRestore
         resource
       properties:
+        autoSnapshotBeforeInPlaceRestore:
+          description: |-
+            AutoSnapshotBeforeInPlaceRestore takes a VirtualMachineSnapshot of the existing
+            target before an InPlace restore replaces its volumes, as a rollback point
+          type: boolean
+        excludeVolumes:
+          description: |-
+            ExcludeVolumes lists the volumes of the snapshot which are not restored, they
+            are handled like the volumes missing from IncludeVolumes.
+            Mutually exclusive with IncludeVolumes
+          items:
+            type: string
+          type: array
+          x-kubernetes-list-type: set
+        includeVolumes:
+          description: |-
+            IncludeVolumes restricts the restore to the listed volumes of the snapshot.
+            A volume which is not restored keeps its current source on an existing target,
+            and is left out of a newly created target together with its disk.
+            Mutually exclusive with ExcludeVolumes
+          items:
+            type: string
+          type: array
+          x-kubernetes-list-type: set
+        metadataRestorePolicy:
+          description: |-
+            MetadataRestorePolicy controls which of the snapshotted VirtualMachine labels and
+            annotations are applied to the target. When unset, a newly created target gets all
+            of them and an existing target keeps its own.
+          properties:
+            annotationKeys:
+              description: AnnotationKeys lists the annotation keys to restore
+                when Type is Selective
+              items:
+                type: string
+              type: array
+              x-kubernetes-list-type: set
+            labelKeys:
+              description: LabelKeys lists the label keys to restore when Type
+                is Selective
+              items:
+                type: string
+              type: array
+              x-kubernetes-list-type: set
+            type:
+              description: |-
+                MetadataRestorePolicyType defines which VirtualMachine labels and annotations captured
+                in the snapshot are applied to the restored VirtualMachine
+              type: string
+          required:
+          - type
+          type: object
         patches:
           description: |-
             If the target for the restore does not exist, it will be created. Patches holds JSON patches that would be
@@ -26025,6 +26077,49 @@ var CRDsValidation map[string]string = map[string]string{
             type: string
           type: array
           x-kubernetes-list-type: atomic
+        placementPolicy:
+          description: |-
+            PlacementPolicy controls whether the restored VirtualMachine keeps the
+            nodeSelector, affinity and tolerations captured in the snapshot.
+            Defaults to Preserve
+          type: string
+        postRestoreCloudInit:
+          description: |-
+            PostRestoreCloudInit is cloud-init user data which is added as a MIME multipart
+            part to the user data of the cloud-init volume of the restored VirtualMachine,
+            keeping its original user data, or as a NoCloud volume when it has none. It is
+            applied before Patches. The restored VirtualMachine gets a firmware UUID derived
+            from its name, which cloud-init uses as instance ID, so the restore must target a
+            new name. User data from a Secret can't be merged and fails the restore.
+          type: string
+        resourceRemapping:
+          description: |-
+            ResourceRemapping maps Secrets and ConfigMaps referenced by the snapshotted
+            VirtualMachine to the ones the restored VirtualMachine should reference.
+            Only the existence of ConfigMap targets is verified on admission.
+          items:
+            description: |-
+              ResourceRemapping replaces the name of a Secret or ConfigMap referenced by the
+              snapshotted VirtualMachine in the restored VirtualMachine
+            properties:
+              kind:
+                description: ResourceRemappingKind is the kind of resource a ResourceRemapping
+                  applies to
+                type: string
+              sourceName:
+                description: SourceName is the name referenced by the snapshotted
+                  VirtualMachine
+                type: string
+              targetName:
+                description: TargetName is the name referenced by the restored VirtualMachine
+                type: string
+            required:
+            - kind
+            - sourceName
+            - targetName
+            type: object
+          type: array
+          x-kubernetes-list-type: atomic
         target:
           description: initially only VirtualMachine type supported
           properties:
@@ -26045,12 +26140,29 @@ var CRDsValidation map[string]string = map[string]string{
           - name
           type: object
           x-kubernetes-map-type: atomic
+        targetNamespace:
+          description: |-
+            TargetNamespace is the namespace the restored VirtualMachine and its volumes
+            are created in. Defaults to the namespace of the restore, which the snapshot
+            is always read from. Restoring volumes into another namespace requires the
+            CrossNamespaceVolumeDataSource feature and a ReferenceGrant allowing PVCs of
+            the target namespace to reference VolumeSnapshots of the restore namespace.
+          type: string
         targetReadinessPolicy:
           description: |-
             TargetReadinessPolicy defines how to handle the restore in case
             the target is not ready
           type: string
+        virtualMachineSnapshotContentName:
+          description: |-
+            VirtualMachineSnapshotContentName restores directly from a ready
+            VirtualMachineSnapshotContent, for example one replicated from another
+            cluster, without requiring its VirtualMachineSnapshot to exist
+          type: string
         virtualMachineSnapshotName:
+          description: |-
+            VirtualMachineSnapshotName is the VirtualMachineSnapshot to restore.
+            Exactly one of it and VirtualMachineSnapshotContentName must be set
           type: string
         volumeOwnershipPolicy:
           description: VolumeOwnershipPolicy defines what owns volumes once they're
@@ -26064,6 +26176,13 @@ var CRDsValidation map[string]string = map[string]string{
             description: VolumeRestoreOverride specifies how a volume should be restored
               from a VirtualMachineSnapshot
             properties:
+              accessModes:
+                description: AccessModes replaces the access modes of the snapshotted
+                  volume
+                items:
+                  type: string
+                type: array
+                x-kubernetes-list-type: atomic
               annotations:
                 additionalProperties:
                   type: string
@@ -26072,8 +26191,20 @@ var CRDsValidation map[string]string = map[string]string{
                 additionalProperties:
                   type: string
                 type: object
+              provisioning:
+                description: |-
+                  Provisioning hints whether the restored volume should be thin or thick
+                  provisioned. It is passed to the provisioner as a PVC annotation and is
+                  only honored by storage classes which declare support for it
+                type: string
               restoreName:
                 type: string
+              storageClassName:
+                description: |-
+                  StorageClassName is the storage class the volume is restored to, instead
+                  of the storage class of the snapshotted volume. The restore fails if the
+                  storage class does not exist
+                type: string
               volumeName:
                 type: string
             type: object
@@ -26085,7 +26216,6 @@ var CRDsValidation map[string]string = map[string]string{
           type: string
       required:
       - target
-      - virtualMachineSnapshotName
       type: object
     status:
       description: VirtualMachineRestoreStatus is the status for a VirtualMachineRestore
@@ -26125,6 +26255,33 @@ var CRDsValidation map[string]string = map[string]string{
             type: string
           type: array
           x-kubernetes-list-type: set
+        memoryDump:
+          description: |-
+            MemoryDump is where the guest memory dump captured by the snapshot is
+            kept. The dump is not restored, the restored VM boots from its volumes
+          properties:
+            fileName:
+              description: FileName is the name of the memory dump file on the volume
+              type: string
+            volumeSnapshotName:
+              description: VolumeSnapshotName is the VolumeSnapshot holding the memory
+                dump
+              type: string
+          required:
+          - volumeSnapshotName
+          type: object
+        preRestoreSnapshotName:
+          description: |-
+            PreRestoreSnapshotName is the VirtualMachineSnapshot taken of the target
+            before the restore, when AutoSnapshotBeforeInPlaceRestore is set
+          type: string
+        progress:
+          description: |-
+            Progress is the percentage of the restored volumes which are ready, weighted
+            by their capacity, or by their count when a capacity is unknown. It only
+            reaches 100 when the restore is complete.
+          format: int32
+          type: integer
         restoreTime:
           format: date-time
           type: string
@@ -26176,11 +26333,44 @@ var CRDsValidation map[string]string = map[string]string{
       description: VirtualMachineSnapshotSpec is the spec for a VirtualMachineSnapshot
         resource
       properties:
+        deadlineGracePeriod:
+          description: |-
+            DeadlineGracePeriod extends the FailureDeadline once by the given
+            duration, if the volume snapshots are still progressing when the
+            deadline is reached.
+          type: string
         deletionPolicy:
           description: |-
             DeletionPolicy defines that to do with VirtualMachineSnapshot
             when VirtualMachineSnapshot is deleted
           type: string
+        deletionTimeout:
+          description: |-
+            DeletionTimeout is how long the deletion of the snapshot may wait for
+            its content and volume snapshots before the pending ones are reported.
+            Defaults to 5min
+          type: string
+        description:
+          description: |-
+            Description is a free form note on why the snapshot was taken, for
+            example before an upgrade. It is echoed into the status.
+          type: string
+        excludeUnsnapshottableVolumes:
+          description: |-
+            ExcludeUnsnapshottableVolumes controls what happens to PVC volumes
+            whose storage class has no VolumeSnapshotClass. When true, the
+            default, they are skipped and listed as excluded volumes. When false,
+            the snapshot fails with the NoVolumeSnapshotClass error reason.
+          type: boolean
+        excludedVolumes:
+          description: |-
+            ExcludedVolumes lists the names of VM volumes which are not captured.
+            They are listed as excluded volumes, and names the VM does not have
+            are reported with the ExcludedVolumesMissing condition.
+          items:
+            type: string
+          type: array
+          x-kubernetes-list-type: atomic
         failureDeadline:
           description: |-
             This time represents the number of seconds we permit the vm snapshot
@@ -26188,6 +26378,110 @@ var CRDsValidation map[string]string = map[string]string{
             as failed.
             Defaults to DefaultFailureDeadline - 5min
           type: string
+        forceDelete:
+          description: |-
+            ForceDelete removes the snapshot finalizer once its deletion has been
+            stuck for too long, even if the content or its volume snapshots are
+            still being deleted.
+            Unlike the rest of the spec it can be set after creation.
+          type: boolean
+        hooks:
+          description: |-
+            Hooks are commands run inside the guest through the guest agent
+            around the filesystem freeze of a running VM. They are passed to the
+            freeze and unfreeze of the VM, so they require a user allowed to
+            freeze it.
+          properties:
+            postThaw:
+              description: |-
+                PostThaw hooks run in order once the guest filesystems are thawed,
+                or once the snapshot is done without freezing them
+              items:
+                description: SnapshotHook is a command run inside the guest
+                properties:
+                  args:
+                    description: Args are the arguments passed to the command
+                    items:
+                      type: string
+                    type: array
+                    x-kubernetes-list-type: atomic
+                  command:
+                    description: Command is the path of the executable run inside
+                      the guest
+                    type: string
+                  name:
+                    description: Name identifies the hook in failure messages
+                    type: string
+                  timeout:
+                    description: |-
+                      Timeout is the time the command is given to complete.
+                      Defaults to 30s, at most 2m
+                    type: string
+                required:
+                - command
+                - name
+                type: object
+              type: array
+              x-kubernetes-list-type: atomic
+            preFreeze:
+              description: |-
+                PreFreeze hooks run in order before the guest filesystems are frozen.
+                If one fails, the guest is not frozen and the freeze is retried from
+                the first hook.
+              items:
+                description: SnapshotHook is a command run inside the guest
+                properties:
+                  args:
+                    description: Args are the arguments passed to the command
+                    items:
+                      type: string
+                    type: array
+                    x-kubernetes-list-type: atomic
+                  command:
+                    description: Command is the path of the executable run inside
+                      the guest
+                    type: string
+                  name:
+                    description: Name identifies the hook in failure messages
+                    type: string
+                  timeout:
+                    description: |-
+                      Timeout is the time the command is given to complete.
+                      Defaults to 30s, at most 2m
+                    type: string
+                required:
+                - command
+                - name
+                type: object
+              type: array
+              x-kubernetes-list-type: atomic
+          type: object
+        includeEphemeralVolumes:
+          description: |-
+            IncludeEphemeralVolumes also captures the PVCs backing ephemeral
+            volumes, which are otherwise listed as excluded volumes. Only the
+            read-only backing PVC is captured, not the guest writes.
+          type: boolean
+        includeMemoryDump:
+          description: |-
+            IncludeMemoryDump dumps the guest memory of a running VM into a PVC
+            before its volumes are frozen, and captures the dump with them.
+            The dump is not loaded on restore, the restore reports where it
+            is kept.
+          type: boolean
+        pauseDuringSnapshot:
+          description: |-
+            PauseDuringSnapshot pauses a running VM while its volumes are
+            captured and unpauses it afterwards, instead of freezing the
+            guest filesystems.
+          type: boolean
+        snapshotType:
+          description: |-
+            SnapshotType selects what the snapshot captures. DefinitionOnly
+            captures the VM definition without any volume backups and is ready
+            as soon as its content is created.
+            Defaults to Full
+          type: string
         source:
           description: |-
             TypedLocalObjectReference contains enough information to let you locate the
@@ -26210,6 +26504,12 @@ var CRDsValidation map[string]string = map[string]string{
           - name
           type: object
           x-kubernetes-map-type: atomic
+        ttlAfterSuccess:
+          description: |-
+            TTLAfterSuccess is how long a succeeded snapshot is kept after its
+            creation time before it is deleted, honoring its DeletionPolicy.
+            Unset or zero keeps the snapshot until it is deleted explicitly.
+          type: string
       required:
       - source
       type: object
@@ -26244,15 +26544,28 @@ var CRDsValidation map[string]string = map[string]string{
             type: object
           type: array
           x-kubernetes-list-type: atomic
+        consistencyLevel:
+          description: |-
+            ConsistencyLevel classifies the guest data consistency of the snapshot,
+            derived from the source indications once it succeeded
+          type: string
         creationTime:
           format: date-time
           nullable: true
           type: string
+        description:
+          description: Description is the description given in the spec
+          type: string
         error:
           description: Error is the last error encountered during the snapshot/restore
           properties:
             message:
               type: string
+            reason:
+              description: |-
+                Reason is a machine readable cause of the error, set for errors which
+                the controller detects itself
+              type: string
             time:
               format: date-time
               type: string
@@ -26266,11 +26579,23 @@ var CRDsValidation map[string]string = map[string]string{
             type: string
           type: array
           x-kubernetes-list-type: set
+        nextRetryTime:
+          description: NextRetryTime is when the controller retries the failed
+            step next
+          format: date-time
+          nullable: true
+          type: string
         phase:
           description: VirtualMachineSnapshotPhase is the current phase of the VirtualMachineSnapshot
           type: string
         readyToUse:
           type: boolean
+        retryCount:
+          description: |-
+            RetryCount is the number of times the controller retried a failed
+            step of the snapshot, such as freezing the guest
+          format: int32
+          type: integer
         snapshotVolumes:
           description: SnapshotVolumesLists includes the list of volumes which were
             included in the snapshot and volumes which were excluded from the snapshot
@@ -26280,6 +26605,14 @@ var CRDsValidation map[string]string = map[string]string{
                 type: string
               type: array
               x-kubernetes-list-type: set
+            hotplugVolumes:
+              description: |-
+                HotplugVolumes lists the included volumes which were hotplugged
+                to the running VMI rather than being part of the VM spec
+              items:
+                type: string
+              type: array
+              x-kubernetes-list-type: set
             includedVolumes:
               items:
                 type: string
@@ -26287,6 +26620,10 @@ var CRDsValidation map[string]string = map[string]string{
               x-kubernetes-list-type: set
           type: object
         sourceIndications:
+          description: |-
+            SourceIndications describe the state of the source VM when the
+            snapshot was taken. When both are set they take precedence over the
+            deprecated Indications, which are kept in sync with them.
           items:
             description: SourceIndication provides an indication of the source VM
               with its description message
@@ -26340,6 +26677,20 @@ var CRDsValidation map[string]string = map[string]string{
       description: VirtualMachineSnapshotContentSpec is the spec for a VirtualMachineSnapshotContent
         resource
       properties:
+        memoryDumpBackup:
+          description: MemoryDumpBackup tracks the guest memory dump captured with the
+            volumes
+          properties:
+            fileName:
+              description: FileName is the name of the memory dump file on the volume
+              type: string
+            volumeName:
+              description: VolumeName is the name of the memory dump volume in the
+                volume backups
+              type: string
+          required:
+          - volumeName
+          type: object
         source:
           description: SourceSpec contains the appropriate spec for the resource being
             snapshotted
@@ -31379,6 +31730,152 @@ var CRDsValidation map[string]string = map[string]string{
           items:
             description: VolumeBackup contains the data neeed to restore a PVC
             properties:
+              disk:
+                description: |-
+                  Disk is the disk definition of a hotplugged volume, so that the
+                  restored volume is hotplugged with the same bus and settings
+                properties:
+                  blockSize:
+                    description: If specified, the virtual disk will be presented
+                      with the given block sizes.
+                    properties:
+                      custom:
+                        description: CustomBlockSize represents the desired logical
+                          and physical block size for a VM disk.
+                        properties:
+                          discardGranularity:
+                            type: integer
+                          logical:
+                            type: integer
+                          physical:
+                            type: integer
+                        type: object
+                      matchVolume:
+                        description: Represents if a feature is enabled or disabled.
+                        properties:
+                          enabled:
+                            description: |-
+                              Enabled determines if the feature should be enabled or disabled on the guest.
+                              Defaults to true.
+                            type: boolean
+                        type: object
+                    type: object
+                  bootOrder:
+                    description: |-
+                      BootOrder is an integer value > 0, used to determine ordering of boot devices.
+                      Lower values take precedence.
+                      Each disk or interface that has a boot order must have a unique value.
+                      Disks without a boot order are not tried if a disk with a boot order exists.
+                    type: integer
+                  cache:
+                    description: |-
+                      Cache specifies which kvm disk cache mode should be used.
+                      Supported values are:
+                      none: Guest I/O not cached on the host, but may be kept in a disk cache.
+                      writethrough: Guest I/O cached on the host but written through to the physical medium. Slowest but with most guarantees.
+                      writeback: Guest I/O cached on the host.
+                      Defaults to none if the storage supports O_DIRECT, otherwise writethrough.
+                    type: string
+                  cdrom:
+                    description: Attach a volume as a cdrom to the vmi.
+                    properties:
+                      bus:
+                        description: |-
+                          Bus indicates the type of disk device to emulate.
+                          supported values: virtio, sata, scsi.
+                        type: string
+                      readonly:
+                        description: |-
+                          ReadOnly.
+                          Defaults to true.
+                        type: boolean
+                      tray:
+                        description: |-
+                          Tray indicates if the tray of the device is open or closed.
+                          Allowed values are "open" and "closed".
+                          Defaults to closed.
+                        type: string
+                    type: object
+                  changedBlockTracking:
+                    description: |-
+                      ChangedBlockTracking indicates this disk should have CBT option
+                      Defaults to false.
+                    type: boolean
+                  dedicatedIOThread:
+                    description: |-
+                      dedicatedIOThread indicates this disk should have an exclusive IO Thread.
+                      Enabling this implies useIOThreads = true.
+                      Defaults to false.
+                    type: boolean
+                  disk:
+                    description: Attach a volume as a disk to the vmi.
+                    properties:
+                      bus:
+                        description: |-
+                          Bus indicates the type of disk device to emulate.
+                          supported values: virtio, sata, scsi, usb.
+                        type: string
+                      pciAddress:
+                        description: 'If specified, the virtual disk will be placed
+                          on the guests pci address with the specified PCI address.
+                          For example: 0000:81:01.10'
+                        type: string
+                      readonly:
+                        description: |-
+                          ReadOnly.
+                          Defaults to false.
+                        type: boolean
+                    type: object
+                  errorPolicy:
+                    description: If specified, it can change the default error policy
+                      (stop) for the disk
+                    type: string
+                  io:
+                    description: |-
+                      IO specifies which QEMU disk IO mode should be used.
+                      Supported values are: native, default, threads.
+                    type: string
+                  lun:
+                    description: Attach a volume as a LUN to the vmi.
+                    properties:
+                      bus:
+                        description: |-
+                          Bus indicates the type of disk device to emulate.
+                          supported values: virtio, sata, scsi.
+                        type: string
+                      readonly:
+                        description: |-
+                          ReadOnly.
+                          Defaults to false.
+                        type: boolean
+                      reservation:
+                        description: Reservation indicates if the disk needs to support
+                          the persistent reservation for the SCSI disk
+                        type: boolean
+                    type: object
+                  name:
+                    description: Name is the device name
+                    type: string
+                  serial:
+                    description: Serial provides the ability to specify a serial number
+                      for the disk device.
+                    type: string
+                  shareable:
+                    description: If specified the disk is made sharable and multiple
+                      write from different VMs are permitted
+                    type: boolean
+                  tag:
+                    description: If specified, disk address and its tag will be provided
+                      to the guest via config drive metadata
+                    type: string
+                required:
+                - name
+                type: object
+              hotplug:
+                description: |-
+                  Hotplug indicates the volume was hotplugged to the running VMI
+                  and is not part of the VM spec template
+                type: boolean
               persistentVolumeClaim:
                 properties:
                   metadata:
@@ -31611,6 +32108,11 @@ var CRDsValidation map[string]string = map[string]string{
           properties:
             message:
               type: string
+            reason:
+              description: |-
+                Reason is a machine readable cause of the error, set for errors which
+                the controller detects itself
+              type: string
             time:
               format: date-time
               type: string
@@ -31625,11 +32127,21 @@ var CRDsValidation map[string]string = map[string]string{
                 format: date-time
                 nullable: true
                 type: string
+              duration:
+                description: |-
+                  Duration is how long the volume snapshot took to become ready to
+                  use after it was created
+                type: string
               error:
                 description: Error is the last error encountered during the snapshot/restore
                 properties:
                   message:
                     type: string
+                  reason:
+                    description: |-
+                      Reason is a machine readable cause of the error, set for errors which
+                      the controller detects itself
+                    type: string
                   time:
                     format: date-time
                     type: string
@@ -2890,6 +2890,34 @@ type VirtualMachineInstanceFileSystem struct {
 // FreezeUnfreezeTimeout represent the time unfreeze will be triggered if guest was not unfrozen by unfreeze command
 type FreezeUnfreezeTimeout struct {
 	UnfreezeTimeout *metav1.Duration `json:"unfreezeTimeout"`
+	// Hooks run in order inside the guest before the filesystems are frozen.
+	// The guest is not frozen when one of them fails.
+	// +optional
+	// +listType=atomic
+	Hooks []FreezeHook `json:"hooks,omitempty"`
+}
+
+// UnfreezeOptions may be provided on unfreeze request.
+type UnfreezeOptions struct {
+	// Hooks run in order inside the guest once the filesystems are thawed
+	// +optional
+	// +listType=atomic
+	Hooks []FreezeHook `json:"hooks,omitempty"`
+}
+
+// FreezeHook is a command run inside the guest through the guest agent around the filesystem freeze
+type FreezeHook struct {
+	// Name identifies the hook in failure messages
+	Name string `json:"name"`
+	// Command is the path of the executable run inside the guest
+	Command string `json:"command"`
+	// Args are the arguments passed to the command
+	// +optional
+	// +listType=atomic
+	Args []string `json:"args,omitempty"`
+	// TimeoutSeconds is the time the command is given to complete
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // VirtualMachineMemoryDumpRequest represent the memory dump request phase and info
@@ -828,7 +828,25 @@ func (VirtualMachineInstanceFileSystem) SwaggerDoc() map[string]string {
 
 func (FreezeUnfreezeTimeout) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"": "FreezeUnfreezeTimeout represent the time unfreeze will be triggered if guest was not unfrozen by unfreeze command",
+		"":      "FreezeUnfreezeTimeout represent the time unfreeze will be triggered if guest was not unfrozen by unfreeze command",
+		"hooks": "Hooks run in order inside the guest before the filesystems are frozen.\nThe guest is not frozen when one of them fails.\n+optional\n+listType=atomic",
+	}
+}
+
+func (UnfreezeOptions) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "UnfreezeOptions may be provided on unfreeze request.",
+		"hooks": "Hooks run in order inside the guest once the filesystems are thawed\n+optional\n+listType=atomic",
+	}
+}
+
+func (FreezeHook) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "FreezeHook is a command run inside the guest through the guest agent around the filesystem freeze",
+		"name":           "Name identifies the hook in failure messages",
+		"command":        "Command is the path of the executable run inside the guest",
+		"args":           "Args are the arguments passed to the command\n+optional\n+listType=atomic",
+		"timeoutSeconds": "TimeoutSeconds is the time the command is given to complete\n+optional",
 	}
 }
 
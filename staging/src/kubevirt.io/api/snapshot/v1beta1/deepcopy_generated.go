@@ -24,9 +24,11 @@ Copyright The KubeVirt Authors.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
+	apicorev1 "kubevirt.io/api/core/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -59,6 +61,11 @@ func (in *Error) DeepCopyInto(out *Error) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Reason != nil {
+		in, out := &in.Reason, &out.Reason
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -72,6 +79,74 @@ func (in *Error) DeepCopy() *Error {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryDumpBackup) DeepCopyInto(out *MemoryDumpBackup) {
+	*out = *in
+	if in.FileName != nil {
+		in, out := &in.FileName, &out.FileName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryDumpBackup.
+func (in *MemoryDumpBackup) DeepCopy() *MemoryDumpBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryDumpBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryDumpLocation) DeepCopyInto(out *MemoryDumpLocation) {
+	*out = *in
+	if in.FileName != nil {
+		in, out := &in.FileName, &out.FileName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryDumpLocation.
+func (in *MemoryDumpLocation) DeepCopy() *MemoryDumpLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryDumpLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataRestorePolicy) DeepCopyInto(out *MetadataRestorePolicy) {
+	*out = *in
+	if in.LabelKeys != nil {
+		in, out := &in.LabelKeys, &out.LabelKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnnotationKeys != nil {
+		in, out := &in.AnnotationKeys, &out.AnnotationKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataRestorePolicy.
+func (in *MetadataRestorePolicy) DeepCopy() *MetadataRestorePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataRestorePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistentVolumeClaim) DeepCopyInto(out *PersistentVolumeClaim) {
 	*out = *in
@@ -90,6 +165,78 @@ func (in *PersistentVolumeClaim) DeepCopy() *PersistentVolumeClaim {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRemapping) DeepCopyInto(out *ResourceRemapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRemapping.
+func (in *ResourceRemapping) DeepCopy() *ResourceRemapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRemapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotHook) DeepCopyInto(out *SnapshotHook) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotHook.
+func (in *SnapshotHook) DeepCopy() *SnapshotHook {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotHooks) DeepCopyInto(out *SnapshotHooks) {
+	*out = *in
+	if in.PreFreeze != nil {
+		in, out := &in.PreFreeze, &out.PreFreeze
+		*out = make([]SnapshotHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostThaw != nil {
+		in, out := &in.PostThaw, &out.PostThaw
+		*out = make([]SnapshotHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotHooks.
+func (in *SnapshotHooks) DeepCopy() *SnapshotHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnapshotVolumesLists) DeepCopyInto(out *SnapshotVolumesLists) {
 	*out = *in
@@ -103,6 +250,11 @@ func (in *SnapshotVolumesLists) DeepCopyInto(out *SnapshotVolumesLists) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.HotplugVolumes != nil {
+		in, out := &in.HotplugVolumes, &out.HotplugVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -241,6 +393,11 @@ func (in *VirtualMachineRestoreList) DeepCopyObject() runtime.Object {
 func (in *VirtualMachineRestoreSpec) DeepCopyInto(out *VirtualMachineRestoreSpec) {
 	*out = *in
 	in.Target.DeepCopyInto(&out.Target)
+	if in.VirtualMachineSnapshotContentName != nil {
+		in, out := &in.VirtualMachineSnapshotContentName, &out.VirtualMachineSnapshotContentName
+		*out = new(string)
+		**out = **in
+	}
 	if in.TargetReadinessPolicy != nil {
 		in, out := &in.TargetReadinessPolicy, &out.TargetReadinessPolicy
 		*out = new(TargetReadinessPolicy)
@@ -251,6 +408,11 @@ func (in *VirtualMachineRestoreSpec) DeepCopyInto(out *VirtualMachineRestoreSpec
 		*out = new(VolumeRestorePolicy)
 		**out = **in
 	}
+	if in.AutoSnapshotBeforeInPlaceRestore != nil {
+		in, out := &in.AutoSnapshotBeforeInPlaceRestore, &out.AutoSnapshotBeforeInPlaceRestore
+		*out = new(bool)
+		**out = **in
+	}
 	if in.VolumeOwnershipPolicy != nil {
 		in, out := &in.VolumeOwnershipPolicy, &out.VolumeOwnershipPolicy
 		*out = new(VolumeOwnershipPolicy)
@@ -263,6 +425,41 @@ func (in *VirtualMachineRestoreSpec) DeepCopyInto(out *VirtualMachineRestoreSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IncludeVolumes != nil {
+		in, out := &in.IncludeVolumes, &out.IncludeVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeVolumes != nil {
+		in, out := &in.ExcludeVolumes, &out.ExcludeVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetadataRestorePolicy != nil {
+		in, out := &in.MetadataRestorePolicy, &out.MetadataRestorePolicy
+		*out = new(MetadataRestorePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlacementPolicy != nil {
+		in, out := &in.PlacementPolicy, &out.PlacementPolicy
+		*out = new(PlacementPolicy)
+		**out = **in
+	}
+	if in.ResourceRemapping != nil {
+		in, out := &in.ResourceRemapping, &out.ResourceRemapping
+		*out = make([]ResourceRemapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostRestoreCloudInit != nil {
+		in, out := &in.PostRestoreCloudInit, &out.PostRestoreCloudInit
+		*out = new(string)
+		**out = **in
+	}
+	if in.TargetNamespace != nil {
+		in, out := &in.TargetNamespace, &out.TargetNamespace
+		*out = new(string)
+		**out = **in
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]string, len(*in))
@@ -305,6 +502,21 @@ func (in *VirtualMachineRestoreStatus) DeepCopyInto(out *VirtualMachineRestoreSt
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PreRestoreSnapshotName != nil {
+		in, out := &in.PreRestoreSnapshotName, &out.PreRestoreSnapshotName
+		*out = new(string)
+		**out = **in
+	}
+	if in.MemoryDump != nil {
+		in, out := &in.MemoryDump, &out.MemoryDump
+		*out = new(MemoryDumpLocation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]Condition, len(*in))
@@ -438,6 +650,11 @@ func (in *VirtualMachineSnapshotContentSpec) DeepCopyInto(out *VirtualMachineSna
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MemoryDumpBackup != nil {
+		in, out := &in.MemoryDumpBackup, &out.MemoryDumpBackup
+		*out = new(MemoryDumpBackup)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -535,6 +752,46 @@ func (in *VirtualMachineSnapshotSpec) DeepCopyInto(out *VirtualMachineSnapshotSp
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.DeadlineGracePeriod != nil {
+		in, out := &in.DeadlineGracePeriod, &out.DeadlineGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeletionTimeout != nil {
+		in, out := &in.DeletionTimeout, &out.DeletionTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTLAfterSuccess != nil {
+		in, out := &in.TTLAfterSuccess, &out.TTLAfterSuccess
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ExcludeUnsnapshottableVolumes != nil {
+		in, out := &in.ExcludeUnsnapshottableVolumes, &out.ExcludeUnsnapshottableVolumes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SnapshotType != nil {
+		in, out := &in.SnapshotType, &out.SnapshotType
+		*out = new(SnapshotType)
+		**out = **in
+	}
+	if in.ExcludedVolumes != nil {
+		in, out := &in.ExcludedVolumes, &out.ExcludedVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeMemoryDump != nil {
+		in, out := &in.IncludeMemoryDump, &out.IncludeMemoryDump
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(SnapshotHooks)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -597,6 +854,10 @@ func (in *VirtualMachineSnapshotStatus) DeepCopyInto(out *VirtualMachineSnapshot
 		*out = new(SnapshotVolumesLists)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -619,6 +880,11 @@ func (in *VolumeBackup) DeepCopyInto(out *VolumeBackup) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Disk != nil {
+		in, out := &in.Disk, &out.Disk
+		*out = new(apicorev1.Disk)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -670,6 +936,21 @@ func (in *VolumeRestoreOverride) DeepCopyInto(out *VolumeRestoreOverride) {
 			(*out)[key] = val
 		}
 	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(VolumeProvisioning)
+		**out = **in
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -700,6 +981,11 @@ func (in *VolumeSnapshotStatus) DeepCopyInto(out *VolumeSnapshotStatus) {
 		*out = new(Error)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
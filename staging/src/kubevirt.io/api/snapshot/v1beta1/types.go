@@ -59,6 +59,10 @@ const (
 	VirtualMachineSnapshotContentRetain DeletionPolicy = "Retain"
 )
 
+// SnapshotAliasOfLabel is set by the snapshot controller on a VirtualMachineSnapshot
+// which reuses the content of the VirtualMachineSnapshot it names
+const SnapshotAliasOfLabel = "snapshot.kubevirt.io/alias-of"
+
 // VirtualMachineSnapshotSpec is the spec for a VirtualMachineSnapshot resource
 type VirtualMachineSnapshotSpec struct {
 	Source corev1.TypedLocalObjectReference `json:"source"`
@@ -72,8 +76,131 @@ type VirtualMachineSnapshotSpec struct {
 	// Defaults to DefaultFailureDeadline - 5min
 	// +optional
 	FailureDeadline *metav1.Duration `json:"failureDeadline,omitempty"`
+
+	// DeadlineGracePeriod extends the FailureDeadline once by the given
+	// duration, if the volume snapshots are still progressing when the
+	// deadline is reached.
+	// +optional
+	DeadlineGracePeriod *metav1.Duration `json:"deadlineGracePeriod,omitempty"`
+
+	// PauseDuringSnapshot pauses a running VM while its volumes are
+	// captured and unpauses it afterwards, instead of freezing the
+	// guest filesystems.
+	// +optional
+	PauseDuringSnapshot bool `json:"pauseDuringSnapshot,omitempty"`
+
+	// IncludeEphemeralVolumes also captures the PVCs backing ephemeral
+	// volumes, which are otherwise listed as excluded volumes. Only the
+	// read-only backing PVC is captured, not the guest writes.
+	// +optional
+	IncludeEphemeralVolumes bool `json:"includeEphemeralVolumes,omitempty"`
+
+	// DeletionTimeout is how long the deletion of the snapshot may wait for
+	// its content and volume snapshots before the pending ones are reported.
+	// Defaults to 5min
+	// +optional
+	DeletionTimeout *metav1.Duration `json:"deletionTimeout,omitempty"`
+
+	// ForceDelete removes the snapshot finalizer once its deletion has been
+	// stuck for too long, even if the content or its volume snapshots are
+	// still being deleted.
+	// Unlike the rest of the spec it can be set after creation.
+	// +optional
+	ForceDelete bool `json:"forceDelete,omitempty"`
+
+	// TTLAfterSuccess is how long a succeeded snapshot is kept after its
+	// creation time before it is deleted, honoring its DeletionPolicy.
+	// Unset or zero keeps the snapshot until it is deleted explicitly.
+	// +optional
+	TTLAfterSuccess *metav1.Duration `json:"ttlAfterSuccess,omitempty"`
+
+	// ExcludeUnsnapshottableVolumes controls what happens to PVC volumes
+	// whose storage class has no VolumeSnapshotClass. When true, the
+	// default, they are skipped and listed as excluded volumes. When false,
+	// the snapshot fails with the NoVolumeSnapshotClass error reason.
+	// +optional
+	ExcludeUnsnapshottableVolumes *bool `json:"excludeUnsnapshottableVolumes,omitempty"`
+
+	// Description is a free form note on why the snapshot was taken, for
+	// example before an upgrade. It is echoed into the status.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// SnapshotType selects what the snapshot captures. DefinitionOnly
+	// captures the VM definition without any volume backups and is ready
+	// as soon as its content is created.
+	// Defaults to Full
+	// +optional
+	SnapshotType *SnapshotType `json:"snapshotType,omitempty"`
+
+	// ExcludedVolumes lists the names of VM volumes which are not captured.
+	// They are listed as excluded volumes, and names the VM does not have
+	// are reported with the ExcludedVolumesMissing condition.
+	// +optional
+	// +listType=atomic
+	ExcludedVolumes []string `json:"excludedVolumes,omitempty"`
+
+	// IncludeMemoryDump dumps the guest memory of a running VM into a PVC
+	// before its volumes are frozen, and captures the dump with them.
+	// The dump is not loaded on restore, the restore reports where it
+	// is kept.
+	// +optional
+	IncludeMemoryDump *bool `json:"includeMemoryDump,omitempty"`
+
+	// Hooks are commands run inside the guest through the guest agent
+	// around the filesystem freeze of a running VM. They are passed to the
+	// freeze and unfreeze of the VM, so they require a user allowed to
+	// freeze it.
+	// +optional
+	Hooks *SnapshotHooks `json:"hooks,omitempty"`
+}
+
+// SnapshotHooks are the commands run around the guest filesystem freeze
+type SnapshotHooks struct {
+	// PreFreeze hooks run in order before the guest filesystems are frozen.
+	// If one fails, the guest is not frozen and the freeze is retried from
+	// the first hook.
+	// +optional
+	// +listType=atomic
+	PreFreeze []SnapshotHook `json:"preFreeze,omitempty"`
+
+	// PostThaw hooks run in order once the guest filesystems are thawed,
+	// or once the snapshot is done without freezing them
+	// +optional
+	// +listType=atomic
+	PostThaw []SnapshotHook `json:"postThaw,omitempty"`
+}
+
+// SnapshotHook is a command run inside the guest
+type SnapshotHook struct {
+	// Name identifies the hook in failure messages
+	Name string `json:"name"`
+
+	// Command is the path of the executable run inside the guest
+	Command string `json:"command"`
+
+	// Args are the arguments passed to the command
+	// +optional
+	// +listType=atomic
+	Args []string `json:"args,omitempty"`
+
+	// Timeout is the time the command is given to complete.
+	// Defaults to 30s, at most 2m
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
+// SnapshotType defines what a VirtualMachineSnapshot captures
+type SnapshotType string
+
+const (
+	// SnapshotTypeFull captures the VM definition and its volumes
+	SnapshotTypeFull SnapshotType = "Full"
+
+	// SnapshotTypeDefinitionOnly captures only the VM definition
+	SnapshotTypeDefinitionOnly SnapshotType = "DefinitionOnly"
+)
+
 // Indication is a way to indicate the state of the vm when taking the snapshot
 type Indication string
 
@@ -83,6 +210,9 @@ const (
 	VMSnapshotGuestAgentIndication     Indication = "GuestAgent"
 	VMSnapshotQuiesceFailedIndication  Indication = "QuiesceFailed"
 	VMSnapshotPausedIndication         Indication = "Paused"
+	VMSnapshotSharedVolumeIndication   Indication = "SharedVolume"
+	VMSnapshotResumedIndication        Indication = "Resumed"
+	VMSnapshotMemoryDumpIndication     Indication = "MemoryDump"
 )
 
 // SourceIndication provides an indication of the source VM with its description message
@@ -93,6 +223,21 @@ type SourceIndication struct {
 	Message string `json:"message"`
 }
 
+// ConsistencyLevel is the guest data consistency a VirtualMachineSnapshot provides
+type ConsistencyLevel string
+
+const (
+	// CrashConsistent snapshots hold the disks as after a power loss of the guest
+	CrashConsistent ConsistencyLevel = "CrashConsistent"
+
+	// FilesystemConsistent snapshots were taken while the guest agent had the
+	// filesystems frozen
+	FilesystemConsistent ConsistencyLevel = "FilesystemConsistent"
+
+	// ApplicationConsistent snapshots were taken while the VM was not running
+	ApplicationConsistent ConsistencyLevel = "ApplicationConsistent"
+)
+
 // VirtualMachineSnapshotPhase is the current phase of the VirtualMachineSnapshot
 type VirtualMachineSnapshotPhase string
 
@@ -135,12 +280,34 @@ type VirtualMachineSnapshotStatus struct {
 	// +listType=set
 	Indications []Indication `json:"indications,omitempty"`
 
+	// SourceIndications describe the state of the source VM when the
+	// snapshot was taken. When both are set they take precedence over the
+	// deprecated Indications, which are kept in sync with them.
 	// +optional
 	// +listType=atomic
 	SourceIndications []SourceIndication `json:"sourceIndications,omitempty"`
 
+	// ConsistencyLevel classifies the guest data consistency of the snapshot,
+	// derived from the source indications once it succeeded
+	// +optional
+	ConsistencyLevel ConsistencyLevel `json:"consistencyLevel,omitempty"`
+
 	// +optional
 	SnapshotVolumes *SnapshotVolumesLists `json:"snapshotVolumes,omitempty"`
+
+	// Description is the description given in the spec
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// RetryCount is the number of times the controller retried a failed
+	// step of the snapshot, such as freezing the guest
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty"`
+
+	// NextRetryTime is when the controller retries the failed step next
+	// +optional
+	// +nullable
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
 }
 
 // SnapshotVolumesLists includes the list of volumes which were included in the snapshot and volumes which were excluded from the snapshot
@@ -152,6 +319,12 @@ type SnapshotVolumesLists struct {
 	// +optional
 	// +listType=set
 	ExcludedVolumes []string `json:"excludedVolumes,omitempty"`
+
+	// HotplugVolumes lists the included volumes which were hotplugged
+	// to the running VMI rather than being part of the VM spec
+	// +optional
+	// +listType=set
+	HotplugVolumes []string `json:"hotplugVolumes,omitempty"`
 }
 
 // Error is the last error encountered during the snapshot/restore
@@ -161,8 +334,39 @@ type Error struct {
 
 	// +optional
 	Message *string `json:"message,omitempty"`
+
+	// Reason is a machine readable cause of the error, set for errors which
+	// the controller detects itself
+	// +optional
+	Reason *string `json:"reason,omitempty"`
 }
 
+const (
+	// NoVolumeSnapshotClassErrorReason is the Error reason when a volume of
+	// the source has no VolumeSnapshotClass to be snapshotted with
+	NoVolumeSnapshotClassErrorReason = "NoVolumeSnapshotClass"
+
+	// VolumeNotSnapshottableErrorReason is the Error reason when the
+	// VolumeSnapshotClass of an included volume belongs to another driver
+	VolumeNotSnapshottableErrorReason = "VolumeNotSnapshottable"
+
+	// VolumeSnapshotNotReadyErrorReason is the Error reason when a VolumeSnapshot
+	// of a previously ready snapshot is no longer ready to use
+	VolumeSnapshotNotReadyErrorReason = "VolumeSnapshotNotReady"
+
+	// VolumeSnapshotMissingErrorReason is the Error reason when a VolumeSnapshot
+	// created by a capture still in progress no longer exists
+	VolumeSnapshotMissingErrorReason = "VolumeSnapshotMissing"
+
+	// SnapshotQuotaExceededErrorReason is the Error reason when the CSI driver
+	// refuses a VolumeSnapshot because a snapshot limit or quota is reached
+	SnapshotQuotaExceededErrorReason = "SnapshotQuotaExceeded"
+
+	// MemoryDumpFailedErrorReason is the Error reason when the guest memory
+	// of a snapshot including memory could not be dumped
+	MemoryDumpFailedErrorReason = "MemoryDumpFailed"
+)
+
 // ConditionType is the const type for Conditions
 type ConditionType string
 
@@ -175,6 +379,18 @@ const (
 
 	// ConditionFailure is the "failure" condition type
 	ConditionFailure ConditionType = "Failure"
+
+	// ConditionDeadlineExtended is the "deadline extended" condition type
+	ConditionDeadlineExtended ConditionType = "DeadlineExtended"
+
+	// ConditionAccessCredentialsRequired is the "access credentials required" condition type
+	ConditionAccessCredentialsRequired ConditionType = "AccessCredentialsRequired"
+
+	// ConditionPlacementUnschedulable is the "placement unschedulable" condition type
+	ConditionPlacementUnschedulable ConditionType = "PlacementUnschedulable"
+
+	// ConditionExcludedVolumesMissing is the "excluded volumes missing" condition type
+	ConditionExcludedVolumesMissing ConditionType = "ExcludedVolumesMissing"
 )
 
 // Condition defines conditions
@@ -229,6 +445,10 @@ type VirtualMachineSnapshotContentSpec struct {
 	// +optional
 	// +listType=atomic
 	VolumeBackups []VolumeBackup `json:"volumeBackups,omitempty"`
+
+	// MemoryDumpBackup tracks the guest memory dump captured with the volumes
+	// +optional
+	MemoryDumpBackup *MemoryDumpBackup `json:"memoryDumpBackup,omitempty"`
 }
 
 type VirtualMachine struct {
@@ -269,6 +489,26 @@ type VolumeBackup struct {
 
 	// +optional
 	VolumeSnapshotName *string `json:"volumeSnapshotName,omitempty"`
+
+	// Hotplug indicates the volume was hotplugged to the running VMI
+	// and is not part of the VM spec template
+	// +optional
+	Hotplug bool `json:"hotplug,omitempty"`
+
+	// Disk is the disk definition of a hotplugged volume, so that the
+	// restored volume is hotplugged with the same bus and settings
+	// +optional
+	Disk *v1.Disk `json:"disk,omitempty"`
+}
+
+// MemoryDumpBackup identifies the volume backup holding the guest memory dump
+type MemoryDumpBackup struct {
+	// VolumeName is the name of the memory dump volume in the volume backups
+	VolumeName string `json:"volumeName"`
+
+	// FileName is the name of the memory dump file on the volume
+	// +optional
+	FileName *string `json:"fileName,omitempty"`
 }
 
 // VirtualMachineSnapshotContentStatus is the status for a VirtualMachineSnapshotStatus resource
@@ -310,6 +550,11 @@ type VolumeSnapshotStatus struct {
 
 	// +optional
 	Error *Error `json:"error,omitempty"`
+
+	// Duration is how long the volume snapshot took to become ready to
+	// use after it was created
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
 }
 
 // VirtualMachineRestore defines the operation of restoring a VM
@@ -374,12 +619,103 @@ const (
 	VolumeOwnershipPolicyNone VolumeOwnershipPolicy = "None"
 )
 
+// VolumeProvisioning hints how a restored volume should be provisioned
+type VolumeProvisioning string
+
+const (
+	// VolumeProvisioningThin requests a thin provisioned restored volume
+	VolumeProvisioningThin VolumeProvisioning = "Thin"
+
+	// VolumeProvisioningThick requests a thick provisioned restored volume
+	VolumeProvisioningThick VolumeProvisioning = "Thick"
+)
+
+// MetadataRestorePolicyType defines which VirtualMachine labels and annotations captured
+// in the snapshot are applied to the restored VirtualMachine
+type MetadataRestorePolicyType string
+
+const (
+	// MetadataRestorePolicyAll restores all captured labels and annotations
+	MetadataRestorePolicyAll MetadataRestorePolicyType = "All"
+
+	// MetadataRestorePolicyNone restores none of the captured labels and annotations
+	MetadataRestorePolicyNone MetadataRestorePolicyType = "None"
+
+	// MetadataRestorePolicySelective restores only the captured labels and annotations
+	// whose keys are listed in the policy
+	MetadataRestorePolicySelective MetadataRestorePolicyType = "Selective"
+)
+
+// MetadataRestorePolicy controls which labels and annotations of the snapshotted
+// VirtualMachine are applied to the restored VirtualMachine. KubeVirt managed
+// annotations (kubevirt.io/latest-observed-api-version,
+// kubevirt.io/storage-observed-api-version and restore.kubevirt.io/lastRestoreUID)
+// are always stripped.
+type MetadataRestorePolicy struct {
+	Type MetadataRestorePolicyType `json:"type"`
+
+	// LabelKeys lists the label keys to restore when Type is Selective
+	// +optional
+	// +listType=set
+	LabelKeys []string `json:"labelKeys,omitempty"`
+
+	// AnnotationKeys lists the annotation keys to restore when Type is Selective
+	// +optional
+	// +listType=set
+	AnnotationKeys []string `json:"annotationKeys,omitempty"`
+}
+
+// ResourceRemappingKind is the kind of resource a ResourceRemapping applies to
+type ResourceRemappingKind string
+
+const (
+	// ResourceRemappingSecret remaps a referenced Secret
+	ResourceRemappingSecret ResourceRemappingKind = "Secret"
+
+	// ResourceRemappingConfigMap remaps a referenced ConfigMap
+	ResourceRemappingConfigMap ResourceRemappingKind = "ConfigMap"
+)
+
+// ResourceRemapping replaces the name of a Secret or ConfigMap referenced by the
+// snapshotted VirtualMachine in the restored VirtualMachine
+type ResourceRemapping struct {
+	Kind ResourceRemappingKind `json:"kind"`
+
+	// SourceName is the name referenced by the snapshotted VirtualMachine
+	SourceName string `json:"sourceName"`
+
+	// TargetName is the name referenced by the restored VirtualMachine
+	TargetName string `json:"targetName"`
+}
+
+// PlacementPolicy defines what happens to the placement constraints of the
+// snapshotted VirtualMachine on restore
+type PlacementPolicy string
+
+const (
+	// PlacementPolicyPreserve keeps the nodeSelector, affinity and tolerations
+	PlacementPolicyPreserve PlacementPolicy = "Preserve"
+
+	// PlacementPolicyClear removes the nodeSelector, affinity and tolerations
+	// so the scheduler places the restored VirtualMachine freely
+	PlacementPolicyClear PlacementPolicy = "Clear"
+)
+
 // VirtualMachineRestoreSpec is the spec for a VirtualMachineRestore resource
 type VirtualMachineRestoreSpec struct {
 	// initially only VirtualMachine type supported
 	Target corev1.TypedLocalObjectReference `json:"target"`
 
-	VirtualMachineSnapshotName string `json:"virtualMachineSnapshotName"`
+	// VirtualMachineSnapshotName is the VirtualMachineSnapshot to restore.
+	// Exactly one of it and VirtualMachineSnapshotContentName must be set
+	// +optional
+	VirtualMachineSnapshotName string `json:"virtualMachineSnapshotName,omitempty"`
+
+	// VirtualMachineSnapshotContentName restores directly from a ready
+	// VirtualMachineSnapshotContent, for example one replicated from another
+	// cluster, without requiring its VirtualMachineSnapshot to exist
+	// +optional
+	VirtualMachineSnapshotContentName *string `json:"virtualMachineSnapshotContentName,omitempty"`
 
 	// +optional
 	TargetReadinessPolicy *TargetReadinessPolicy `json:"targetReadinessPolicy,omitempty"`
@@ -387,6 +723,11 @@ type VirtualMachineRestoreSpec struct {
 	// +optional
 	VolumeRestorePolicy *VolumeRestorePolicy `json:"volumeRestorePolicy,omitempty"`
 
+	// AutoSnapshotBeforeInPlaceRestore takes a VirtualMachineSnapshot of the existing
+	// target before an InPlace restore replaces its volumes, as a rollback point
+	// +optional
+	AutoSnapshotBeforeInPlaceRestore *bool `json:"autoSnapshotBeforeInPlaceRestore,omitempty"`
+
 	// +optional
 	VolumeOwnershipPolicy *VolumeOwnershipPolicy `json:"volumeOwnershipPolicy,omitempty"`
 
@@ -396,6 +737,57 @@ type VirtualMachineRestoreSpec struct {
 	// +listType=atomic
 	VolumeRestoreOverrides []VolumeRestoreOverride `json:"volumeRestoreOverrides,omitempty"`
 
+	// IncludeVolumes restricts the restore to the listed volumes of the snapshot.
+	// A volume which is not restored keeps its current source on an existing target,
+	// and is left out of a newly created target together with its disk.
+	// Mutually exclusive with ExcludeVolumes
+	// +optional
+	// +listType=set
+	IncludeVolumes []string `json:"includeVolumes,omitempty"`
+
+	// ExcludeVolumes lists the volumes of the snapshot which are not restored, they
+	// are handled like the volumes missing from IncludeVolumes.
+	// Mutually exclusive with IncludeVolumes
+	// +optional
+	// +listType=set
+	ExcludeVolumes []string `json:"excludeVolumes,omitempty"`
+
+	// MetadataRestorePolicy controls which of the snapshotted VirtualMachine labels and
+	// annotations are applied to the target. When unset, a newly created target gets all
+	// of them and an existing target keeps its own.
+	// +optional
+	MetadataRestorePolicy *MetadataRestorePolicy `json:"metadataRestorePolicy,omitempty"`
+
+	// PlacementPolicy controls whether the restored VirtualMachine keeps the
+	// nodeSelector, affinity and tolerations captured in the snapshot.
+	// Defaults to Preserve
+	// +optional
+	PlacementPolicy *PlacementPolicy `json:"placementPolicy,omitempty"`
+
+	// ResourceRemapping maps Secrets and ConfigMaps referenced by the snapshotted
+	// VirtualMachine to the ones the restored VirtualMachine should reference.
+	// Only the existence of ConfigMap targets is verified on admission.
+	// +optional
+	// +listType=atomic
+	ResourceRemapping []ResourceRemapping `json:"resourceRemapping,omitempty"`
+
+	// PostRestoreCloudInit is cloud-init user data which is added as a MIME multipart
+	// part to the user data of the cloud-init volume of the restored VirtualMachine,
+	// keeping its original user data, or as a NoCloud volume when it has none. It is
+	// applied before Patches. The restored VirtualMachine gets a firmware UUID derived
+	// from its name, which cloud-init uses as instance ID, so the restore must target a
+	// new name. User data from a Secret can't be merged and fails the restore.
+	// +optional
+	PostRestoreCloudInit *string `json:"postRestoreCloudInit,omitempty"`
+
+	// TargetNamespace is the namespace the restored VirtualMachine and its volumes
+	// are created in. Defaults to the namespace of the restore, which the snapshot
+	// is always read from. Restoring volumes into another namespace requires the
+	// CrossNamespaceVolumeDataSource feature and a ReferenceGrant allowing PVCs of
+	// the target namespace to reference VolumeSnapshots of the restore namespace.
+	// +optional
+	TargetNamespace *string `json:"targetNamespace,omitempty"`
+
 	// If the target for the restore does not exist, it will be created. Patches holds JSON patches that would be
 	// applied to the target manifest before it's created. Patches should fit the target's Kind.
 	//
@@ -422,11 +814,37 @@ type VirtualMachineRestoreStatus struct {
 	// +optional
 	Complete *bool `json:"complete,omitempty"`
 
+	// Progress is the percentage of the restored volumes which are ready, weighted
+	// by their capacity, or by their count when a capacity is unknown. It only
+	// reaches 100 when the restore is complete.
+	// +optional
+	Progress *int32 `json:"progress,omitempty"`
+
+	// PreRestoreSnapshotName is the VirtualMachineSnapshot taken of the target
+	// before the restore, when AutoSnapshotBeforeInPlaceRestore is set
+	// +optional
+	PreRestoreSnapshotName *string `json:"preRestoreSnapshotName,omitempty"`
+
+	// MemoryDump is where the guest memory dump captured by the snapshot is
+	// kept. The dump is not restored, the restored VM boots from its volumes
+	// +optional
+	MemoryDump *MemoryDumpLocation `json:"memoryDump,omitempty"`
+
 	// +optional
 	// +listType=atomic
 	Conditions []Condition `json:"conditions,omitempty"`
 }
 
+// MemoryDumpLocation is where the guest memory dump of a snapshot is kept
+type MemoryDumpLocation struct {
+	// VolumeSnapshotName is the VolumeSnapshot holding the memory dump
+	VolumeSnapshotName string `json:"volumeSnapshotName"`
+
+	// FileName is the name of the memory dump file on the volume
+	// +optional
+	FileName *string `json:"fileName,omitempty"`
+}
+
 // VolumeRestore contains the data needed to restore a PVC
 type VolumeRestore struct {
 	VolumeName string `json:"volumeName"`
@@ -448,6 +866,20 @@ type VolumeRestoreOverride struct {
 	Labels map[string]string `json:"labels,omitempty"`
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// Provisioning hints whether the restored volume should be thin or thick
+	// provisioned. It is passed to the provisioner as a PVC annotation and is
+	// only honored by storage classes which declare support for it
+	// +optional
+	Provisioning *VolumeProvisioning `json:"provisioning,omitempty"`
+	// StorageClassName is the storage class the volume is restored to, instead
+	// of the storage class of the snapshotted volume. The restore fails if the
+	// storage class does not exist
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// AccessModes replaces the access modes of the snapshotted volume
+	// +optional
+	// +listType=atomic
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
 }
 
 // VirtualMachineRestoreList is a list of VirtualMachineRestore resources
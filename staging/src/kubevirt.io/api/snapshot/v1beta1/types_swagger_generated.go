@@ -11,9 +11,39 @@ func (VirtualMachineSnapshot) SwaggerDoc() map[string]string {
 
 func (VirtualMachineSnapshotSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                "VirtualMachineSnapshotSpec is the spec for a VirtualMachineSnapshot resource",
-		"deletionPolicy":  "+optional",
-		"failureDeadline": "This time represents the number of seconds we permit the vm snapshot\nto take. In case we pass this deadline we mark this snapshot\nas failed.\nDefaults to DefaultFailureDeadline - 5min\n+optional",
+		"":                              "VirtualMachineSnapshotSpec is the spec for a VirtualMachineSnapshot resource",
+		"deletionPolicy":                "+optional",
+		"failureDeadline":               "This time represents the number of seconds we permit the vm snapshot\nto take. In case we pass this deadline we mark this snapshot\nas failed.\nDefaults to DefaultFailureDeadline - 5min\n+optional",
+		"deadlineGracePeriod":           "DeadlineGracePeriod extends the FailureDeadline once by the given\nduration, if the volume snapshots are still progressing when the\ndeadline is reached.\n+optional",
+		"pauseDuringSnapshot":           "PauseDuringSnapshot pauses a running VM while its volumes are\ncaptured and unpauses it afterwards, instead of freezing the\nguest filesystems.\n+optional",
+		"includeEphemeralVolumes":       "IncludeEphemeralVolumes also captures the PVCs backing ephemeral\nvolumes, which are otherwise listed as excluded volumes. Only the\nread-only backing PVC is captured, not the guest writes.\n+optional",
+		"deletionTimeout":               "DeletionTimeout is how long the deletion of the snapshot may wait for\nits content and volume snapshots before the pending ones are reported.\nDefaults to 5min\n+optional",
+		"forceDelete":                   "ForceDelete removes the snapshot finalizer once its deletion has been\nstuck for too long, even if the content or its volume snapshots are\nstill being deleted.\nUnlike the rest of the spec it can be set after creation.\n+optional",
+		"ttlAfterSuccess":               "TTLAfterSuccess is how long a succeeded snapshot is kept after its\ncreation time before it is deleted, honoring its DeletionPolicy.\nUnset or zero keeps the snapshot until it is deleted explicitly.\n+optional",
+		"excludeUnsnapshottableVolumes": "ExcludeUnsnapshottableVolumes controls what happens to PVC volumes\nwhose storage class has no VolumeSnapshotClass. When true, the\ndefault, they are skipped and listed as excluded volumes. When false,\nthe snapshot fails with the NoVolumeSnapshotClass error reason.\n+optional",
+		"description":                   "Description is a free form note on why the snapshot was taken, for\nexample before an upgrade. It is echoed into the status.\n+optional",
+		"snapshotType":                  "SnapshotType selects what the snapshot captures. DefinitionOnly\ncaptures the VM definition without any volume backups and is ready\nas soon as its content is created.\nDefaults to Full\n+optional",
+		"excludedVolumes":               "ExcludedVolumes lists the names of VM volumes which are not captured.\nThey are listed as excluded volumes, and names the VM does not have\nare reported with the ExcludedVolumesMissing condition.\n+optional\n+listType=atomic",
+		"includeMemoryDump":             "IncludeMemoryDump dumps the guest memory of a running VM into a PVC\nbefore its volumes are frozen, and captures the dump with them.\nThe dump is not loaded on restore, the restore reports where it\nis kept.\n+optional",
+		"hooks":                         "Hooks are commands run inside the guest through the guest agent\naround the filesystem freeze of a running VM. They are passed to the\nfreeze and unfreeze of the VM, so they require a user allowed to\nfreeze it.\n+optional",
+	}
+}
+
+func (SnapshotHooks) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "SnapshotHooks are the commands run around the guest filesystem freeze",
+		"preFreeze": "PreFreeze hooks run in order before the guest filesystems are frozen.\nIf one fails, the guest is not frozen and the freeze is retried from\nthe first hook.\n+optional\n+listType=atomic",
+		"postThaw":  "PostThaw hooks run in order once the guest filesystems are thawed,\nor once the snapshot is done without freezing them\n+optional\n+listType=atomic",
+	}
+}
+
+func (SnapshotHook) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "SnapshotHook is a command run inside the guest",
+		"name":    "Name identifies the hook in failure messages",
+		"command": "Command is the path of the executable run inside the guest",
+		"args":    "Args are the arguments passed to the command\n+optional\n+listType=atomic",
+		"timeout": "Timeout is the time the command is given to complete.\nDefaults to 30s, at most 2m\n+optional",
 	}
 }
 
@@ -36,8 +66,12 @@ func (VirtualMachineSnapshotStatus) SwaggerDoc() map[string]string {
 		"error":                             "+optional",
 		"conditions":                        "+optional\n+listType=atomic",
 		"indications":                       "Deprecated: Use SourceIndications instead. This field will be removed in a future version.\n+optional\n+listType=set",
-		"sourceIndications":                 "+optional\n+listType=atomic",
+		"sourceIndications":                 "SourceIndications describe the state of the source VM when the\nsnapshot was taken. When both are set they take precedence over the\ndeprecated Indications, which are kept in sync with them.\n+optional\n+listType=atomic",
+		"consistencyLevel":                  "ConsistencyLevel classifies the guest data consistency of the snapshot,\nderived from the source indications once it succeeded\n+optional",
 		"snapshotVolumes":                   "+optional",
+		"description":                       "Description is the description given in the spec\n+optional",
+		"retryCount":                        "RetryCount is the number of times the controller retried a failed\nstep of the snapshot, such as freezing the guest\n+optional",
+		"nextRetryTime":                     "NextRetryTime is when the controller retries the failed step next\n+optional\n+nullable",
 	}
 }
 
@@ -46,6 +80,7 @@ func (SnapshotVolumesLists) SwaggerDoc() map[string]string {
 		"":                "SnapshotVolumesLists includes the list of volumes which were included in the snapshot and volumes which were excluded from the snapshot",
 		"includedVolumes": "+optional\n+listType=set",
 		"excludedVolumes": "+optional\n+listType=set",
+		"hotplugVolumes":  "HotplugVolumes lists the included volumes which were hotplugged\nto the running VMI rather than being part of the VM spec\n+optional\n+listType=set",
 	}
 }
 
@@ -54,6 +89,7 @@ func (Error) SwaggerDoc() map[string]string {
 		"":        "Error is the last error encountered during the snapshot/restore",
 		"time":    "+optional",
 		"message": "+optional",
+		"reason":  "Reason is a machine readable cause of the error, set for errors which\nthe controller detects itself\n+optional",
 	}
 }
 
@@ -82,8 +118,9 @@ func (VirtualMachineSnapshotContent) SwaggerDoc() map[string]string {
 
 func (VirtualMachineSnapshotContentSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":              "VirtualMachineSnapshotContentSpec is the spec for a VirtualMachineSnapshotContent resource",
-		"volumeBackups": "+optional\n+listType=atomic",
+		"":                 "VirtualMachineSnapshotContentSpec is the spec for a VirtualMachineSnapshotContent resource",
+		"volumeBackups":    "+optional\n+listType=atomic",
+		"memoryDumpBackup": "MemoryDumpBackup tracks the guest memory dump captured with the volumes\n+optional",
 	}
 }
 
@@ -111,6 +148,16 @@ func (VolumeBackup) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                   "VolumeBackup contains the data neeed to restore a PVC",
 		"volumeSnapshotName": "+optional",
+		"hotplug":            "Hotplug indicates the volume was hotplugged to the running VMI\nand is not part of the VM spec template\n+optional",
+		"disk":               "Disk is the disk definition of a hotplugged volume, so that the\nrestored volume is hotplugged with the same bus and settings\n+optional",
+	}
+}
+
+func (MemoryDumpBackup) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "MemoryDumpBackup identifies the volume backup holding the guest memory dump",
+		"volumeName": "VolumeName is the name of the memory dump volume in the volume backups",
+		"fileName":   "FileName is the name of the memory dump file on the volume\n+optional",
 	}
 }
 
@@ -136,6 +183,7 @@ func (VolumeSnapshotStatus) SwaggerDoc() map[string]string {
 		"creationTime": "+optional\n+nullable",
 		"readyToUse":   "+optional",
 		"error":        "+optional",
+		"duration":     "Duration is how long the volume snapshot took to become ready to\nuse after it was created\n+optional",
 	}
 }
 
@@ -146,26 +194,63 @@ func (VirtualMachineRestore) SwaggerDoc() map[string]string {
 	}
 }
 
+func (MetadataRestorePolicy) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "MetadataRestorePolicy controls which labels and annotations of the snapshotted\nVirtualMachine are applied to the restored VirtualMachine. KubeVirt managed\nannotations (kubevirt.io/latest-observed-api-version,\nkubevirt.io/storage-observed-api-version and restore.kubevirt.io/lastRestoreUID)\nare always stripped.",
+		"labelKeys":      "LabelKeys lists the label keys to restore when Type is Selective\n+optional\n+listType=set",
+		"annotationKeys": "AnnotationKeys lists the annotation keys to restore when Type is Selective\n+optional\n+listType=set",
+	}
+}
+
+func (ResourceRemapping) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "ResourceRemapping replaces the name of a Secret or ConfigMap referenced by the\nsnapshotted VirtualMachine in the restored VirtualMachine",
+		"sourceName": "SourceName is the name referenced by the snapshotted VirtualMachine",
+		"targetName": "TargetName is the name referenced by the restored VirtualMachine",
+	}
+}
+
 func (VirtualMachineRestoreSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                       "VirtualMachineRestoreSpec is the spec for a VirtualMachineRestore resource",
-		"target":                 "initially only VirtualMachine type supported",
-		"targetReadinessPolicy":  "+optional",
-		"volumeRestorePolicy":    "+optional",
-		"volumeOwnershipPolicy":  "+optional",
-		"volumeRestoreOverrides": "VolumeRestoreOverrides gives the option to change properties of each restored volume\nFor example, specifying the name of the restored volume, or adding labels/annotations to it\n+optional\n+listType=atomic",
-		"patches":                "If the target for the restore does not exist, it will be created. Patches holds JSON patches that would be\napplied to the target manifest before it's created. Patches should fit the target's Kind.\n\nExample for a patch: {\"op\": \"replace\", \"path\": \"/metadata/name\", \"value\": \"new-vm-name\"}\n\n+optional\n+listType=atomic",
+		"":                                  "VirtualMachineRestoreSpec is the spec for a VirtualMachineRestore resource",
+		"target":                            "initially only VirtualMachine type supported",
+		"virtualMachineSnapshotName":        "VirtualMachineSnapshotName is the VirtualMachineSnapshot to restore.\nExactly one of it and VirtualMachineSnapshotContentName must be set\n+optional",
+		"virtualMachineSnapshotContentName": "VirtualMachineSnapshotContentName restores directly from a ready\nVirtualMachineSnapshotContent, for example one replicated from another\ncluster, without requiring its VirtualMachineSnapshot to exist\n+optional",
+		"targetReadinessPolicy":             "+optional",
+		"volumeRestorePolicy":               "+optional",
+		"autoSnapshotBeforeInPlaceRestore":  "AutoSnapshotBeforeInPlaceRestore takes a VirtualMachineSnapshot of the existing\ntarget before an InPlace restore replaces its volumes, as a rollback point\n+optional",
+		"volumeOwnershipPolicy":             "+optional",
+		"volumeRestoreOverrides":            "VolumeRestoreOverrides gives the option to change properties of each restored volume\nFor example, specifying the name of the restored volume, or adding labels/annotations to it\n+optional\n+listType=atomic",
+		"includeVolumes":                    "IncludeVolumes restricts the restore to the listed volumes of the snapshot.\nA volume which is not restored keeps its current source on an existing target,\nand is left out of a newly created target together with its disk.\nMutually exclusive with ExcludeVolumes\n+optional\n+listType=set",
+		"excludeVolumes":                    "ExcludeVolumes lists the volumes of the snapshot which are not restored, they\nare handled like the volumes missing from IncludeVolumes.\nMutually exclusive with IncludeVolumes\n+optional\n+listType=set",
+		"metadataRestorePolicy":             "MetadataRestorePolicy controls which of the snapshotted VirtualMachine labels and\nannotations are applied to the target. When unset, a newly created target gets all\nof them and an existing target keeps its own.\n+optional",
+		"placementPolicy":                   "PlacementPolicy controls whether the restored VirtualMachine keeps the\nnodeSelector, affinity and tolerations captured in the snapshot.\nDefaults to Preserve\n+optional",
+		"resourceRemapping":                 "ResourceRemapping maps Secrets and ConfigMaps referenced by the snapshotted\nVirtualMachine to the ones the restored VirtualMachine should reference.\nOnly the existence of ConfigMap targets is verified on admission.\n+optional\n+listType=atomic",
+		"postRestoreCloudInit":              "PostRestoreCloudInit is cloud-init user data which is added as a MIME multipart\npart to the user data of the cloud-init volume of the restored VirtualMachine,\nkeeping its original user data, or as a NoCloud volume when it has none. It is\napplied before Patches. The restored VirtualMachine gets a firmware UUID derived\nfrom its name, which cloud-init uses as instance ID, so the restore must target a\nnew name. User data from a Secret can't be merged and fails the restore.\n+optional",
+		"targetNamespace":                   "TargetNamespace is the namespace the restored VirtualMachine and its volumes\nare created in. Defaults to the namespace of the restore, which the snapshot\nis always read from. Restoring volumes into another namespace requires the\nCrossNamespaceVolumeDataSource feature and a ReferenceGrant allowing PVCs of\nthe target namespace to reference VolumeSnapshots of the restore namespace.\n+optional",
+		"patches":                           "If the target for the restore does not exist, it will be created. Patches holds JSON patches that would be\napplied to the target manifest before it's created. Patches should fit the target's Kind.\n\nExample for a patch: {\"op\": \"replace\", \"path\": \"/metadata/name\", \"value\": \"new-vm-name\"}\n\n+optional\n+listType=atomic",
 	}
 }
 
 func (VirtualMachineRestoreStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                   "VirtualMachineRestoreStatus is the status for a VirtualMachineRestore resource",
-		"restores":           "+optional\n+listType=atomic",
-		"restoreTime":        "+optional",
-		"deletedDataVolumes": "+optional\n+listType=set",
-		"complete":           "+optional",
-		"conditions":         "+optional\n+listType=atomic",
+		"":                       "VirtualMachineRestoreStatus is the status for a VirtualMachineRestore resource",
+		"restores":               "+optional\n+listType=atomic",
+		"restoreTime":            "+optional",
+		"deletedDataVolumes":     "+optional\n+listType=set",
+		"complete":               "+optional",
+		"progress":               "Progress is the percentage of the restored volumes which are ready, weighted\nby their capacity, or by their count when a capacity is unknown. It only\nreaches 100 when the restore is complete.\n+optional",
+		"preRestoreSnapshotName": "PreRestoreSnapshotName is the VirtualMachineSnapshot taken of the target\nbefore the restore, when AutoSnapshotBeforeInPlaceRestore is set\n+optional",
+		"memoryDump":             "MemoryDump is where the guest memory dump captured by the snapshot is\nkept. The dump is not restored, the restored VM boots from its volumes\n+optional",
+		"conditions":             "+optional\n+listType=atomic",
+	}
+}
+
+func (MemoryDumpLocation) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "MemoryDumpLocation is where the guest memory dump of a snapshot is kept",
+		"volumeSnapshotName": "VolumeSnapshotName is the VolumeSnapshot holding the memory dump",
+		"fileName":           "FileName is the name of the memory dump file on the volume\n+optional",
 	}
 }
 
@@ -178,10 +263,13 @@ func (VolumeRestore) SwaggerDoc() map[string]string {
 
 func (VolumeRestoreOverride) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":            "VolumeRestoreOverride specifies how a volume should be restored from a VirtualMachineSnapshot",
-		"restoreName": "+optional",
-		"labels":      "+optional",
-		"annotations": "+optional",
+		"":                 "VolumeRestoreOverride specifies how a volume should be restored from a VirtualMachineSnapshot",
+		"restoreName":      "+optional",
+		"labels":           "+optional",
+		"annotations":      "+optional",
+		"provisioning":     "Provisioning hints whether the restored volume should be thin or thick\nprovisioned. It is passed to the provisioner as a PVC annotation and is\nonly honored by storage classes which declare support for it\n+optional",
+		"storageClassName": "StorageClassName is the storage class the volume is restored to, instead\nof the storage class of the snapshotted volume. The restore fails if the\nstorage class does not exist\n+optional",
+		"accessModes":      "AccessModes replaces the access modes of the snapshotted volume\n+optional\n+listType=atomic",
 	}
 }
 
@@ -423,6 +423,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/api/core/v1.FilesystemVirtiofs":                                                      schema_kubevirtio_api_core_v1_FilesystemVirtiofs(ref),
 		"kubevirt.io/api/core/v1.Firmware":                                                                schema_kubevirtio_api_core_v1_Firmware(ref),
 		"kubevirt.io/api/core/v1.Flags":                                                                   schema_kubevirtio_api_core_v1_Flags(ref),
+		"kubevirt.io/api/core/v1.FreezeHook":                                                              schema_kubevirtio_api_core_v1_FreezeHook(ref),
 		"kubevirt.io/api/core/v1.FreezeUnfreezeTimeout":                                                   schema_kubevirtio_api_core_v1_FreezeUnfreezeTimeout(ref),
 		"kubevirt.io/api/core/v1.GPU":                                                                     schema_kubevirtio_api_core_v1_GPU(ref),
 		"kubevirt.io/api/core/v1.GenerationStatus":                                                        schema_kubevirtio_api_core_v1_GenerationStatus(ref),
@@ -544,6 +545,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/api/core/v1.TopologyHints":                                                           schema_kubevirtio_api_core_v1_TopologyHints(ref),
 		"kubevirt.io/api/core/v1.USBHostDevice":                                                           schema_kubevirtio_api_core_v1_USBHostDevice(ref),
 		"kubevirt.io/api/core/v1.USBSelector":                                                             schema_kubevirtio_api_core_v1_USBSelector(ref),
+		"kubevirt.io/api/core/v1.UnfreezeOptions":                                                         schema_kubevirtio_api_core_v1_UnfreezeOptions(ref),
 		"kubevirt.io/api/core/v1.UnpauseOptions":                                                          schema_kubevirtio_api_core_v1_UnpauseOptions(ref),
 		"kubevirt.io/api/core/v1.UserPasswordAccessCredential":                                            schema_kubevirtio_api_core_v1_UserPasswordAccessCredential(ref),
 		"kubevirt.io/api/core/v1.UserPasswordAccessCredentialPropagationMethod":                           schema_kubevirtio_api_core_v1_UserPasswordAccessCredentialPropagationMethod(ref),
@@ -716,7 +718,13 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/api/snapshot/v1alpha1.VolumeSnapshotStatus":                                          schema_kubevirtio_api_snapshot_v1alpha1_VolumeSnapshotStatus(ref),
 		"kubevirt.io/api/snapshot/v1beta1.Condition":                                                      schema_kubevirtio_api_snapshot_v1beta1_Condition(ref),
 		"kubevirt.io/api/snapshot/v1beta1.Error":                                                          schema_kubevirtio_api_snapshot_v1beta1_Error(ref),
+		"kubevirt.io/api/snapshot/v1beta1.MemoryDumpBackup":                                               schema_kubevirtio_api_snapshot_v1beta1_MemoryDumpBackup(ref),
+		"kubevirt.io/api/snapshot/v1beta1.MemoryDumpLocation":                                             schema_kubevirtio_api_snapshot_v1beta1_MemoryDumpLocation(ref),
+		"kubevirt.io/api/snapshot/v1beta1.MetadataRestorePolicy":                                          schema_kubevirtio_api_snapshot_v1beta1_MetadataRestorePolicy(ref),
 		"kubevirt.io/api/snapshot/v1beta1.PersistentVolumeClaim":                                          schema_kubevirtio_api_snapshot_v1beta1_PersistentVolumeClaim(ref),
+		"kubevirt.io/api/snapshot/v1beta1.ResourceRemapping":                                              schema_kubevirtio_api_snapshot_v1beta1_ResourceRemapping(ref),
+		"kubevirt.io/api/snapshot/v1beta1.SnapshotHook":                                                   schema_kubevirtio_api_snapshot_v1beta1_SnapshotHook(ref),
+		"kubevirt.io/api/snapshot/v1beta1.SnapshotHooks":                                                  schema_kubevirtio_api_snapshot_v1beta1_SnapshotHooks(ref),
 		"kubevirt.io/api/snapshot/v1beta1.SnapshotVolumesLists":                                           schema_kubevirtio_api_snapshot_v1beta1_SnapshotVolumesLists(ref),
 		"kubevirt.io/api/snapshot/v1beta1.SourceIndication":                                               schema_kubevirtio_api_snapshot_v1beta1_SourceIndication(ref),
 		"kubevirt.io/api/snapshot/v1beta1.SourceSpec":                                                     schema_kubevirtio_api_snapshot_v1beta1_SourceSpec(ref),
@@ -21183,6 +21191,63 @@ func schema_kubevirtio_api_core_v1_Flags(ref common.ReferenceCallback) common.Op
 	}
 }
 
+func schema_kubevirtio_api_core_v1_FreezeHook(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FreezeHook is a command run inside the guest through the guest agent around the filesystem freeze",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the hook in failure messages",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"command": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Command is the path of the executable run inside the guest",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"args": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Args are the arguments passed to the command",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeoutSeconds is the time the command is given to complete",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"name", "command"},
+			},
+		},
+	}
+}
+
 func schema_kubevirtio_api_core_v1_FreezeUnfreezeTimeout(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -21195,12 +21260,31 @@ func schema_kubevirtio_api_core_v1_FreezeUnfreezeTimeout(ref common.ReferenceCal
 							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
+					"hooks": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Hooks run in order inside the guest before the filesystems are frozen. The guest is not frozen when one of them fails.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/api/core/v1.FreezeHook"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"unfreezeTimeout"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kubevirt.io/api/core/v1.FreezeHook"},
 	}
 }
 
@@ -25942,6 +26026,40 @@ func schema_kubevirtio_api_core_v1_USBSelector(ref common.ReferenceCallback) com
 	}
 }
 
+func schema_kubevirtio_api_core_v1_UnfreezeOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnfreezeOptions may be provided on unfreeze request.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"hooks": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Hooks run in order inside the guest once the filesystems are thawed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/api/core/v1.FreezeHook"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/api/core/v1.FreezeHook"},
+	}
+}
+
 func schema_kubevirtio_api_core_v1_UnpauseOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -34313,6 +34431,13 @@ func schema_kubevirtio_api_snapshot_v1beta1_Error(ref common.ReferenceCallback)
 							Format: "",
 						},
 					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a machine readable cause of the error, set for errors which the controller detects itself",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -34321,6 +34446,125 @@ func schema_kubevirtio_api_snapshot_v1beta1_Error(ref common.ReferenceCallback)
 	}
 }
 
+func schema_kubevirtio_api_snapshot_v1beta1_MemoryDumpBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MemoryDumpBackup identifies the volume backup holding the guest memory dump",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"volumeName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeName is the name of the memory dump volume in the volume backups",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fileName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FileName is the name of the memory dump file on the volume",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"volumeName"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_api_snapshot_v1beta1_MemoryDumpLocation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MemoryDumpLocation is where the guest memory dump of a snapshot is kept",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"volumeSnapshotName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeSnapshotName is the VolumeSnapshot holding the memory dump",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fileName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FileName is the name of the memory dump file on the volume",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"volumeSnapshotName"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_api_snapshot_v1beta1_MetadataRestorePolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MetadataRestorePolicy controls which labels and annotations of the snapshotted VirtualMachine are applied to the restored VirtualMachine. KubeVirt managed annotations (kubevirt.io/latest-observed-api-version, kubevirt.io/storage-observed-api-version and restore.kubevirt.io/lastRestoreUID) are always stripped.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"labelKeys": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "LabelKeys lists the label keys to restore when Type is Selective",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"annotationKeys": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "AnnotationKeys lists the annotation keys to restore when Type is Selective",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"type"},
+			},
+		},
+	}
+}
+
 func schema_kubevirtio_api_snapshot_v1beta1_PersistentVolumeClaim(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -34349,6 +34593,154 @@ func schema_kubevirtio_api_snapshot_v1beta1_PersistentVolumeClaim(ref common.Ref
 	}
 }
 
+func schema_kubevirtio_api_snapshot_v1beta1_ResourceRemapping(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ResourceRemapping replaces the name of a Secret or ConfigMap referenced by the snapshotted VirtualMachine in the restored VirtualMachine",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"sourceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SourceName is the name referenced by the snapshotted VirtualMachine",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targetName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TargetName is the name referenced by the restored VirtualMachine",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"kind", "sourceName", "targetName"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_api_snapshot_v1beta1_SnapshotHook(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SnapshotHook is a command run inside the guest",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the hook in failure messages",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"command": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Command is the path of the executable run inside the guest",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"args": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Args are the arguments passed to the command",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout is the time the command is given to complete. Defaults to 30s, at most 2m",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"name", "command"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_kubevirtio_api_snapshot_v1beta1_SnapshotHooks(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SnapshotHooks are the commands run around the guest filesystem freeze",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"preFreeze": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "PreFreeze hooks run in order before the guest filesystems are frozen. If one fails, the guest is not frozen and the freeze is retried from the first hook.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/api/snapshot/v1beta1.SnapshotHook"),
+									},
+								},
+							},
+						},
+					},
+					"postThaw": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "PostThaw hooks run in order once the guest filesystems are thawed, or once the snapshot is done without freezing them",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/api/snapshot/v1beta1.SnapshotHook"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/api/snapshot/v1beta1.SnapshotHook"},
+	}
+}
+
 func schema_kubevirtio_api_snapshot_v1beta1_SnapshotVolumesLists(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -34394,6 +34786,26 @@ func schema_kubevirtio_api_snapshot_v1beta1_SnapshotVolumesLists(ref common.Refe
 							},
 						},
 					},
+					"hotplugVolumes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "HotplugVolumes lists the included volumes which were hotplugged to the running VMI rather than being part of the VM spec",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -34596,9 +35008,16 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineRestoreSpec(ref common
 					},
 					"virtualMachineSnapshotName": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Description: "VirtualMachineSnapshotName is the VirtualMachineSnapshot to restore. Exactly one of it and VirtualMachineSnapshotContentName must be set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"virtualMachineSnapshotContentName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VirtualMachineSnapshotContentName restores directly from a ready VirtualMachineSnapshotContent, for example one replicated from another cluster, without requiring its VirtualMachineSnapshot to exist",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 					"targetReadinessPolicy": {
@@ -34613,6 +35032,13 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineRestoreSpec(ref common
 							Format: "",
 						},
 					},
+					"autoSnapshotBeforeInPlaceRestore": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutoSnapshotBeforeInPlaceRestore takes a VirtualMachineSnapshot of the existing target before an InPlace restore replaces its volumes, as a rollback point",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"volumeOwnershipPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
@@ -34638,6 +35064,92 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineRestoreSpec(ref common
 							},
 						},
 					},
+					"includeVolumes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "IncludeVolumes restricts the restore to the listed volumes of the snapshot. A volume which is not restored keeps its current source on an existing target, and is left out of a newly created target together with its disk. Mutually exclusive with ExcludeVolumes",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"excludeVolumes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ExcludeVolumes lists the volumes of the snapshot which are not restored, they are handled like the volumes missing from IncludeVolumes. Mutually exclusive with IncludeVolumes",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"metadataRestorePolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetadataRestorePolicy controls which of the snapshotted VirtualMachine labels and annotations are applied to the target. When unset, a newly created target gets all of them and an existing target keeps its own.",
+							Ref:         ref("kubevirt.io/api/snapshot/v1beta1.MetadataRestorePolicy"),
+						},
+					},
+					"placementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlacementPolicy controls whether the restored VirtualMachine keeps the nodeSelector, affinity and tolerations captured in the snapshot. Defaults to Preserve",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resourceRemapping": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceRemapping maps Secrets and ConfigMaps referenced by the snapshotted VirtualMachine to the ones the restored VirtualMachine should reference. Only the existence of ConfigMap targets is verified on admission.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubevirt.io/api/snapshot/v1beta1.ResourceRemapping"),
+									},
+								},
+							},
+						},
+					},
+					"postRestoreCloudInit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PostRestoreCloudInit is cloud-init user data which is added as a MIME multipart part to the user data of the cloud-init volume of the restored VirtualMachine, keeping its original user data, or as a NoCloud volume when it has none. It is applied before Patches. The restored VirtualMachine gets a firmware UUID derived from its name, which cloud-init uses as instance ID, so the restore must target a new name. User data from a Secret can't be merged and fails the restore.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targetNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TargetNamespace is the namespace the restored VirtualMachine and its volumes are created in. Defaults to the namespace of the restore, which the snapshot is always read from. Restoring volumes into another namespace requires the CrossNamespaceVolumeDataSource feature and a ReferenceGrant allowing PVCs of the target namespace to reference VolumeSnapshots of the restore namespace.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"patches": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -34659,11 +35171,11 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineRestoreSpec(ref common
 						},
 					},
 				},
-				Required: []string{"target", "virtualMachineSnapshotName"},
+				Required: []string{"target"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.TypedLocalObjectReference", "kubevirt.io/api/snapshot/v1beta1.VolumeRestoreOverride"},
+			"k8s.io/api/core/v1.TypedLocalObjectReference", "kubevirt.io/api/snapshot/v1beta1.MetadataRestorePolicy", "kubevirt.io/api/snapshot/v1beta1.ResourceRemapping", "kubevirt.io/api/snapshot/v1beta1.VolumeRestoreOverride"},
 	}
 }
 
@@ -34722,6 +35234,26 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineRestoreStatus(ref comm
 							Format: "",
 						},
 					},
+					"progress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Progress is the percentage of the restored volumes which are ready, weighted by their capacity, or by their count when a capacity is unknown. It only reaches 100 when the restore is complete.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"preRestoreSnapshotName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreRestoreSnapshotName is the VirtualMachineSnapshot taken of the target before the restore, when AutoSnapshotBeforeInPlaceRestore is set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"memoryDump": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MemoryDump is where the guest memory dump captured by the snapshot is kept. The dump is not restored, the restored VM boots from its volumes",
+							Ref:         ref("kubevirt.io/api/snapshot/v1beta1.MemoryDumpLocation"),
+						},
+					},
 					"conditions": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -34744,7 +35276,7 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineRestoreStatus(ref comm
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubevirt.io/api/snapshot/v1beta1.Condition", "kubevirt.io/api/snapshot/v1beta1.VolumeRestore"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubevirt.io/api/snapshot/v1beta1.Condition", "kubevirt.io/api/snapshot/v1beta1.MemoryDumpLocation", "kubevirt.io/api/snapshot/v1beta1.VolumeRestore"},
 	}
 }
 
@@ -34928,12 +35460,18 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineSnapshotContentSpec(re
 							},
 						},
 					},
+					"memoryDumpBackup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MemoryDumpBackup tracks the guest memory dump captured with the volumes",
+							Ref:         ref("kubevirt.io/api/snapshot/v1beta1.MemoryDumpBackup"),
+						},
+					},
 				},
 				Required: []string{"source"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/api/snapshot/v1beta1.SourceSpec", "kubevirt.io/api/snapshot/v1beta1.VolumeBackup"},
+			"kubevirt.io/api/snapshot/v1beta1.MemoryDumpBackup", "kubevirt.io/api/snapshot/v1beta1.SourceSpec", "kubevirt.io/api/snapshot/v1beta1.VolumeBackup"},
 	}
 }
 
@@ -35060,12 +35598,105 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineSnapshotSpec(ref commo
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
+					"deadlineGracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeadlineGracePeriod extends the FailureDeadline once by the given duration, if the volume snapshots are still progressing when the deadline is reached.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"pauseDuringSnapshot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PauseDuringSnapshot pauses a running VM while its volumes are captured and unpauses it afterwards, instead of freezing the guest filesystems.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"includeEphemeralVolumes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IncludeEphemeralVolumes also captures the PVCs backing ephemeral volumes, which are otherwise listed as excluded volumes. Only the read-only backing PVC is captured, not the guest writes.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"deletionTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionTimeout is how long the deletion of the snapshot may wait for its content and volume snapshots before the pending ones are reported. Defaults to 5min",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"forceDelete": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ForceDelete removes the snapshot finalizer once its deletion has been stuck for too long, even if the content or its volume snapshots are still being deleted. Unlike the rest of the spec it can be set after creation.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"ttlAfterSuccess": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTLAfterSuccess is how long a succeeded snapshot is kept after its creation time before it is deleted, honoring its DeletionPolicy. Unset or zero keeps the snapshot until it is deleted explicitly.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"excludeUnsnapshottableVolumes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExcludeUnsnapshottableVolumes controls what happens to PVC volumes whose storage class has no VolumeSnapshotClass. When true, the default, they are skipped and listed as excluded volumes. When false, the snapshot fails with the NoVolumeSnapshotClass error reason.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is a free form note on why the snapshot was taken, for example before an upgrade. It is echoed into the status.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"snapshotType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SnapshotType selects what the snapshot captures. DefinitionOnly captures the VM definition without any volume backups and is ready as soon as its content is created. Defaults to Full",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"excludedVolumes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ExcludedVolumes lists the names of VM volumes which are not captured. They are listed as excluded volumes, and names the VM does not have are reported with the ExcludedVolumesMissing condition.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"includeMemoryDump": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IncludeMemoryDump dumps the guest memory of a running VM into a PVC before its volumes are frozen, and captures the dump with them. The dump is not loaded on restore, the restore reports where it is kept.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"hooks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hooks are commands run inside the guest through the guest agent around the filesystem freeze of a running VM. They are passed to the freeze and unfreeze of the VM, so they require a user allowed to freeze it.",
+							Ref:         ref("kubevirt.io/api/snapshot/v1beta1.SnapshotHooks"),
+						},
+					},
 				},
 				Required: []string{"source"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.TypedLocalObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+			"k8s.io/api/core/v1.TypedLocalObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kubevirt.io/api/snapshot/v1beta1.SnapshotHooks"},
 	}
 }
 
@@ -35155,7 +35786,8 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineSnapshotStatus(ref com
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "SourceIndications describe the state of the source VM when the snapshot was taken. When both are set they take precedence over the deprecated Indications, which are kept in sync with them.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
@@ -35166,11 +35798,38 @@ func schema_kubevirtio_api_snapshot_v1beta1_VirtualMachineSnapshotStatus(ref com
 							},
 						},
 					},
+					"consistencyLevel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConsistencyLevel classifies the guest data consistency of the snapshot, derived from the source indications once it succeeded",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"snapshotVolumes": {
 						SchemaProps: spec.SchemaProps{
 							Ref: ref("kubevirt.io/api/snapshot/v1beta1.SnapshotVolumesLists"),
 						},
 					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description is the description given in the spec",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retryCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryCount is the number of times the controller retried a failed step of the snapshot, such as freezing the guest",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"nextRetryTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NextRetryTime is when the controller retries the failed step next",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 				},
 			},
 		},
@@ -35205,12 +35864,25 @@ func schema_kubevirtio_api_snapshot_v1beta1_VolumeBackup(ref common.ReferenceCal
 							Format: "",
 						},
 					},
+					"hotplug": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hotplug indicates the volume was hotplugged to the running VMI and is not part of the VM spec template",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"disk": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disk is the disk definition of a hotplugged volume, so that the restored volume is hotplugged with the same bus and settings",
+							Ref:         ref("kubevirt.io/api/core/v1.Disk"),
+						},
+					},
 				},
 				Required: []string{"volumeName", "persistentVolumeClaim"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/api/snapshot/v1beta1.PersistentVolumeClaim"},
+			"kubevirt.io/api/core/v1.Disk", "kubevirt.io/api/snapshot/v1beta1.PersistentVolumeClaim"},
 	}
 }
 
@@ -35304,6 +35976,41 @@ func schema_kubevirtio_api_snapshot_v1beta1_VolumeRestoreOverride(ref common.Ref
 							},
 						},
 					},
+					"provisioning": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Provisioning hints whether the restored volume should be thin or thick provisioned. It is passed to the provisioner as a PVC annotation and is only honored by storage classes which declare support for it",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"storageClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StorageClassName is the storage class the volume is restored to, instead of the storage class of the snapshotted volume. The restore fails if the storage class does not exist",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"accessModes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "AccessModes replaces the access modes of the snapshotted volume",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+										Enum:    []interface{}{"ReadOnlyMany", "ReadWriteMany", "ReadWriteOnce", "ReadWriteOncePod"},
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -35340,12 +36047,18 @@ func schema_kubevirtio_api_snapshot_v1beta1_VolumeSnapshotStatus(ref common.Refe
 							Ref: ref("kubevirt.io/api/snapshot/v1beta1.Error"),
 						},
 					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Duration is how long the volume snapshot took to become ready to use after it was created",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 				},
 				Required: []string{"volumeSnapshotName"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubevirt.io/api/snapshot/v1beta1.Error"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubevirt.io/api/snapshot/v1beta1.Error"},
 	}
 }
 
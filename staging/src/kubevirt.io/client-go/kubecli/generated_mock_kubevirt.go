@@ -1536,17 +1536,22 @@ func (mr *MockVirtualMachineInstanceInterfaceMockRecorder) FilesystemList(ctx, n
 }
 
 // Freeze mocks base method.
-func (m *MockVirtualMachineInstanceInterface) Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration) error {
+func (m *MockVirtualMachineInstanceInterface) Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration, hooks ...v122.FreezeHook) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Freeze", ctx, name, unfreezeTimeout)
+	varargs := []any{ctx, name, unfreezeTimeout}
+	for _, a := range hooks {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Freeze", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Freeze indicates an expected call of Freeze.
-func (mr *MockVirtualMachineInstanceInterfaceMockRecorder) Freeze(ctx, name, unfreezeTimeout any) *gomock.Call {
+func (mr *MockVirtualMachineInstanceInterfaceMockRecorder) Freeze(ctx, name, unfreezeTimeout any, hooks ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Freeze", reflect.TypeOf((*MockVirtualMachineInstanceInterface)(nil).Freeze), ctx, name, unfreezeTimeout)
+	varargs := append([]any{ctx, name, unfreezeTimeout}, hooks...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Freeze", reflect.TypeOf((*MockVirtualMachineInstanceInterface)(nil).Freeze), varargs...)
 }
 
 // Get mocks base method.
@@ -1804,17 +1809,22 @@ func (mr *MockVirtualMachineInstanceInterfaceMockRecorder) USBRedir(vmiName any)
 }
 
 // Unfreeze mocks base method.
-func (m *MockVirtualMachineInstanceInterface) Unfreeze(ctx context.Context, name string) error {
+func (m *MockVirtualMachineInstanceInterface) Unfreeze(ctx context.Context, name string, hooks ...v122.FreezeHook) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Unfreeze", ctx, name)
+	varargs := []any{ctx, name}
+	for _, a := range hooks {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Unfreeze", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Unfreeze indicates an expected call of Unfreeze.
-func (mr *MockVirtualMachineInstanceInterfaceMockRecorder) Unfreeze(ctx, name any) *gomock.Call {
+func (mr *MockVirtualMachineInstanceInterfaceMockRecorder) Unfreeze(ctx, name any, hooks ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfreeze", reflect.TypeOf((*MockVirtualMachineInstanceInterface)(nil).Unfreeze), ctx, name)
+	varargs := append([]any{ctx, name}, hooks...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfreeze", reflect.TypeOf((*MockVirtualMachineInstanceInterface)(nil).Unfreeze), varargs...)
 }
 
 // Unpause mocks base method.
@@ -346,6 +346,34 @@ var _ = Describe("Kubevirt VirtualMachineInstance Client", func() {
 		Entry("with proxied server URL", proxyPath),
 	)
 
+	DescribeTable("should pass the freeze hooks to the freeze and unfreeze requests", func(proxyPath string) {
+		client, err := GetKubevirtClientFromFlags(server.URL()+proxyPath, "")
+		Expect(err).ToNot(HaveOccurred())
+
+		hook := v1.FreezeHook{Name: "flush", Command: "/usr/bin/sync", TimeoutSeconds: 10}
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", path.Join(proxyPath, subVMIPath, "freeze")),
+				ghttp.VerifyBody([]byte(`{"unfreezeTimeout":"1m0s","hooks":[{"name":"flush","command":"/usr/bin/sync","timeoutSeconds":10}]}`)),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", path.Join(proxyPath, subVMIPath, "unfreeze")),
+				ghttp.VerifyBody([]byte(`{"hooks":[{"name":"flush","command":"/usr/bin/sync","timeoutSeconds":10}]}`)),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+			),
+		)
+		err = client.VirtualMachineInstance(k8sv1.NamespaceDefault).Freeze(context.Background(), "testvm", time.Minute, hook)
+		Expect(err).ToNot(HaveOccurred())
+		err = client.VirtualMachineInstance(k8sv1.NamespaceDefault).Unfreeze(context.Background(), "testvm", hook)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	},
+		Entry("with regular server URL", ""),
+		Entry("with proxied server URL", proxyPath),
+	)
+
 	DescribeTable("should reset a VirtualMachineInstance", func(proxyPath string) {
 		client, err := GetKubevirtClientFromFlags(server.URL()+proxyPath, "")
 		Expect(err).ToNot(HaveOccurred())
@@ -65,14 +65,14 @@ func (c *fakeVirtualMachineInstances) Unpause(ctx context.Context, name string,
 	return err
 }
 
-func (c *fakeVirtualMachineInstances) Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration) error {
+func (c *fakeVirtualMachineInstances) Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration, hooks ...v1.FreezeHook) error {
 	_, err := c.Fake.
 		Invokes(fake2.NewPutSubresourceAction(c.Resource(), c.Namespace(), "freeze", name, struct{}{}), nil)
 
 	return err
 }
 
-func (c *fakeVirtualMachineInstances) Unfreeze(ctx context.Context, name string) error {
+func (c *fakeVirtualMachineInstances) Unfreeze(ctx context.Context, name string, hooks ...v1.FreezeHook) error {
 	_, err := c.Fake.
 		Invokes(fake2.NewPutSubresourceAction(c.Resource(), c.Namespace(), "unfreeze", name, struct{}{}), nil)
 
@@ -47,8 +47,8 @@ type VirtualMachineInstanceExpansion interface {
 	Backup(ctx context.Context, name string, backupOptions *backupv1.BackupOptions) error
 	Pause(ctx context.Context, name string, pauseOptions *v1.PauseOptions) error
 	Unpause(ctx context.Context, name string, unpauseOptions *v1.UnpauseOptions) error
-	Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration) error
-	Unfreeze(ctx context.Context, name string) error
+	Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration, hooks ...v1.FreezeHook) error
+	Unfreeze(ctx context.Context, name string, hooks ...v1.FreezeHook) error
 	Reset(ctx context.Context, name string) error
 	SoftReboot(ctx context.Context, name string) error
 	GuestOsInfo(ctx context.Context, name string) (v1.VirtualMachineInstanceGuestAgentInfo, error)
@@ -163,12 +163,13 @@ func (c *virtualMachineInstances) Unpause(ctx context.Context, name string, unpa
 		Error()
 }
 
-func (c *virtualMachineInstances) Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration) error {
+func (c *virtualMachineInstances) Freeze(ctx context.Context, name string, unfreezeTimeout time.Duration, hooks ...v1.FreezeHook) error {
 	log.Log.Infof("Freeze VMI %s", name)
 	freezeUnfreezeTimeout := &v1.FreezeUnfreezeTimeout{
 		UnfreezeTimeout: &metav1.Duration{
 			Duration: unfreezeTimeout,
 		},
+		Hooks: hooks,
 	}
 
 	body, err := json.Marshal(freezeUnfreezeTimeout)
@@ -187,14 +188,20 @@ func (c *virtualMachineInstances) Freeze(ctx context.Context, name string, unfre
 		Error()
 }
 
-func (c *virtualMachineInstances) Unfreeze(ctx context.Context, name string) error {
+func (c *virtualMachineInstances) Unfreeze(ctx context.Context, name string, hooks ...v1.FreezeHook) error {
 	log.Log.Infof("Unfreeze VMI %s", name)
+	body, err := json.Marshal(&v1.UnfreezeOptions{Hooks: hooks})
+	if err != nil {
+		return err
+	}
+
 	return c.GetClient().Put().
 		AbsPath(fmt.Sprintf(vmiSubresourceURL, v1.ApiStorageVersion)).
 		Namespace(c.GetNamespace()).
 		Resource("virtualmachineinstances").
 		Name(name).
 		SubResource("unfreeze").
+		Body(body).
 		Do(ctx).
 		Error()
 }